@@ -277,6 +277,23 @@ func (item WowheadItemResponse) GetUnique() bool {
 	return uniqueRegex.MatchString(item.Tooltip)
 }
 
+// Tooltip text for effects which can't be described by stats alone.
+var effectPatterns = []*regexp.Regexp{
+	regexp.MustCompile("Use: "),
+	regexp.MustCompile("Chance on hit: "),
+	regexp.MustCompile("Equip: (<a [^>]*>)?[^<]*[Cc]hance"),
+}
+
+func (item WowheadItemResponse) HasEffect() bool {
+	tooltip := item.TooltipWithoutSetBonus()
+	for _, pattern := range effectPatterns {
+		if pattern.MatchString(tooltip) {
+			return true
+		}
+	}
+	return false
+}
+
 var itemTypePatterns = map[proto.ItemType]*regexp.Regexp{
 	proto.ItemType_ItemTypeHead:     regexp.MustCompile("<td>Head</td>"),
 	proto.ItemType_ItemTypeNeck:     regexp.MustCompile("<td>Neck</td>"),
@@ -223,6 +223,10 @@ func itemToGoString(itemData ItemData) string {
 
 	itemStr += fmt.Sprintf("Ilvl:%d, ", itemData.Response.GetItemLevel())
 
+	if itemData.Response.HasEffect() {
+		itemStr += "HasEffect:true, "
+	}
+
 	if itemData.QualityModifier != 0 {
 		itemStr += fmt.Sprintf("QualityModifier:%0.03f, ", itemData.QualityModifier)
 	}
@@ -0,0 +1,309 @@
+// Command line runner for sim requests, for running sims in batch from scripts.
+//
+// Reads a RaidSimRequest or StatWeightsRequest from a file, or from every file
+// in a directory, runs it and writes the result. Requests can be in protojson
+// or binary proto format; the format is detected from the file contents.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wowsims/tbc/sim"
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func init() {
+	sim.RegisterAll()
+}
+
+const (
+	requestTypeRaidSim     = "raidsim"
+	requestTypeStatWeights = "statweights"
+
+	formatJSON    = "json"
+	formatBinary  = "binary"
+	formatSummary = "summary"
+)
+
+type options struct {
+	requestType string
+	format      string
+
+	// Overrides for the request's SimOptions, if set.
+	iterations  int
+	seed        int64
+	concurrency int
+}
+
+func main() {
+	var input = flag.String("input", "", "Request file, or a directory of request files, to run.")
+	var output = flag.String("output", "", "File to write the result to, or a directory when input is a directory. Defaults to stdout.")
+	var requestType = flag.String("type", requestTypeRaidSim, "Type of the request: raidsim or statweights.")
+	var format = flag.String("format", formatSummary, "Format of the result: json, binary or summary.")
+	var iterations = flag.Int("iterations", 0, "If set, overrides the number of iterations in the request.")
+	var seed = flag.Int64("seed", 0, "If set, overrides the random seed in the request.")
+	var concurrency = flag.Int("concurrency", runtime.NumCPU(), "Number of worker sims to split raid sim iterations across, for requests which don't set one.")
+
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := options{
+		requestType: *requestType,
+		format:      *format,
+		iterations:  *iterations,
+		seed:        *seed,
+		concurrency: *concurrency,
+	}
+	if err := opts.validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(*input, *output, opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (opts options) validate() error {
+	switch opts.requestType {
+	case requestTypeRaidSim, requestTypeStatWeights:
+	default:
+		return fmt.Errorf("unknown request type: %s", opts.requestType)
+	}
+
+	switch opts.format {
+	case formatJSON, formatBinary, formatSummary:
+	default:
+		return fmt.Errorf("unknown output format: %s", opts.format)
+	}
+	return nil
+}
+
+// Runs a single request file, or every file in a directory in name order.
+func run(input string, output string, opts options) error {
+	info, err := os.Stat(input)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return runFile(input, output, opts)
+	}
+
+	entries, err := ioutil.ReadDir(input)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	if output != "" {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		inputFile := filepath.Join(input, entry.Name())
+		outputFile := ""
+		if output != "" {
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			outputFile = filepath.Join(output, name+".result"+opts.fileExtension())
+		} else if opts.format == formatSummary {
+			fmt.Printf("== %s ==\n", inputFile)
+		}
+
+		if err := runFile(inputFile, outputFile, opts); err != nil {
+			return fmt.Errorf("%s: %w", inputFile, err)
+		}
+	}
+	return nil
+}
+
+func (opts options) fileExtension() string {
+	switch opts.format {
+	case formatJSON:
+		return ".json"
+	case formatBinary:
+		return ".binpb"
+	default:
+		return ".txt"
+	}
+}
+
+// Runs the request in inputFile, and writes the result to outputFile or to
+// stdout if outputFile is empty. Results with an error message are still
+// written, but also returned as an error.
+func runFile(inputFile string, outputFile string, opts options) error {
+	data, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	result, err := runRequest(data, opts)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeResult(out, result, opts.format); err != nil {
+		return err
+	}
+	if errorMsg := resultErrorMsg(result); errorMsg != "" {
+		return fmt.Errorf("sim failed: %s", errorMsg)
+	}
+	return nil
+}
+
+func resultErrorMsg(result googleProto.Message) string {
+	switch r := result.(type) {
+	case *proto.RaidSimResult:
+		return r.ErrorMsg
+	case *proto.StatWeightsResult:
+		return r.ErrorMsg
+	}
+	return ""
+}
+
+// Parses and runs a single request, returning its result.
+func runRequest(data []byte, opts options) (googleProto.Message, error) {
+	switch opts.requestType {
+	case requestTypeStatWeights:
+		request := &proto.StatWeightsRequest{}
+		if err := unmarshalRequest(data, request); err != nil {
+			return nil, err
+		}
+		request.SimOptions = opts.applySimOptions(request.SimOptions)
+		return core.StatWeights(request), nil
+	default:
+		request := &proto.RaidSimRequest{}
+		if err := unmarshalRequest(data, request); err != nil {
+			return nil, err
+		}
+		request.SimOptions = opts.applySimOptions(request.SimOptions)
+		if request.SimOptions.Concurrency == 0 {
+			request.SimOptions.Concurrency = int32(opts.concurrency)
+		}
+		return core.RunRaidSim(request), nil
+	}
+}
+
+// Requests starting with '{' are parsed as protojson, everything else as binary.
+func unmarshalRequest(data []byte, request googleProto.Message) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return protojson.Unmarshal(trimmed, request)
+	}
+	return googleProto.Unmarshal(data, request)
+}
+
+func (opts options) applySimOptions(simOptions *proto.SimOptions) *proto.SimOptions {
+	if simOptions == nil {
+		simOptions = &proto.SimOptions{}
+	}
+	if opts.iterations > 0 {
+		simOptions.Iterations = int32(opts.iterations)
+	}
+	if opts.seed != 0 {
+		simOptions.RandomSeed = opts.seed
+	}
+	return simOptions
+}
+
+func writeResult(out io.Writer, result googleProto.Message, format string) error {
+	switch format {
+	case formatJSON:
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(append(data, '\n'))
+		return err
+	case formatBinary:
+		data, err := googleProto.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		switch r := result.(type) {
+		case *proto.RaidSimResult:
+			return writeRaidSimSummary(out, r)
+		case *proto.StatWeightsResult:
+			return writeStatWeightsSummary(out, r)
+		}
+		return nil
+	}
+}
+
+func writeRaidSimSummary(out io.Writer, result *proto.RaidSimResult) error {
+	if result.ErrorMsg != "" {
+		_, err := fmt.Fprintf(out, "Error: %s\n", result.ErrorMsg)
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "Unit\tDPS\tStdev\tHPS\tTPS\tDTPS\t\n")
+	for _, party := range result.RaidMetrics.Parties {
+		for _, player := range party.Players {
+			if player.Name == "" {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%0.2f\t%0.2f\t%0.2f\t%0.2f\t%0.2f\t\n",
+				player.Name, player.Dps.Avg, player.Dps.Stdev, player.Hps.Avg, player.Threat.Avg, player.Dtps.Avg)
+		}
+	}
+	fmt.Fprintf(w, "Raid\t%0.2f\t%0.2f\t\t\t\t\n", result.RaidMetrics.Dps.Avg, result.RaidMetrics.Dps.Stdev)
+	if result.KillTime != nil {
+		fmt.Fprintf(w, "Kill Time\t%0.2f\t%0.2f\t\t\t\t\n", result.KillTime.Avg, result.KillTime.Stdev)
+	}
+	return w.Flush()
+}
+
+func writeStatWeightsSummary(out io.Writer, result *proto.StatWeightsResult) error {
+	if result.ErrorMsg != "" {
+		_, err := fmt.Fprintf(out, "Error: %s\n", result.ErrorMsg)
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "Stat\tDPS Weight\tStdev\tDPS EP\tTPS EP\tHPS EP\t\n")
+	for i := 0; i < int(stats.Len); i++ {
+		if i >= len(result.Dps.Weights) || result.Dps.Weights[i] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%0.3f\t%0.3f\t%0.3f\t%0.3f\t%0.3f\t\n",
+			stats.Stat(i).StatName(),
+			result.Dps.Weights[i], result.Dps.WeightsStdev[i],
+			result.Dps.EpValues[i], result.Tps.EpValues[i], result.Hps.EpValues[i])
+	}
+	return w.Flush()
+}
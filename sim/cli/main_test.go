@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+var testPlayer = &proto.Player{
+	Name:      "Elemental Shaman",
+	Race:      proto.Race_RaceTroll10,
+	Class:     proto.Class_ClassShaman,
+	Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{{Id: 29035}, {Id: 28762}}},
+	Consumes:  &proto.Consumes{},
+	Spec: &proto.Player_ElementalShaman{
+		ElementalShaman: &proto.ElementalShaman{
+			Rotation: &proto.ElementalShaman_Rotation{
+				Type: proto.ElementalShaman_Rotation_Adaptive,
+			},
+			Talents: &proto.ShamanTalents{},
+			Options: &proto.ElementalShaman_Options{},
+		},
+	},
+}
+
+func TestRunDirectory(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(testPlayer, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: core.MakeSingleTargetEncounter(0),
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+		},
+	}
+	jsonData, err := protojson.Marshal(rsr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binaryData, err := googleProto.Marshal(rsr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(inputDir, "a.json"), jsonData, 0644)
+	ioutil.WriteFile(filepath.Join(inputDir, "b.binpb"), binaryData, 0644)
+
+	opts := options{
+		requestType: requestTypeRaidSim,
+		format:      formatJSON,
+		iterations:  3,
+		seed:        7,
+		concurrency: 1,
+	}
+	if err := run(inputDir, outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var dps []float64
+	for _, name := range []string{"a.result.json", "b.result.json"} {
+		data, err := ioutil.ReadFile(filepath.Join(outputDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &proto.RaidSimResult{}
+		if err := protojson.Unmarshal(data, result); err != nil {
+			t.Fatal(err)
+		}
+		if result.RaidMetrics.Dps.Avg <= 0 {
+			t.Fatalf("Expected dps in %s", name)
+		}
+		dps = append(dps, result.RaidMetrics.Dps.Avg)
+	}
+
+	// Both formats hold the same request, so the results should match.
+	if dps[0] != dps[1] {
+		t.Fatalf("Expected json and binary requests to give the same result, got %0.3f vs %0.3f", dps[0], dps[1])
+	}
+}
+
+func TestSummary(t *testing.T) {
+	result := &proto.RaidSimResult{
+		RaidMetrics: &proto.RaidMetrics{
+			Dps: &proto.DistributionMetrics{Avg: 1234.5},
+			Parties: []*proto.PartyMetrics{
+				{
+					Players: []*proto.UnitMetrics{
+						{
+							Name:   "Elemental Shaman",
+							Dps:    &proto.DistributionMetrics{Avg: 1234.5},
+							Hps:    &proto.DistributionMetrics{},
+							Threat: &proto.DistributionMetrics{},
+							Dtps:   &proto.DistributionMetrics{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := writeResult(out, result, formatSummary); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"Elemental Shaman", "1234.50", "Raid"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("Expected summary to contain %s, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	result := &proto.RaidSimResult{ErrorMsg: "Invalid rotation"}
+
+	out := &bytes.Buffer{}
+	if err := writeResult(out, result, formatSummary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Invalid rotation") {
+		t.Fatalf("Expected summary to contain the error, got:\n%s", out.String())
+	}
+
+	inputDir := t.TempDir()
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(testPlayer, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: core.MakeSingleTargetEncounter(0),
+		SimOptions: &proto.SimOptions{
+			Iterations:            1,
+			IsTest:                true,
+			TargetDpsStderr:       1,
+			TargetDpsStderrPlayer: &proto.RaidTarget{TargetIndex: 5},
+		},
+	}
+	data, err := protojson.Marshal(rsr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputFile := filepath.Join(inputDir, "invalid.json")
+	ioutil.WriteFile(inputFile, data, 0644)
+
+	opts := options{
+		requestType: requestTypeRaidSim,
+		format:      formatSummary,
+		concurrency: 1,
+	}
+	if err := runFile(inputFile, filepath.Join(inputDir, "invalid.result.txt"), opts); err == nil {
+		t.Fatalf("Expected an error for a result with an error message")
+	}
+}
@@ -24,9 +24,10 @@ func ApplyShatteredSunPendantofAcumen(agent core.Agent) {
 
 	var aldorAura *core.Aura
 	var scryerSpell *core.Spell
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 34678})
 
 	if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionAldor {
-		aldorAura = character.NewTemporaryStatsAura("Light's Wrath", core.ActionID{SpellID: 45479}, stats.Stats{stats.SpellPower: 120}, time.Second*10)
+		aldorAura = character.NewTemporaryStatsProcAura("Light's Wrath", core.ActionID{SpellID: 45479}, stats.Stats{stats.SpellPower: 120}, time.Second*10, procMetrics)
 	} else if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionScryer {
 		scryerSpell = character.RegisterSpell(core.SpellConfig{
 			ActionID:    core.ActionID{SpellID: 45429},
@@ -63,6 +64,7 @@ func ApplyShatteredSunPendantofAcumen(agent core.Agent) {
 			if !icd.IsReady(sim) || sim.RandomFloat("pendant of acumen") > proc { // can't activate if on CD or didn't proc
 				return
 			}
+			procMetrics.AddProc()
 			icd.Use(sim)
 
 			if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionAldor {
@@ -76,7 +78,8 @@ func ApplyShatteredSunPendantofAcumen(agent core.Agent) {
 
 func ApplyRobeOfTheElderScribes(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Power of Arcanagos", core.ActionID{ItemID: 28602}, stats.Stats{stats.SpellPower: 130}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28602})
+	procAura := character.NewTemporaryStatsProcAura("Power of Arcanagos", core.ActionID{ItemID: 28602}, stats.Stats{stats.SpellPower: 130}, time.Second*10, procMetrics)
 
 	// Gives a chance when your harmful spells land to increase the damage of your spells and effects by up to 130 for 10 sec. (Proc chance: 20%, 50s cooldown)
 	icd := core.Cooldown{
@@ -102,6 +105,7 @@ func ApplyRobeOfTheElderScribes(agent core.Agent) {
 				return
 			}
 			icd.Use(sim)
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -109,7 +113,8 @@ func ApplyRobeOfTheElderScribes(agent core.Agent) {
 
 func ApplyEternalSage(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Band of the Eternal Sage Proc", core.ActionID{ItemID: 29305}, stats.Stats{stats.SpellPower: 95}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 29305})
+	procAura := character.NewTemporaryStatsProcAura("Band of the Eternal Sage Proc", core.ActionID{ItemID: 29305}, stats.Stats{stats.SpellPower: 95}, time.Second*10, procMetrics)
 
 	// Your offensive spells have a chance on hit to increase your spell damage by 95 for 10 secs.
 	icd := core.Cooldown{
@@ -135,6 +140,7 @@ func ApplyEternalSage(agent core.Agent) {
 				return
 			}
 			icd.Use(sim)
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -162,6 +168,7 @@ func ApplyTimbals(agent core.Agent) {
 		Duration: time.Second * 15,
 	}
 	const proc = 0.1
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 45055})
 
 	character.RegisterAura(core.Aura{
 		Label:    "Timbals",
@@ -173,6 +180,7 @@ func ApplyTimbals(agent core.Agent) {
 			if !icd.IsReady(sim) || sim.RandomFloat("timbals") > proc { // can't activate if on CD or didn't proc
 				return
 			}
+			procMetrics.AddProc()
 			icd.Use(sim)
 
 			timbalsSpell.Cast(sim, spellEffect.Target)
@@ -25,7 +25,8 @@ var ItemSetManaEtched = core.ItemSet{
 		},
 		4: func(agent core.Agent) {
 			character := agent.GetCharacter()
-			procAura := character.NewTemporaryStatsAura("Mana-Etched Insight Proc", core.ActionID{SpellID: 37619}, stats.Stats{stats.SpellPower: 110}, time.Second*15)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 37619})
+			procAura := character.NewTemporaryStatsProcAura("Mana-Etched Insight Proc", core.ActionID{SpellID: 37619}, stats.Stats{stats.SpellPower: 110}, time.Second*15, procMetrics)
 
 			character.RegisterAura(core.Aura{
 				Label:    "Mana-Etched Insight",
@@ -37,6 +38,7 @@ var ItemSetManaEtched = core.ItemSet{
 					if sim.RandomFloat("Mana-Etched Insight") > 0.02 {
 						return
 					}
+					procMetrics.AddProc()
 					procAura.Activate(sim)
 				},
 			})
@@ -58,7 +60,8 @@ var ItemSetSpellstrike = core.ItemSet{
 	Bonuses: map[int32]core.ApplyEffect{
 		2: func(agent core.Agent) {
 			character := agent.GetCharacter()
-			procAura := character.NewTemporaryStatsAura("Spellstrike Proc", core.ActionID{SpellID: 32106}, stats.Stats{stats.SpellPower: 92}, time.Second*10)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 32106})
+			procAura := character.NewTemporaryStatsProcAura("Spellstrike Proc", core.ActionID{SpellID: 32106}, stats.Stats{stats.SpellPower: 92}, time.Second*10, procMetrics)
 
 			character.RegisterAura(core.Aura{
 				Label:    "Spellstrike",
@@ -70,6 +73,7 @@ var ItemSetSpellstrike = core.ItemSet{
 					if sim.RandomFloat("spellstrike") > 0.05 {
 						return
 					}
+					procMetrics.AddProc()
 					procAura.Activate(sim)
 				},
 			})
@@ -11,10 +11,8 @@ import (
 func init() {
 	// Proc effects. Keep these in order by item ID.
 	core.AddItemEffect(23207, ApplyMarkOfTheChampionCaster)
-	core.AddItemEffect(27683, ApplyQuagmirransEye)
 	core.AddItemEffect(28418, ApplyShiffarsNexusHorn)
 	core.AddItemEffect(28789, ApplyEyeOfMagtheridon)
-	core.AddItemEffect(30626, ApplySextantOfUnstableCurrents)
 	core.AddItemEffect(31856, ApplyDarkmoonCardCrusade)
 
 	// Offensive trinkets. Keep these in order by item ID.
@@ -43,34 +41,10 @@ func ApplyMarkOfTheChampionCaster(agent core.Agent) {
 	}
 }
 
-func ApplyQuagmirransEye(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Fungal Frenzy", core.ActionID{ItemID: 27683}, stats.Stats{stats.SpellHaste: 320}, time.Second*6)
-
-	icd := core.Cooldown{
-		Timer:    character.NewTimer(),
-		Duration: time.Second * 45,
-	}
-
-	character.RegisterAura(core.Aura{
-		Label:    "Quagmirran's Eye",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
-			if !icd.IsReady(sim) || sim.RandomFloat("Quagmirran's Eye") > 0.1 {
-				return
-			}
-			icd.Use(sim)
-			procAura.Activate(sim)
-		},
-	})
-}
-
 func ApplyShiffarsNexusHorn(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Call of the Nexus", core.ActionID{ItemID: 28418}, stats.Stats{stats.SpellPower: 225}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28418})
+	procAura := character.NewTemporaryStatsProcAura("Call of the Nexus", core.ActionID{ItemID: 28418}, stats.Stats{stats.SpellPower: 225}, time.Second*10, procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
@@ -94,6 +68,7 @@ func ApplyShiffarsNexusHorn(agent core.Agent) {
 				return
 			}
 			icd.Use(sim)
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -101,7 +76,8 @@ func ApplyShiffarsNexusHorn(agent core.Agent) {
 
 func ApplyEyeOfMagtheridon(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Recurring Power", core.ActionID{ItemID: 28789}, stats.Stats{stats.SpellPower: 170}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28789})
+	procAura := character.NewTemporaryStatsProcAura("Recurring Power", core.ActionID{ItemID: 28789}, stats.Stats{stats.SpellPower: 170}, time.Second*10, procMetrics)
 
 	character.RegisterAura(core.Aura{
 		Label:    "Eye of Magtheridon",
@@ -116,6 +92,7 @@ func ApplyEyeOfMagtheridon(agent core.Agent) {
 			if !spellEffect.Outcome.Matches(core.OutcomeMiss) {
 				return
 			}
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 		OnPeriodicDamageDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
@@ -125,37 +102,7 @@ func ApplyEyeOfMagtheridon(agent core.Agent) {
 			if !spellEffect.Outcome.Matches(core.OutcomeMiss) {
 				return
 			}
-			procAura.Activate(sim)
-		},
-	})
-}
-
-func ApplySextantOfUnstableCurrents(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Unstable Currents", core.ActionID{ItemID: 30626}, stats.Stats{stats.SpellPower: 190}, time.Second*15)
-
-	icd := core.Cooldown{
-		Timer:    character.NewTimer(),
-		Duration: time.Second * 45,
-	}
-
-	character.RegisterAura(core.Aura{
-		Label:    "Sextant of Unstable Currents",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.ProcMask.Matches(core.ProcMaskSpellDamage) {
-				return
-			}
-			if !spellEffect.Outcome.Matches(core.OutcomeCrit) || !icd.IsReady(sim) {
-				return
-			}
-			if sim.RandomFloat("Sextant of Unstable Currents") > 0.2 {
-				return
-			}
-			icd.Use(sim)
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -165,32 +112,40 @@ func ApplyDarkmoonCardCrusade(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	var apBonusPerStack stats.Stats
-	apAura := character.RegisterAura(core.Aura{
+	apBonus := stats.Stats{stats.AttackPower: 6, stats.RangedAttackPower: 6}
+	apAuraConfig := core.Aura{
 		Label:     "DMC Crusade AP",
 		ActionID:  core.ActionID{ItemID: 31856, Tag: 1},
 		Duration:  time.Second * 10,
 		MaxStacks: 20,
 		OnInit: func(aura *core.Aura, sim *core.Simulation) {
-			apBonusPerStack = character.ApplyStatDependencies(stats.Stats{stats.AttackPower: 6, stats.RangedAttackPower: 6})
+			apBonusPerStack = character.ApplyStatDependencies(apBonus)
 		},
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatsDynamic(sim, apBonusPerStack.Multiply(float64(newStacks-oldStacks)))
 		},
-	})
+	}
+	apProcMetrics := character.Metrics.GetOrRegisterProcMetrics(apAuraConfig.ActionID)
+	apProcMetrics.TrackAura(&apAuraConfig, apBonus)
+	apAura := character.RegisterAura(apAuraConfig)
 
 	var spBonusPerStack stats.Stats
-	spAura := character.RegisterAura(core.Aura{
+	spBonus := stats.Stats{stats.SpellPower: 8}
+	spAuraConfig := core.Aura{
 		Label:     "DMC Crusade SP",
 		ActionID:  core.ActionID{ItemID: 31856, Tag: 2},
 		Duration:  time.Second * 10,
 		MaxStacks: 10,
 		OnInit: func(aura *core.Aura, sim *core.Simulation) {
-			spBonusPerStack = character.ApplyStatDependencies(stats.Stats{stats.SpellPower: 8})
+			spBonusPerStack = character.ApplyStatDependencies(spBonus)
 		},
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatsDynamic(sim, spBonusPerStack.Multiply(float64(newStacks-oldStacks)))
 		},
-	})
+	}
+	spProcMetrics := character.Metrics.GetOrRegisterProcMetrics(spAuraConfig.ActionID)
+	spProcMetrics.TrackAura(&spAuraConfig, spBonus)
+	spAura := character.RegisterAura(spAuraConfig)
 
 	character.RegisterAura(core.Aura{
 		Label:    "DMC Crusade",
@@ -200,6 +155,7 @@ func ApplyDarkmoonCardCrusade(agent core.Agent) {
 		},
 		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
 			if spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged) {
+				apProcMetrics.AddProc()
 				apAura.Activate(sim)
 				apAura.AddStack(sim)
 				apAura.Refresh(sim)
@@ -207,6 +163,7 @@ func ApplyDarkmoonCardCrusade(agent core.Agent) {
 				if !spellEffect.Landed() {
 					return
 				}
+				spProcMetrics.AddProc()
 				spAura.Activate(sim)
 				spAura.AddStack(sim)
 				spAura.Refresh(sim)
@@ -63,8 +63,10 @@ func ApplyCrusader(agent core.Agent) {
 
 	// -4 str per level over 60
 	const strBonus = 100.0 - 4.0*float64(core.CharacterLevel-60)
-	mhAura := character.NewTemporaryStatsAura("Crusader Enchant MH", core.ActionID{SpellID: 20007, Tag: 1}, stats.Stats{stats.Strength: strBonus}, time.Second*15)
-	ohAura := character.NewTemporaryStatsAura("Crusader Enchant OH", core.ActionID{SpellID: 20007, Tag: 2}, stats.Stats{stats.Strength: strBonus}, time.Second*15)
+	mhProcMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 20007, Tag: 1})
+	ohProcMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 20007, Tag: 2})
+	mhAura := character.NewTemporaryStatsProcAura("Crusader Enchant MH", core.ActionID{SpellID: 20007, Tag: 1}, stats.Stats{stats.Strength: strBonus}, time.Second*15, mhProcMetrics)
+	ohAura := character.NewTemporaryStatsProcAura("Crusader Enchant OH", core.ActionID{SpellID: 20007, Tag: 2}, stats.Stats{stats.Strength: strBonus}, time.Second*15, ohProcMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Crusader Enchant",
@@ -80,8 +82,10 @@ func ApplyCrusader(agent core.Agent) {
 			isMH := spellEffect.IsMH()
 			if ppmm.Proc(sim, isMH, false, "Crusader") {
 				if isMH {
+					mhProcMetrics.AddProc()
 					mhAura.Activate(sim)
 				} else {
+					ohProcMetrics.AddProc()
 					ohAura.Activate(sim)
 				}
 			}
@@ -98,8 +102,10 @@ func ApplyRingStriking(agent core.Agent) {
 	agent.GetCharacter().PseudoStats.BonusDamage += 2
 }
 
-func newLightningSpeedAura(character *core.Character, auraLabel string, actionID core.ActionID) *core.Aura {
-	return character.NewTemporaryStatsAuraWrapped(auraLabel, actionID, stats.Stats{stats.Agility: 120}, time.Second*15, func(aura *core.Aura) {
+func newLightningSpeedAura(character *core.Character, auraLabel string, actionID core.ActionID, procMetrics *core.ProcMetrics) *core.Aura {
+	bonus := stats.Stats{stats.Agility: 120}
+	return character.NewTemporaryStatsAuraWrapped(auraLabel, actionID, bonus, time.Second*15, func(aura *core.Aura) {
+		procMetrics.TrackAura(aura, bonus)
 		oldOnGain := aura.OnGain
 		oldOnExpire := aura.OnExpire
 		aura.OnGain = func(aura *core.Aura, sim *core.Simulation) {
@@ -131,8 +137,10 @@ func ApplyMongoose(agent core.Agent) {
 		ppmm.SetProcChance(false, 0)
 	}
 
-	mhAura := newLightningSpeedAura(character, "Lightning Speed MH", core.ActionID{SpellID: 28093, Tag: 1})
-	ohAura := newLightningSpeedAura(character, "Lightning Speed OH", core.ActionID{SpellID: 28093, Tag: 2})
+	mhProcMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 28093, Tag: 1})
+	ohProcMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 28093, Tag: 2})
+	mhAura := newLightningSpeedAura(character, "Lightning Speed MH", core.ActionID{SpellID: 28093, Tag: 1}, mhProcMetrics)
+	ohAura := newLightningSpeedAura(character, "Lightning Speed OH", core.ActionID{SpellID: 28093, Tag: 2}, ohProcMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Mongoose Enchant",
@@ -148,8 +156,10 @@ func ApplyMongoose(agent core.Agent) {
 			isMH := spellEffect.IsMH()
 			if ppmm.Proc(sim, isMH, false, "mongoose") {
 				if isMH {
+					mhProcMetrics.AddProc()
 					mhAura.Activate(sim)
 				} else {
+					ohProcMetrics.AddProc()
 					ohAura.Activate(sim)
 				}
 			}
@@ -192,7 +202,8 @@ func ApplyExecutioner(agent core.Agent) {
 		ppmm.SetProcChance(false, 0)
 	}
 
-	procAura := character.NewTemporaryStatsAura("Executioner Proc", core.ActionID{SpellID: 42976}, stats.Stats{stats.ArmorPenetration: 840}, time.Second*15)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 42976})
+	procAura := character.NewTemporaryStatsProcAura("Executioner Proc", core.ActionID{SpellID: 42976}, stats.Stats{stats.ArmorPenetration: 840}, time.Second*15, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Executioner",
@@ -206,6 +217,7 @@ func ApplyExecutioner(agent core.Agent) {
 			}
 
 			if ppmm.Proc(sim, spellEffect.IsMH(), false, "Executioner") {
+				procMetrics.AddProc()
 				procAura.Activate(sim)
 			}
 		},
@@ -241,15 +253,18 @@ func ApplyDeathfrost(agent core.Agent) {
 		}),
 	})
 
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+
 	if mh {
-		applyDeathfrostForWeapon(character, procSpell, true)
+		applyDeathfrostForWeapon(character, procSpell, procMetrics, true)
 	}
 	if oh {
-		applyDeathfrostForWeapon(character, procSpell, false)
+		applyDeathfrostForWeapon(character, procSpell, procMetrics, false)
 	}
 }
-func applyDeathfrostForWeapon(character *core.Character, procSpell *core.Spell, isMH bool) {
+func applyDeathfrostForWeapon(character *core.Character, procSpell *core.Spell, procMetrics *core.ProcMetrics, isMH bool) {
 	ppmm := character.AutoAttacks.NewPPMManager(2.15)
+	ppmm.SetMetrics(procMetrics)
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
 		Duration: time.Second * 25,
@@ -282,6 +297,7 @@ func applyDeathfrostForWeapon(character *core.Character, procSpell *core.Spell,
 				if !icd.IsReady(sim) || sim.RandomFloat("Deathfrost") > 0.5 {
 					return
 				}
+				procMetrics.AddProc()
 				icd.Use(sim)
 				procSpell.Cast(sim, spellEffect.Target)
 			}
@@ -0,0 +1,328 @@
+package common
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Effects for items which fit one of the shapes in ItemEffectConfig, so they
+// don't need their own ApplyEffect function.
+//
+//go:embed item_effects.json
+var itemEffectsJSON []byte
+
+func init() {
+	configs, err := ParseItemEffectConfigs(itemEffectsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid item_effects.json: %s", err))
+	}
+	for _, config := range configs.Effects {
+		AddItemEffectConfig(config)
+	}
+}
+
+// Parses and validates a protojson ItemEffectConfigs message.
+func ParseItemEffectConfigs(data []byte) (*proto.ItemEffectConfigs, error) {
+	configs := &proto.ItemEffectConfigs{}
+	if err := protojson.Unmarshal(data, configs); err != nil {
+		return nil, err
+	}
+	for _, config := range configs.Effects {
+		if err := ValidateItemEffectConfig(config); err != nil {
+			return nil, err
+		}
+	}
+	return configs, nil
+}
+
+func ValidateItemEffectConfig(config *proto.ItemEffectConfig) error {
+	if config.ItemId == 0 {
+		return fmt.Errorf("Item effect %q has no item id", config.Name)
+	}
+	if config.Name == "" {
+		return fmt.Errorf("Item effect for item %d has no name", config.ItemId)
+	}
+
+	switch effect := config.Effect.(type) {
+	case *proto.ItemEffectConfig_OnUseStats:
+		if effect.OnUseStats.DurationSeconds <= 0 || effect.OnUseStats.CooldownSeconds <= 0 {
+			return fmt.Errorf("On use effect %s needs a duration and cooldown", config.Name)
+		}
+	case *proto.ItemEffectConfig_StatProc:
+		if effect.StatProc.DurationSeconds <= 0 {
+			return fmt.Errorf("Stat proc %s needs a duration", config.Name)
+		}
+		return validateProcConfig(config.Name, effect.StatProc.Proc)
+	case *proto.ItemEffectConfig_DamageProc:
+		if effect.DamageProc.MinDamage > effect.DamageProc.MaxDamage {
+			return fmt.Errorf("Damage proc %s has min damage above max damage", config.Name)
+		}
+		return validateProcConfig(config.Name, effect.DamageProc.Proc)
+	default:
+		return fmt.Errorf("Item effect %s has no effect", config.Name)
+	}
+	return nil
+}
+
+func validateProcConfig(name string, proc *proto.ProcConfig) error {
+	if proc == nil || proc.Trigger == proto.ProcTrigger_ProcTriggerUnknown {
+		return fmt.Errorf("Proc %s has no trigger", name)
+	}
+	if (proc.ProcChance > 0) == (proc.Ppm > 0) {
+		return fmt.Errorf("Proc %s needs exactly one of proc_chance or ppm", name)
+	}
+	if proc.Ppm > 0 && !isMeleeOrRangedTrigger(proc.Trigger) {
+		return fmt.Errorf("Proc %s can only use ppm with a melee or ranged trigger", name)
+	}
+	return nil
+}
+
+func isMeleeOrRangedTrigger(trigger proto.ProcTrigger) bool {
+	return trigger == proto.ProcTrigger_ProcTriggerMeleeOrRangedHit ||
+		trigger == proto.ProcTrigger_ProcTriggerMeleeOrRangedCrit ||
+		trigger == proto.ProcTrigger_ProcTriggerMeleeHit
+}
+
+// Registers the effect described by config for its item. Panics if the config
+// is invalid.
+func AddItemEffectConfig(config *proto.ItemEffectConfig) {
+	if err := ValidateItemEffectConfig(config); err != nil {
+		panic(err)
+	}
+
+	switch effect := config.Effect.(type) {
+	case *proto.ItemEffectConfig_OnUseStats:
+		addOnUseStatsEffect(config.ItemId, effect.OnUseStats)
+	case *proto.ItemEffectConfig_StatProc:
+		core.AddItemEffect(config.ItemId, func(agent core.Agent) {
+			applyStatProcEffect(agent.GetCharacter(), config, effect.StatProc)
+		})
+	case *proto.ItemEffectConfig_DamageProc:
+		core.AddItemEffect(config.ItemId, func(agent core.Agent) {
+			applyDamageProcEffect(agent.GetCharacter(), config, effect.DamageProc)
+		})
+	}
+}
+
+func statValuesToStats(statValues []*proto.StatValue) stats.Stats {
+	var bonus stats.Stats
+	for _, statValue := range statValues {
+		bonus[statValue.Stat] += statValue.Value
+	}
+	return bonus
+}
+
+func addOnUseStatsEffect(itemID int32, effect *proto.OnUseStatsEffect) {
+	bonus := statValuesToStats(effect.Stats)
+	duration := core.DurationFromSeconds(effect.DurationSeconds)
+	cooldown := core.DurationFromSeconds(effect.CooldownSeconds)
+
+	switch effect.SharedCooldown {
+	case proto.ItemEffectSharedCooldown_SharedCooldownOffensiveTrinket:
+		AddSimpleStatOffensiveTrinketEffect(itemID, bonus, duration, cooldown)
+	case proto.ItemEffectSharedCooldown_SharedCooldownDefensiveTrinket:
+		AddSimpleStatDefensiveTrinketEffect(itemID, bonus, duration, cooldown)
+	default:
+		AddSimpleStatItemEffect(itemID, bonus, duration, cooldown)
+	}
+}
+
+// Item effects are applied once per equipped copy of the item. A weapon
+// equipped in both hands still has a single proc, triggered by either hand, so
+// the proc is only registered for the first copy.
+func itemProcRegistered(character *core.Character, name string) bool {
+	return character.HasAura(name)
+}
+
+func applyStatProcEffect(character *core.Character, config *proto.ItemEffectConfig, effect *proto.StatProcEffect) {
+	if itemProcRegistered(character, config.Name) {
+		return
+	}
+	bonus := statValuesToStats(effect.Stats)
+	duration := core.DurationFromSeconds(effect.DurationSeconds)
+	procName := effect.ProcName
+	if procName == "" {
+		procName = config.Name + " Proc"
+	}
+	actionID := core.ActionID{ItemID: config.ItemId}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+
+	var onProc func(sim *core.Simulation, target *core.Unit)
+	if effect.MaxStacks > 1 {
+		var bonusPerStack stats.Stats
+		procAuraConfig := core.Aura{
+			Label:     procName,
+			ActionID:  actionID,
+			Duration:  duration,
+			MaxStacks: effect.MaxStacks,
+			OnInit: func(aura *core.Aura, sim *core.Simulation) {
+				bonusPerStack = character.ApplyStatDependencies(bonus)
+			},
+			OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+				character.AddStatsDynamic(sim, bonusPerStack.Multiply(float64(newStacks-oldStacks)))
+			},
+		}
+		procMetrics.TrackAura(&procAuraConfig, bonus)
+		procAura := character.RegisterAura(procAuraConfig)
+		onProc = func(sim *core.Simulation, _ *core.Unit) {
+			procAura.Activate(sim)
+			procAura.AddStack(sim)
+		}
+	} else {
+		procAura := character.NewTemporaryStatsAuraWrapped(procName, actionID, bonus, duration, func(config *core.Aura) {
+			procMetrics.TrackAura(config, bonus)
+		})
+		onProc = func(sim *core.Simulation, _ *core.Unit) {
+			procAura.Activate(sim)
+		}
+	}
+
+	registerProcTriggerAura(character, config.ItemId, config.Name, effect.Proc, procMetrics, onProc)
+}
+
+func applyDamageProcEffect(character *core.Character, config *proto.ItemEffectConfig, effect *proto.DamageProcEffect) {
+	if itemProcRegistered(character, config.Name) {
+		return
+	}
+	actionID := core.ActionID{ItemID: config.ItemId}
+	if effect.SpellId != 0 {
+		actionID = core.ActionID{SpellID: effect.SpellId}
+	}
+
+	var outcomeApplier core.OutcomeApplier
+	switch effect.Outcome {
+	case proto.DamageProcOutcome_DamageProcOutcomeMeleeSpecialHitAndCrit:
+		outcomeApplier = character.OutcomeFuncMeleeSpecialHitAndCrit(character.DefaultMeleeCritMultiplier())
+	case proto.DamageProcOutcome_DamageProcOutcomeAlwaysHit:
+		outcomeApplier = character.OutcomeFuncAlwaysHit()
+	default:
+		outcomeApplier = character.OutcomeFuncMagicHitAndCrit(character.DefaultSpellCritMultiplier())
+	}
+
+	var spellExtras core.SpellExtras
+	if effect.IgnoreResists {
+		spellExtras |= core.SpellExtrasIgnoreResists
+	}
+
+	procSpell := character.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolFromProto(effect.School),
+		SpellExtras: spellExtras,
+		ApplyEffects: core.ApplyEffectFuncDirectDamage(core.SpellEffect{
+			ProcMask:         core.ProcMaskEmpty,
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			BaseDamage:     core.BaseDamageConfigRoll(effect.MinDamage, effect.MaxDamage),
+			OutcomeApplier: outcomeApplier,
+		}),
+	})
+
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: config.ItemId})
+	registerProcTriggerAura(character, config.ItemId, config.Name, effect.Proc, procMetrics, func(sim *core.Simulation, target *core.Unit) {
+		procSpell.Cast(sim, target)
+	})
+}
+
+// Registers an always-active aura which calls onProc whenever the proc's
+// trigger, chance and internal cooldown allow it. Procs and triggers blocked
+// by the internal cooldown are recorded in procMetrics.
+func registerProcTriggerAura(character *core.Character, itemID int32, name string, proc *proto.ProcConfig, procMetrics *core.ProcMetrics, onProc func(sim *core.Simulation, target *core.Unit)) {
+	icd := core.Cooldown{
+		Timer:    character.NewTimer(),
+		Duration: core.DurationFromSeconds(proc.IcdSeconds),
+	}
+
+	var ppmm core.PPMManager
+	if proc.Ppm > 0 {
+		ppmm = character.AutoAttacks.NewPPMManager(proc.Ppm)
+	}
+
+	// Returns whether the proc happened, after the trigger has matched.
+	tryProc := func(sim *core.Simulation, spellEffect *core.SpellEffect) bool {
+		if proc.IcdSeconds > 0 && !icd.IsReady(sim) {
+			if proc.Ppm > 0 {
+				procMetrics.AddICDBlockedProc(ppmm.ProcChance(spellEffect.IsMH(), spellEffect.ProcMask.Matches(core.ProcMaskRanged)))
+			} else {
+				procMetrics.AddICDBlockedProc(proc.ProcChance)
+			}
+			return false
+		}
+		if proc.Ppm > 0 {
+			if !ppmm.Proc(sim, spellEffect.IsMH(), spellEffect.ProcMask.Matches(core.ProcMaskRanged), name) {
+				return false
+			}
+		} else {
+			if sim.RandomFloat(name) > proc.ProcChance {
+				return false
+			}
+		}
+		procMetrics.AddProc()
+		if proc.IcdSeconds > 0 {
+			icd.Use(sim)
+		}
+		return true
+	}
+
+	aura := core.Aura{
+		Label:    name,
+		Duration: core.NeverExpires,
+		OnReset: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Activate(sim)
+		},
+	}
+
+	if proc.Trigger == proto.ProcTrigger_ProcTriggerCastComplete {
+		aura.OnCastComplete = func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if tryProc(sim, nil) {
+				onProc(sim, character.CurrentTarget)
+			}
+		}
+	} else {
+		matches := procTriggerMatcher(character, itemID, proc.Trigger)
+		aura.OnSpellHitDealt = func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
+			if matches(spellEffect) && tryProc(sim, spellEffect) {
+				onProc(sim, spellEffect.Target)
+			}
+		}
+	}
+
+	character.RegisterAura(aura)
+}
+
+// Returns whether a hit matches a trigger.
+func procTriggerMatcher(character *core.Character, itemID int32, trigger proto.ProcTrigger) func(*core.SpellEffect) bool {
+	switch trigger {
+	case proto.ProcTrigger_ProcTriggerMeleeOrRangedHit:
+		return func(spellEffect *core.SpellEffect) bool {
+			return spellEffect.Landed() && spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged)
+		}
+	case proto.ProcTrigger_ProcTriggerMeleeOrRangedCrit:
+		return func(spellEffect *core.SpellEffect) bool {
+			return spellEffect.Outcome.Matches(core.OutcomeCrit) && spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged)
+		}
+	case proto.ProcTrigger_ProcTriggerMeleeHit:
+		procMask := core.ProcMaskMelee
+		if items.ByID[itemID].Type == proto.ItemType_ItemTypeWeapon {
+			procMask = core.GetMeleeProcMaskForHands(character.GetWeaponHands(itemID))
+		}
+		return func(spellEffect *core.SpellEffect) bool {
+			return spellEffect.Landed() && spellEffect.ProcMask.Matches(procMask)
+		}
+	case proto.ProcTrigger_ProcTriggerSpellHit:
+		return func(spellEffect *core.SpellEffect) bool {
+			return spellEffect.Landed() && spellEffect.ProcMask.Matches(core.ProcMaskSpellDamage)
+		}
+	case proto.ProcTrigger_ProcTriggerSpellCrit:
+		return func(spellEffect *core.SpellEffect) bool {
+			return spellEffect.Outcome.Matches(core.OutcomeCrit) && spellEffect.ProcMask.Matches(core.ProcMaskSpellDamage)
+		}
+	}
+	panic(fmt.Sprintf("Invalid proc trigger: %s", trigger))
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestParseItemEffectConfigs(t *testing.T) {
+	configs, err := ParseItemEffectConfigs(itemEffectsJSON)
+	if err != nil {
+		t.Fatalf("Failed to parse item_effects.json: %s", err)
+	}
+	if len(configs.Effects) == 0 {
+		t.Fatalf("Expected item effects in item_effects.json")
+	}
+
+	invalidConfigs := map[string]string{
+		"no item id":     `{"effects": [{"name": "X", "onUseStats": {"durationSeconds": 10, "cooldownSeconds": 60}}]}`,
+		"no effect":      `{"effects": [{"itemId": 1, "name": "X"}]}`,
+		"no trigger":     `{"effects": [{"itemId": 1, "name": "X", "statProc": {"proc": {"procChance": 0.1}, "durationSeconds": 10}}]}`,
+		"chance and ppm": `{"effects": [{"itemId": 1, "name": "X", "statProc": {"proc": {"trigger": "ProcTriggerMeleeHit", "procChance": 0.1, "ppm": 1}, "durationSeconds": 10}}]}`,
+		"spell ppm":      `{"effects": [{"itemId": 1, "name": "X", "statProc": {"proc": {"trigger": "ProcTriggerSpellHit", "ppm": 1}, "durationSeconds": 10}}]}`,
+		"damage range":   `{"effects": [{"itemId": 1, "name": "X", "damageProc": {"proc": {"trigger": "ProcTriggerMeleeHit", "procChance": 0.1}, "minDamage": 10, "maxDamage": 5}}]}`,
+		"unknown field":  `{"effects": [{"itemId": 1, "name": "X", "statProc": {"chance": 0.1}}]}`,
+	}
+	for label, data := range invalidConfigs {
+		if _, err := ParseItemEffectConfigs([]byte(data)); err == nil {
+			t.Errorf("Expected an error for config with %s", label)
+		}
+	}
+}
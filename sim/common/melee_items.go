@@ -20,9 +20,6 @@ func init() {
 	core.AddItemEffect(28429, ApplyLionheartChampion)
 	core.AddItemEffect(28430, ApplyLionheartExecutioner)
 	core.AddItemEffect(28437, ApplyDrakefistHammer)
-	core.AddItemEffect(28438, ApplyDragonmaw)
-	core.AddItemEffect(28439, ApplyDragonstrike)
-	core.AddItemEffect(28573, ApplyDespair)
 	core.AddItemEffect(28767, ApplyTheDecapitator)
 	core.AddItemEffect(28774, ApplyGlaiveOfThePit)
 	core.AddItemEffect(29297, ApplyBandOfTheEternalDefender)
@@ -52,9 +49,10 @@ func ApplyShatteredSunPendantofMight(agent core.Agent) {
 
 	var aldorAura *core.Aura
 	var scryerSpell *core.Spell
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 34679})
 
 	if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionAldor {
-		aldorAura = character.NewTemporaryStatsAura("Light's Strength", core.ActionID{SpellID: 45480}, stats.Stats{stats.AttackPower: 200}, time.Second*10)
+		aldorAura = character.NewTemporaryStatsProcAura("Light's Strength", core.ActionID{SpellID: 45480}, stats.Stats{stats.AttackPower: 200}, time.Second*10, procMetrics)
 	} else if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionScryer {
 		scryerSpell = character.RegisterSpell(core.SpellConfig{
 			ActionID:    core.ActionID{SpellID: 45428},
@@ -92,6 +90,7 @@ func ApplyShatteredSunPendantofMight(agent core.Agent) {
 			if !icd.IsReady(sim) || sim.RandomFloat("pendant of acumen") > proc { // can't activate if on CD or didn't proc
 				return
 			}
+			procMetrics.AddProc()
 			icd.Use(sim)
 
 			if character.ShattFaction == proto.ShattrathFaction_ShattrathFactionAldor {
@@ -182,7 +181,8 @@ func ApplyEmpyreanDemolisher(agent core.Agent) {
 	procMask := core.GetMeleeProcMaskForHands(mh, oh)
 	const procChance = 2.8 / 60.0
 
-	procAura := character.NewTemporaryStatsAura("Empyrean Demolisher Proc", core.ActionID{ItemID: 17112}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 17112})
+	procAura := character.NewTemporaryStatsProcAura("Empyrean Demolisher Proc", core.ActionID{ItemID: 17112}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Empyrean Demolisher",
@@ -198,6 +198,7 @@ func ApplyEmpyreanDemolisher(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -207,7 +208,8 @@ func ApplyKhoriumChampion(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 0.5 * 3.3 / 60.0
-	procAura := character.NewTemporaryStatsAura("Khorium Champion Proc", core.ActionID{ItemID: 23541}, stats.Stats{stats.Strength: 120}, time.Second*30)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 23541})
+	procAura := character.NewTemporaryStatsProcAura("Khorium Champion Proc", core.ActionID{ItemID: 23541}, stats.Stats{stats.Strength: 120}, time.Second*30, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Khorium Champion",
@@ -224,6 +226,7 @@ func ApplyKhoriumChampion(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -239,7 +242,8 @@ func ApplyBlackoutTruncheon(agent core.Agent) {
 	procMask := core.GetMeleeProcMaskForHands(mh, oh)
 
 	const procChance = 1.5 * 0.8 / 60.0
-	procAura := character.NewTemporaryStatsAura("Blackout Truncheon Proc", core.ActionID{ItemID: 27901}, stats.Stats{stats.MeleeHaste: 132}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 27901})
+	procAura := character.NewTemporaryStatsProcAura("Blackout Truncheon Proc", core.ActionID{ItemID: 27901}, stats.Stats{stats.MeleeHaste: 132}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Blackout Truncheon",
@@ -256,6 +260,7 @@ func ApplyBlackoutTruncheon(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -265,7 +270,8 @@ func ApplyLionheartChampion(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 3.6 / 60.0
-	procAura := character.NewTemporaryStatsAura("Lionheart Champion Proc", core.ActionID{ItemID: 28429}, stats.Stats{stats.Strength: 100}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28429})
+	procAura := character.NewTemporaryStatsProcAura("Lionheart Champion Proc", core.ActionID{ItemID: 28429}, stats.Stats{stats.Strength: 100}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Lionheart Champion",
@@ -282,6 +288,7 @@ func ApplyLionheartChampion(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -291,7 +298,8 @@ func ApplyLionheartExecutioner(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 3.6 / 60.0
-	procAura := character.NewTemporaryStatsAura("Lionheart Executioner Proc", core.ActionID{ItemID: 28430}, stats.Stats{stats.Strength: 100}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28430})
+	procAura := character.NewTemporaryStatsProcAura("Lionheart Executioner Proc", core.ActionID{ItemID: 28430}, stats.Stats{stats.Strength: 100}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Lionheart Executioner",
@@ -307,6 +315,7 @@ func ApplyLionheartExecutioner(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -318,7 +327,8 @@ func ApplyDrakefistHammer(agent core.Agent) {
 	procMask := core.GetMeleeProcMaskForHands(mh, oh)
 
 	const procChance = 2.7 / 60.0
-	procAura := character.NewTemporaryStatsAura("Drakefist Hammer Proc", core.ActionID{ItemID: 28437}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28437})
+	procAura := character.NewTemporaryStatsProcAura("Drakefist Hammer Proc", core.ActionID{ItemID: 28437}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Drakefist Hammer",
@@ -334,104 +344,12 @@ func ApplyDrakefistHammer(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
 }
 
-func ApplyDragonmaw(agent core.Agent) {
-	character := agent.GetCharacter()
-	mh, oh := character.GetWeaponHands(28438)
-	procMask := core.GetMeleeProcMaskForHands(mh, oh)
-
-	const procChance = 2.7 / 60.0
-	procAura := character.NewTemporaryStatsAura("Dragonmaw Proc", core.ActionID{ItemID: 28438}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10)
-
-	character.GetOrRegisterAura(core.Aura{
-		Label:    "Dragonmaw",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.Landed() || !spellEffect.ProcMask.Matches(procMask) {
-				return
-			}
-			if sim.RandomFloat("Dragonmaw") > procChance {
-				return
-			}
-
-			procAura.Activate(sim)
-		},
-	})
-}
-
-func ApplyDragonstrike(agent core.Agent) {
-	character := agent.GetCharacter()
-	mh, oh := character.GetWeaponHands(28439)
-	procMask := core.GetMeleeProcMaskForHands(mh, oh)
-
-	const procChance = 2.7 / 60.0
-	procAura := character.NewTemporaryStatsAura("Dragonstrike Proc", core.ActionID{ItemID: 28439}, stats.Stats{stats.MeleeHaste: 212}, time.Second*10)
-
-	character.GetOrRegisterAura(core.Aura{
-		Label:    "Dragonstrike",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.Landed() || !spellEffect.ProcMask.Matches(procMask) {
-				return
-			}
-			if sim.RandomFloat("Dragonstrike") > procChance {
-				return
-			}
-
-			procAura.Activate(sim)
-		},
-	})
-}
-
-func ApplyDespair(agent core.Agent) {
-	character := agent.GetCharacter()
-	actionID := core.ActionID{SpellID: 34580}
-
-	procSpell := character.GetOrRegisterSpell(core.SpellConfig{
-		ActionID:    actionID,
-		SpellSchool: core.SpellSchoolPhysical,
-		SpellExtras: core.SpellExtrasIgnoreResists,
-		ApplyEffects: core.ApplyEffectFuncDirectDamage(core.SpellEffect{
-			ProcMask:         core.ProcMaskEmpty,
-			DamageMultiplier: 1,
-			ThreatMultiplier: 1,
-
-			BaseDamage:     core.BaseDamageConfigFlat(600),
-			OutcomeApplier: character.OutcomeFuncMeleeSpecialHitAndCrit(character.DefaultMeleeCritMultiplier()),
-		}),
-	})
-
-	const procChance = 0.5 * 3.5 / 60.0
-	character.GetOrRegisterAura(core.Aura{
-		Label:    "Despair",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			// ProcMask: 20
-			if !spellEffect.Landed() || !spellEffect.ProcMask.Matches(core.ProcMaskMelee) {
-				return
-			}
-			if sim.RandomFloat("Despair") > procChance {
-				return
-			}
-
-			procSpell.Cast(sim, spellEffect.Target)
-		},
-	})
-}
-
 func ApplyTheDecapitator(agent core.Agent) {
 	character := agent.GetCharacter()
 	actionID := core.ActionID{ItemID: 28767}
@@ -486,6 +404,7 @@ func ApplyGlaiveOfThePit(agent core.Agent) {
 
 	const hasteBonus = 212.0
 	const procChance = 3.7 / 60.0
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 34696})
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Glaive of the Pit",
@@ -501,6 +420,7 @@ func ApplyGlaiveOfThePit(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procSpell.Cast(sim, spellEffect.Target)
 		},
 	})
@@ -510,7 +430,8 @@ func ApplyBandOfTheEternalDefender(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 0.03
-	procAura := character.NewTemporaryStatsAura("Band of the Eternal Defender Proc", core.ActionID{ItemID: 29297}, stats.Stats{stats.Armor: 800}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 29297})
+	procAura := character.NewTemporaryStatsProcAura("Band of the Eternal Defender Proc", core.ActionID{ItemID: 29297}, stats.Stats{stats.Armor: 800}, time.Second*10, procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
@@ -532,6 +453,7 @@ func ApplyBandOfTheEternalDefender(agent core.Agent) {
 			}
 			if sim.RandomFloat("Band of the Eternal Defender") < procChance {
 				icd.Use(sim)
+				procMetrics.AddProc()
 				procAura.Activate(sim)
 			}
 		},
@@ -541,8 +463,10 @@ func ApplyBandOfTheEternalDefender(agent core.Agent) {
 func ApplyBandOfTheEternalChampion(agent core.Agent) {
 	character := agent.GetCharacter()
 
-	procAura := character.NewTemporaryStatsAura("Band of the Eternal Champion Proc", core.ActionID{ItemID: 29301}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 29301})
+	procAura := character.NewTemporaryStatsProcAura("Band of the Eternal Champion Proc", core.ActionID{ItemID: 29301}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*10, procMetrics)
 	ppmm := character.AutoAttacks.NewPPMManager(1.0)
+	ppmm.SetMetrics(procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
@@ -577,7 +501,8 @@ func ApplyTheBladefist(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 2.7 / 60.0
-	procAura := character.NewTemporaryStatsAura("The Bladefist Proc", core.ActionID{ItemID: 29348}, stats.Stats{stats.MeleeHaste: 180}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 29348})
+	procAura := character.NewTemporaryStatsProcAura("The Bladefist Proc", core.ActionID{ItemID: 29348}, stats.Stats{stats.MeleeHaste: 180}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "The Bladefist",
@@ -593,6 +518,7 @@ func ApplyTheBladefist(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -603,8 +529,10 @@ func ApplyHeartrazor(agent core.Agent) {
 	mh, oh := character.GetWeaponHands(29962)
 	procMask := core.GetMeleeProcMaskForHands(mh, oh)
 
-	procAura := character.NewTemporaryStatsAura("Heartrazor Proc", core.ActionID{ItemID: 29962}, stats.Stats{stats.AttackPower: 270, stats.RangedAttackPower: 270}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 29962})
+	procAura := character.NewTemporaryStatsProcAura("Heartrazor Proc", core.ActionID{ItemID: 29962}, stats.Stats{stats.AttackPower: 270, stats.RangedAttackPower: 270}, time.Second*10, procMetrics)
 	ppmm := character.AutoAttacks.NewPPMManager(1.0)
+	ppmm.SetMetrics(procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Heartrazor",
@@ -633,6 +561,7 @@ func ApplyRodOfTheSunKing(agent core.Agent) {
 
 	const procChance = 2.7 / 60.0
 	actionID := core.ActionID{ItemID: 29996}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Rod of the Sun King",
@@ -649,11 +578,13 @@ func ApplyRodOfTheSunKing(agent core.Agent) {
 				if sim.RandomFloat("Rod of the Sun King") > procChance {
 					return
 				}
+				procMetrics.AddProc()
 				spell.Unit.AddRage(sim, 5, actionID)
 			} else if spell.Unit.HasEnergyBar() {
 				if sim.RandomFloat("Rod of the Sun King") > procChance {
 					return
 				}
+				procMetrics.AddProc()
 				spell.Unit.AddEnergy(sim, 10, actionID)
 			}
 		},
@@ -664,7 +595,8 @@ func ApplyWorldBreaker(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 3.7 / 60.0
-	procAura := character.NewTemporaryStatsAura("World Breaker Proc", core.ActionID{ItemID: 30090}, stats.Stats{stats.MeleeCrit: 900}, time.Second*4)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 30090})
+	procAura := character.NewTemporaryStatsProcAura("World Breaker Proc", core.ActionID{ItemID: 30090}, stats.Stats{stats.MeleeCrit: 900}, time.Second*4, procMetrics)
 
 	character.RegisterAura(core.Aura{
 		Label:    "World Breaker",
@@ -682,6 +614,7 @@ func ApplyWorldBreaker(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -696,7 +629,7 @@ func ApplyWarpSlicer(agent core.Agent) {
 	const inverseBonus = 1 / 1.2
 	const procChance = 0.5
 
-	procAura := character.GetOrRegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:    "Warp Slicer Proc",
 		ActionID: core.ActionID{ItemID: 30311},
 		Duration: time.Second * 30,
@@ -706,7 +639,10 @@ func ApplyWarpSlicer(agent core.Agent) {
 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
 			character.MultiplyMeleeSpeed(sim, inverseBonus)
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(procAuraConfig.ActionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{})
+	procAura := character.GetOrRegisterAura(procAuraConfig)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Warp Slicer",
@@ -722,6 +658,7 @@ func ApplyWarpSlicer(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -734,7 +671,7 @@ func ApplyDevastation(agent core.Agent) {
 	const inverseBonus = 1 / 1.2
 	const procChance = 0.5
 
-	procAura := character.GetOrRegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:    "Devastation Proc",
 		ActionID: core.ActionID{ItemID: 30316},
 		Duration: time.Second * 30,
@@ -744,7 +681,10 @@ func ApplyDevastation(agent core.Agent) {
 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
 			character.MultiplyMeleeSpeed(sim, inverseBonus)
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(procAuraConfig.ActionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{})
+	procAura := character.GetOrRegisterAura(procAuraConfig)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Devastation",
@@ -760,6 +700,7 @@ func ApplyDevastation(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -783,6 +724,7 @@ func ApplyBladeOfUnquenchedThirst(agent core.Agent) {
 	})
 
 	const procChance = 0.02
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 31193})
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Blade of Unquenched Thirst",
 		Duration: core.NeverExpires,
@@ -797,6 +739,7 @@ func ApplyBladeOfUnquenchedThirst(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procSpell.Cast(sim, spellEffect.Target)
 		},
 	})
@@ -806,7 +749,8 @@ func ApplySingingCrystalAxe(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 3.5 / 60.0
-	procAura := character.NewTemporaryStatsAura("Singing Crystal Axe Proc", core.ActionID{ItemID: 31318}, stats.Stats{stats.MeleeHaste: 400}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 31318})
+	procAura := character.NewTemporaryStatsProcAura("Singing Crystal Axe Proc", core.ActionID{ItemID: 31318}, stats.Stats{stats.MeleeHaste: 400}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Singing Crystal Axe",
@@ -822,6 +766,7 @@ func ApplySingingCrystalAxe(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -839,6 +784,7 @@ func ApplyBlinkstrike(agent core.Agent) {
 	if !oh {
 		ppmm.SetProcChance(false, 0)
 	}
+	ppmm.SetMetrics(character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 31332}))
 
 	var blinkstrikeSpell *core.Spell
 	icd := core.Cooldown{
@@ -884,7 +830,7 @@ func ApplyTheNightBlade(agent core.Agent) {
 	mh, oh := character.GetWeaponHands(31331)
 	procMask := core.GetMeleeProcMaskForHands(mh, oh)
 
-	procAura := character.GetOrRegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:     "The Night Blade Proc",
 		ActionID:  core.ActionID{ItemID: 31331},
 		Duration:  time.Second * 10,
@@ -892,7 +838,10 @@ func ApplyTheNightBlade(agent core.Agent) {
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatDynamic(sim, stats.ArmorPenetration, 435*float64(newStacks-oldStacks))
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(procAuraConfig.ActionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{stats.ArmorPenetration: 435})
+	procAura := character.GetOrRegisterAura(procAuraConfig)
 
 	const procChance = 2 * 1.8 / 60.0
 	character.GetOrRegisterAura(core.Aura{
@@ -909,6 +858,7 @@ func ApplyTheNightBlade(agent core.Agent) {
 				return
 			}
 
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 			procAura.AddStack(sim)
 		},
@@ -938,7 +888,7 @@ func ApplySyphonOfTheNathrezim(agent core.Agent) {
 		}),
 	})
 
-	procAura := character.GetOrRegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:    "Siphon Essence",
 		ActionID: core.ActionID{SpellID: 40291},
 		Duration: time.Second * 6,
@@ -949,7 +899,11 @@ func ApplySyphonOfTheNathrezim(agent core.Agent) {
 
 			procSpell.Cast(sim, spellEffect.Target)
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(procAuraConfig.ActionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{})
+	ppmm.SetMetrics(procMetrics)
+	procAura := character.GetOrRegisterAura(procAuraConfig)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Syphon of the Nathrezim",
@@ -973,7 +927,8 @@ func ApplyBulwarkOfAzzinoth(agent core.Agent) {
 	character := agent.GetCharacter()
 
 	const procChance = 0.02
-	procAura := character.NewTemporaryStatsAura("Bulwark Of Azzinoth Proc", core.ActionID{ItemID: 32375}, stats.Stats{stats.Armor: 2000}, time.Second*10)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 32375})
+	procAura := character.NewTemporaryStatsProcAura("Bulwark Of Azzinoth Proc", core.ActionID{ItemID: 32375}, stats.Stats{stats.Armor: 2000}, time.Second*10, procMetrics)
 
 	character.GetOrRegisterAura(core.Aura{
 		Label:    "Bulwark Of Azzinoth",
@@ -983,6 +938,7 @@ func ApplyBulwarkOfAzzinoth(agent core.Agent) {
 		},
 		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
 			if spellEffect.Landed() && spell.SpellSchool == core.SpellSchoolPhysical && sim.RandomFloat("Bulwark of Azzinoth") < procChance {
+				procMetrics.AddProc()
 				procAura.Activate(sim)
 			}
 		},
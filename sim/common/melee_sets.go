@@ -41,7 +41,8 @@ var ItemSetDesolationBattlegear = core.ItemSet{
 		},
 		4: func(agent core.Agent) {
 			character := agent.GetCharacter()
-			procAura := character.NewTemporaryStatsAura("Desolation Battlegear Proc", core.ActionID{SpellID: 37617}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 37617})
+			procAura := character.NewTemporaryStatsProcAura("Desolation Battlegear Proc", core.ActionID{SpellID: 37617}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15, procMetrics)
 
 			icd := core.Cooldown{
 				Timer:    character.NewTimer(),
@@ -69,6 +70,7 @@ var ItemSetDesolationBattlegear = core.ItemSet{
 						return
 					}
 					icd.Use(sim)
+					procMetrics.AddProc()
 					procAura.Activate(sim)
 				},
 			})
@@ -84,7 +86,8 @@ var ItemSetDoomplateBattlegear = core.ItemSet{
 		},
 		4: func(agent core.Agent) {
 			character := agent.GetCharacter()
-			procAura := character.NewTemporaryStatsAura("Doomplate Battlegear Proc", core.ActionID{SpellID: 37611}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 37611})
+			procAura := character.NewTemporaryStatsProcAura("Doomplate Battlegear Proc", core.ActionID{SpellID: 37611}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15, procMetrics)
 
 			const procChance = 0.02
 			character.RegisterAura(core.Aura{
@@ -103,6 +106,7 @@ var ItemSetDoomplateBattlegear = core.ItemSet{
 					if sim.RandomFloat("Doomplate Battlegear") > procChance {
 						return
 					}
+					procMetrics.AddProc()
 					procAura.Activate(sim)
 				},
 			})
@@ -157,6 +161,7 @@ var ItemSetFistsOfFury = core.ItemSet{
 			})
 
 			ppmm := character.AutoAttacks.NewPPMManager(2)
+			ppmm.SetMetrics(character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 41989}))
 
 			character.RegisterAura(core.Aura{
 				Label:    "Fists of Fury",
@@ -216,9 +221,11 @@ var ItemSetTwinBladesOfAzzinoth = core.ItemSet{
 			if character.CurrentTarget.MobType == proto.MobType_MobTypeDemon {
 				character.PseudoStats.MobTypeAttackPower += 200
 			}
-			procAura := character.NewTemporaryStatsAura("Twin Blade of Azzinoth Proc", core.ActionID{SpellID: 41435}, stats.Stats{stats.MeleeHaste: 450}, time.Second*10)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 41435})
+			procAura := character.NewTemporaryStatsProcAura("Twin Blade of Azzinoth Proc", core.ActionID{SpellID: 41435}, stats.Stats{stats.MeleeHaste: 450}, time.Second*10, procMetrics)
 
 			ppmm := character.AutoAttacks.NewPPMManager(1.0)
+			ppmm.SetMetrics(procMetrics)
 			icd := core.Cooldown{
 				Timer:    character.NewTimer(),
 				Duration: time.Second * 45,
@@ -263,7 +270,8 @@ var ItemSetWastewalkerArmor = core.ItemSet{
 		},
 		4: func(agent core.Agent) {
 			character := agent.GetCharacter()
-			procAura := character.NewTemporaryStatsAura("Wastewalker Armor Proc", core.ActionID{SpellID: 37618}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15)
+			procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{SpellID: 37618})
+			procAura := character.NewTemporaryStatsProcAura("Wastewalker Armor Proc", core.ActionID{SpellID: 37618}, stats.Stats{stats.AttackPower: 160, stats.RangedAttackPower: 160}, time.Second*15, procMetrics)
 
 			icd := core.Cooldown{
 				Timer:    character.NewTimer(),
@@ -291,6 +299,7 @@ var ItemSetWastewalkerArmor = core.ItemSet{
 						return
 					}
 					icd.Use(sim)
+					procMetrics.AddProc()
 					procAura.Activate(sim)
 				},
 			})
@@ -13,16 +13,12 @@ func init() {
 	core.AddItemEffect(11815, ApplyHandOfJustice)
 	core.AddItemEffect(21670, ApplyBadgeOfTheSwarmguard)
 	core.AddItemEffect(23206, ApplyMarkOfTheChampionMelee)
-	core.AddItemEffect(28034, ApplyHourglassUnraveller)
 	core.AddItemEffect(28579, ApplyRomulosPoisonVial)
 	core.AddItemEffect(28830, ApplyDragonspineTrophy)
-	core.AddItemEffect(30627, ApplyTsunamiTalisman)
 	core.AddItemEffect(31857, ApplyDarkmoonCardWrath)
 	core.AddItemEffect(31858, ApplyDarkmoonCardVengeance)
-	core.AddItemEffect(32505, ApplyMadnessOfTheBetrayer)
 	core.AddItemEffect(32654, ApplyCrystalforgedTrinket)
 	core.AddItemEffect(34427, ApplyBlackenedNaaruSliver)
-	core.AddItemEffect(34472, ApplyShardOfContempt)
 
 	//// Battlemasters trinkets
 	//sharedBattlemasterCooldownID := core.NewCooldownID()
@@ -83,6 +79,7 @@ func ApplyHandOfJustice(agent core.Agent) {
 		Duration: time.Second * 2,
 	}
 	procChance := 0.013333
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 11815})
 
 	character.RegisterAura(core.Aura{
 		Label:    "Hand of Justice",
@@ -105,12 +102,14 @@ func ApplyHandOfJustice(agent core.Agent) {
 			}
 
 			if !icd.IsReady(sim) {
+				procMetrics.AddICDBlockedProc(procChance)
 				return
 			}
 
 			if sim.RandomFloat("HandOfJustice") > procChance {
 				return
 			}
+			procMetrics.AddProc()
 			icd.Use(sim)
 
 			aura.Unit.AutoAttacks.MaybeReplaceMHSwing(sim, handOfJusticeSpell).Cast(sim, spellEffect.Target)
@@ -145,7 +144,7 @@ func ApplyCrystalforgedTrinket(agent core.Agent) {
 func ApplyBadgeOfTheSwarmguard(agent core.Agent) {
 	character := agent.GetCharacter()
 
-	procAura := character.RegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:     "Badge of the Swarmguard Proc",
 		ActionID:  core.ActionID{SpellID: 26481},
 		Duration:  core.NeverExpires,
@@ -153,10 +152,14 @@ func ApplyBadgeOfTheSwarmguard(agent core.Agent) {
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatDynamic(sim, stats.ArmorPenetration, 200*float64(newStacks-oldStacks))
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(procAuraConfig.ActionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{stats.ArmorPenetration: 200})
+	procAura := character.RegisterAura(procAuraConfig)
 
 	actionID := core.ActionID{ItemID: 21670}
 	ppmm := character.AutoAttacks.NewPPMManager(10.0)
+	ppmm.SetMetrics(procMetrics)
 	activeAura := character.RegisterAura(core.Aura{
 		Label:    "Badge of the Swarmguard",
 		ActionID: actionID,
@@ -210,42 +213,6 @@ func ApplyMarkOfTheChampionMelee(agent core.Agent) {
 	}
 }
 
-func ApplyHourglassUnraveller(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Rage of the Unraveller", core.ActionID{ItemID: 28034}, stats.Stats{stats.AttackPower: 300, stats.RangedAttackPower: 300}, time.Second*10)
-	const procChance = 0.1
-
-	icd := core.Cooldown{
-		Timer:    character.NewTimer(),
-		Duration: time.Second * 50,
-	}
-
-	character.RegisterAura(core.Aura{
-		Label:    "Hourglass of the Unraveller",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.Outcome.Matches(core.OutcomeCrit) {
-				return
-			}
-			if !spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged) {
-				return
-			}
-			if !icd.IsReady(sim) {
-				return
-			}
-			if sim.RandomFloat("Hourglass of the Unraveller") > procChance {
-				return
-			}
-
-			icd.Use(sim)
-			procAura.Activate(sim)
-		},
-	})
-}
-
 func ApplyRomulosPoisonVial(agent core.Agent) {
 	character := agent.GetCharacter()
 
@@ -263,6 +230,7 @@ func ApplyRomulosPoisonVial(agent core.Agent) {
 	})
 
 	ppmm := character.AutoAttacks.NewPPMManager(1.0)
+	ppmm.SetMetrics(character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 28579}))
 
 	character.RegisterAura(core.Aura{
 		Label:    "Romulos Poison Vial",
@@ -286,13 +254,17 @@ func ApplyRomulosPoisonVial(agent core.Agent) {
 
 func ApplyDragonspineTrophy(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Dragonspine Trophy Proc", core.ActionID{ItemID: 28830}, stats.Stats{stats.MeleeHaste: 325}, time.Second*10)
+	actionID := core.ActionID{ItemID: 28830}
+	bonus := stats.Stats{stats.MeleeHaste: 325}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+	procAura := character.NewTemporaryStatsProcAura("Dragonspine Trophy Proc", actionID, bonus, time.Second*10, procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
 		Duration: time.Second * 20,
 	}
 	ppmm := character.AutoAttacks.NewPPMManager(1.0)
+	ppmm.SetMetrics(procMetrics)
 
 	character.RegisterAura(core.Aura{
 		Label:    "Dragonspine Trophy",
@@ -306,6 +278,7 @@ func ApplyDragonspineTrophy(agent core.Agent) {
 				return
 			}
 			if !icd.IsReady(sim) {
+				procMetrics.AddICDBlockedProc(ppmm.ProcChance(spellEffect.IsMH(), spellEffect.ProcMask.Matches(core.ProcMaskRanged)))
 				return
 			}
 			if !ppmm.Proc(sim, spellEffect.IsMH(), spellEffect.ProcMask.Matches(core.ProcMaskRanged), "dragonspine") {
@@ -318,55 +291,23 @@ func ApplyDragonspineTrophy(agent core.Agent) {
 	})
 }
 
-func ApplyTsunamiTalisman(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Tsunami Talisman Proc", core.ActionID{ItemID: 30627}, stats.Stats{stats.AttackPower: 340, stats.RangedAttackPower: 340}, time.Second*10)
-	const procChance = 0.1
-
-	icd := core.Cooldown{
-		Timer:    character.NewTimer(),
-		Duration: time.Second * 45,
-	}
-
-	character.RegisterAura(core.Aura{
-		Label:    "Tsunami Talisman",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.Outcome.Matches(core.OutcomeCrit) {
-				return
-			}
-			if !spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged) {
-				return
-			}
-			if !icd.IsReady(sim) {
-				return
-			}
-			if sim.RandomFloat("Tsunami Talisman") > procChance {
-				return
-			}
-
-			icd.Use(sim)
-			procAura.Activate(sim)
-		},
-	})
-}
-
 func ApplyDarkmoonCardWrath(agent core.Agent) {
 	character := agent.GetCharacter()
 
-	procAura := character.RegisterAura(core.Aura{
+	actionID := core.ActionID{ItemID: 31857}
+	procAuraConfig := core.Aura{
 		Label:     "DMC Wrath Proc",
-		ActionID:  core.ActionID{ItemID: 31857},
+		ActionID:  actionID,
 		Duration:  time.Second * 10,
 		MaxStacks: 1000,
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatDynamic(sim, stats.MeleeCrit, 17*float64(newStacks-oldStacks))
 			character.AddStatDynamic(sim, stats.SpellCrit, 17*float64(newStacks-oldStacks))
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+	procMetrics.TrackAura(&procAuraConfig, stats.Stats{stats.MeleeCrit: 17, stats.SpellCrit: 17})
+	procAura := character.RegisterAura(procAuraConfig)
 
 	character.RegisterAura(core.Aura{
 		Label:    "DMC Wrath",
@@ -383,6 +324,7 @@ func ApplyDarkmoonCardWrath(agent core.Agent) {
 			if spellEffect.Outcome.Matches(core.OutcomeCrit) {
 				procAura.Deactivate(sim)
 			} else {
+				procMetrics.AddProc()
 				procAura.Activate(sim)
 				procAura.AddStack(sim)
 			}
@@ -407,6 +349,8 @@ func ApplyDarkmoonCardVengeance(agent core.Agent) {
 		}),
 	})
 
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+
 	// Normal proc chance.
 	procChance := 0.1
 
@@ -425,6 +369,7 @@ func ApplyDarkmoonCardVengeance(agent core.Agent) {
 	if procChanceOnHitDealt > 0 {
 		onSpellHitDealt = func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
 			if spellEffect.Landed() && spellEffect.ProcMask.Matches(core.ProcMaskMelee) && sim.RandomFloat("DMC Vengeance") < procChanceOnHitDealt {
+				procMetrics.AddProc()
 				procSpell.Cast(sim, spell.Unit)
 			}
 		}
@@ -438,6 +383,7 @@ func ApplyDarkmoonCardVengeance(agent core.Agent) {
 		},
 		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
 			if spellEffect.Landed() && sim.RandomFloat("DMC Vengeance") < procChance {
+				procMetrics.AddProc()
 				procSpell.Cast(sim, spell.Unit)
 			}
 		},
@@ -445,43 +391,19 @@ func ApplyDarkmoonCardVengeance(agent core.Agent) {
 	})
 }
 
-func ApplyMadnessOfTheBetrayer(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Madness of the Betrayer Proc", core.ActionID{ItemID: 32505}, stats.Stats{stats.ArmorPenetration: 300}, time.Second*10)
-
-	ppmm := character.AutoAttacks.NewPPMManager(1.0)
-
-	character.RegisterAura(core.Aura{
-		Label:    "Madness of the Betrayer",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			// mask 340
-			if !spellEffect.Landed() || !spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged) {
-				return
-			}
-			if !ppmm.Proc(sim, spellEffect.IsMH(), spellEffect.ProcMask.Matches(core.ProcMaskRanged), "Madness of the Betrayer") {
-				return
-			}
-
-			procAura.Activate(sim)
-		},
-	})
-}
-
 func ApplyBlackenedNaaruSliver(agent core.Agent) {
 	character := agent.GetCharacter()
 
+	actionID := core.ActionID{ItemID: 34427}
+	bonus := stats.Stats{stats.AttackPower: 44, stats.RangedAttackPower: 44}
 	var bonusPerStack stats.Stats
-	procAura := character.RegisterAura(core.Aura{
+	procAuraConfig := core.Aura{
 		Label:     "Blackened Naaru Sliver Proc",
-		ActionID:  core.ActionID{ItemID: 34427},
+		ActionID:  actionID,
 		Duration:  time.Second * 20,
 		MaxStacks: 10,
 		OnInit: func(aura *core.Aura, sim *core.Simulation) {
-			bonusPerStack = character.ApplyStatDependencies(stats.Stats{stats.AttackPower: 44, stats.RangedAttackPower: 44})
+			bonusPerStack = character.ApplyStatDependencies(bonus)
 		},
 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
 			character.AddStatsDynamic(sim, bonusPerStack.Multiply(float64(newStacks-oldStacks)))
@@ -491,7 +413,10 @@ func ApplyBlackenedNaaruSliver(agent core.Agent) {
 				aura.AddStack(sim)
 			}
 		},
-	})
+	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(actionID)
+	procMetrics.TrackAura(&procAuraConfig, bonus)
+	procAura := character.RegisterAura(procAuraConfig)
 
 	const procChance = 0.1
 
@@ -512,45 +437,14 @@ func ApplyBlackenedNaaruSliver(agent core.Agent) {
 				return
 			}
 			if !icd.IsReady(sim) {
+				procMetrics.AddICDBlockedProc(procChance)
 				return
 			}
 			if sim.RandomFloat("Blackened Naaru Sliver") > procChance {
 				return
 			}
 
-			icd.Use(sim)
-			procAura.Activate(sim)
-		},
-	})
-}
-
-func ApplyShardOfContempt(agent core.Agent) {
-	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Shard of Contempt Proc", core.ActionID{ItemID: 34472}, stats.Stats{stats.AttackPower: 230, stats.RangedAttackPower: 230}, time.Second*20)
-
-	icd := core.Cooldown{
-		Timer:    character.NewTimer(),
-		Duration: time.Second * 45,
-	}
-	const procChance = 0.1
-
-	character.RegisterAura(core.Aura{
-		Label:    "Shard of Contempt",
-		Duration: core.NeverExpires,
-		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-			aura.Activate(sim)
-		},
-		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
-			if !spellEffect.Landed() || !spellEffect.ProcMask.Matches(core.ProcMaskMeleeOrRanged) {
-				return
-			}
-			if !icd.IsReady(sim) {
-				return
-			}
-			if sim.RandomFloat("Shard of Contempt") > procChance {
-				return
-			}
-
+			procMetrics.AddProc()
 			icd.Use(sim)
 			procAura.Activate(sim)
 		},
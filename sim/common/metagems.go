@@ -26,7 +26,8 @@ func ApplyBrutalEarthstormDiamond(agent core.Agent) {
 
 func ApplyMysticalSkyfireDiamond(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Mystic Focus Proc", core.ActionID{ItemID: 25893}, stats.Stats{stats.SpellHaste: 320}, time.Second*4)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 25893})
+	procAura := character.NewTemporaryStatsProcAura("Mystic Focus Proc", core.ActionID{ItemID: 25893}, stats.Stats{stats.SpellHaste: 320}, time.Second*4, procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
@@ -44,6 +45,7 @@ func ApplyMysticalSkyfireDiamond(agent core.Agent) {
 				return
 			}
 			icd.Use(sim)
+			procMetrics.AddProc()
 			procAura.Activate(sim)
 		},
 	})
@@ -55,6 +57,7 @@ func ApplyInsightfulEarthstormDiamond(agent core.Agent) {
 		Timer:    character.NewTimer(),
 		Duration: time.Second * 15,
 	}
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 25901})
 
 	character.RegisterAura(core.Aura{
 		Label:    "Insightful Earthstorm Diamond",
@@ -66,6 +69,7 @@ func ApplyInsightfulEarthstormDiamond(agent core.Agent) {
 			if !icd.IsReady(sim) || sim.RandomFloat("Insightful Earthstorm Diamond") > 0.04 {
 				return
 			}
+			procMetrics.AddProc()
 			icd.Use(sim)
 			character.AddMana(sim, 300, core.ActionID{ItemID: 25901}, false)
 		},
@@ -84,13 +88,15 @@ func ApplyEmberSkyfireDiamond(agent core.Agent) {
 
 func ApplyThunderingSkyfireDiamond(agent core.Agent) {
 	character := agent.GetCharacter()
-	procAura := character.NewTemporaryStatsAura("Thundering Skyfire Diamond Proc", core.ActionID{ItemID: 32410}, stats.Stats{stats.MeleeHaste: 240}, time.Second*6)
+	procMetrics := character.Metrics.GetOrRegisterProcMetrics(core.ActionID{ItemID: 32410})
+	procAura := character.NewTemporaryStatsProcAura("Thundering Skyfire Diamond Proc", core.ActionID{ItemID: 32410}, stats.Stats{stats.MeleeHaste: 240}, time.Second*6, procMetrics)
 
 	icd := core.Cooldown{
 		Timer:    character.NewTimer(),
 		Duration: time.Second * 40,
 	}
 	ppmm := character.AutoAttacks.NewPPMManager(1.5)
+	ppmm.SetMetrics(procMetrics)
 
 	character.RegisterAura(core.Aura{
 		Label:    "Thundering Skyfire Diamond",
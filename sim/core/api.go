@@ -2,6 +2,9 @@
 package core
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/wowsims/tbc/sim/core/items"
 	"github.com/wowsims/tbc/sim/core/proto"
 	"github.com/wowsims/tbc/sim/core/stats"
@@ -17,7 +20,9 @@ func GetGearList(request *proto.GearListRequest) *proto.GearListResult {
 
 	for i := range items.Items {
 		item := items.Items[i]
-		result.Items = append(result.Items, item.ToProto())
+		itemProto := item.ToProto()
+		itemProto.UnimplementedEffect = HasUnimplementedEffect(item)
+		result.Items = append(result.Items, itemProto)
 	}
 	for i := range items.Gems {
 		gem := items.Gems[i]
@@ -31,14 +36,56 @@ func GetGearList(request *proto.GearListRequest) *proto.GearListResult {
 	return result
 }
 
+/**
+ * Returns the items which have an on-use or proc effect that the sim doesn't implement.
+ */
+func GetUnimplementedItems(request *proto.UnimplementedItemsRequest) *proto.UnimplementedItemsResult {
+	result := &proto.UnimplementedItemsResult{}
+
+	for i := range items.Items {
+		item := items.Items[i]
+		if HasUnimplementedEffect(item) {
+			itemProto := item.ToProto()
+			itemProto.UnimplementedEffect = true
+			result.Items = append(result.Items, itemProto)
+		}
+	}
+
+	return result
+}
+
 /**
  * Returns character stats taking into account gear / buffs / consumes / etc
  */
 func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
+	if err := validateRaidTalents(csr.Raid); err != nil {
+		return &proto.ComputeStatsResult{
+			ErrorMsg: err.Error(),
+		}
+	}
+
 	env := NewEnvironment(*csr.Raid, proto.Encounter{})
+	if err := env.validate(); err != nil {
+		return &proto.ComputeStatsResult{
+			ErrorMsg: err.Error(),
+		}
+	}
+
+	var warnings []string
+	for _, party := range env.Raid.Parties {
+		for _, player := range party.Players {
+			character := player.GetCharacter()
+			for _, item := range character.Equip {
+				if HasUnimplementedEffect(item) {
+					warnings = append(warnings, fmt.Sprintf("%s: %s has an effect which isn't implemented, only its stats are used", character.Name, item.Name))
+				}
+			}
+		}
+	}
 
 	return &proto.ComputeStatsResult{
 		RaidStats: env.Raid.GetStats(),
+		Warnings:  warnings,
 	}
 }
 
@@ -48,15 +95,17 @@ func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
 func StatWeights(request *proto.StatWeightsRequest) *proto.StatWeightsResult {
 	statsToWeigh := stats.ProtoArrayToStatsList(request.StatsToWeigh)
 
-	result := CalcStatWeight(*request, statsToWeigh, stats.Stat(request.EpReferenceStat), nil)
+	result := CalcStatWeight(context.Background(), *request, statsToWeigh, stats.Stat(request.EpReferenceStat), nil)
 
 	return result.ToProto()
 }
 
-func StatWeightsAsync(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics) {
+// The stat sims stop early if ctx is cancelled, and the final result then only
+// has an error message.
+func StatWeightsAsync(ctx context.Context, request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics) {
 	statsToWeigh := stats.ProtoArrayToStatsList(request.StatsToWeigh)
 	go func() {
-		result := CalcStatWeight(*request, statsToWeigh, stats.Stat(request.EpReferenceStat), progress)
+		result := CalcStatWeight(ctx, *request, statsToWeigh, stats.Stat(request.EpReferenceStat), progress)
 		progress <- &proto.ProgressMetrics{
 			FinalWeightResult: result.ToProto(),
 		}
@@ -70,6 +119,62 @@ func RunRaidSim(request *proto.RaidSimRequest) *proto.RaidSimResult {
 	return RunSim(*request, nil)
 }
 
-func RunRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) {
-	go RunSim(*request, progress)
+// The sim stops early if ctx is cancelled, and the final result then only has
+// an error message.
+func RunRaidSimAsync(ctx context.Context, request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) {
+	go RunSimWithContext(ctx, *request, progress)
+}
+
+/**
+ * Finds the best gear set from a pool of items, gems, and enchants.
+ */
+func GearOptimize(request *proto.GearOptimizeRequest) *proto.GearOptimizeResult {
+	return CalcGearOptimize(context.Background(), *request, nil).ToProto()
+}
+
+// The sims stop early if ctx is cancelled, and the final result then only has
+// an error message.
+func GearOptimizeAsync(ctx context.Context, request *proto.GearOptimizeRequest, progress chan *proto.ProgressMetrics) {
+	go func() {
+		result := CalcGearOptimize(ctx, *request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalGearOptimizeResult: result.ToProto(),
+		}
+	}()
+}
+
+/**
+ * Finds the cooldown usage times which give the most DPS.
+ */
+func CooldownOptimize(request *proto.CooldownOptimizeRequest) *proto.CooldownOptimizeResult {
+	return CalcCooldownOptimize(context.Background(), *request, nil).ToProto()
+}
+
+// The sims stop early if ctx is cancelled, and the final result then only has
+// an error message.
+func CooldownOptimizeAsync(ctx context.Context, request *proto.CooldownOptimizeRequest, progress chan *proto.ProgressMetrics) {
+	go func() {
+		result := CalcCooldownOptimize(ctx, *request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalCooldownOptimizeResult: result.ToProto(),
+		}
+	}()
+}
+
+/**
+ * Finds the best talent build by varying some of a player's talents.
+ */
+func TalentOptimize(request *proto.TalentOptimizeRequest) *proto.TalentOptimizeResult {
+	return CalcTalentOptimize(context.Background(), *request, nil).ToProto()
+}
+
+// The sims stop early if ctx is cancelled, and the final result then only has
+// an error message.
+func TalentOptimizeAsync(ctx context.Context, request *proto.TalentOptimizeRequest, progress chan *proto.ProgressMetrics) {
+	go func() {
+		result := CalcTalentOptimize(ctx, *request, progress)
+		progress <- &proto.ProgressMetrics{
+			FinalTalentOptimizeResult: result.ToProto(),
+		}
+	}()
 }
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// How long to wait before evaluating the priority list again, when none of its
+// actions could be used.
+const aplMaxWaitTime = time.Millisecond * 500
+
+// A rotation defined by a user-supplied action priority list, as configured by
+// a proto.APLRotation. When set, it is used instead of Agent.OnGCDReady.
+type APLRotation struct {
+	character *Character
+	actions   []*aplAction
+}
+
+type aplAction struct {
+	spell *Spell
+
+	// Whether the spell belongs to a major cooldown, which must be activated
+	// through the majorCooldownManager.
+	isMajorCooldown bool
+
+	conditions []aplCondition
+}
+
+// Returns whether the condition currently passes.
+type aplCondition func(sim *Simulation) bool
+
+// Returns an error if the priority list refers to an unknown spell or
+// condition.
+func newAPLRotation(character *Character, config *proto.APLRotation) (*APLRotation, error) {
+	rotation := &APLRotation{
+		character: character,
+	}
+
+	for _, actionConfig := range config.PriorityList {
+		if actionConfig.ActionId == nil {
+			return nil, fmt.Errorf("APL action is missing an action ID")
+		}
+		actionID := ProtoToActionID(*actionConfig.ActionId)
+		spell := character.GetSpell(actionID)
+		if spell == nil {
+			return nil, fmt.Errorf("APL action %s is not a spell known by %s", actionID, character.Label)
+		}
+
+		action := &aplAction{
+			spell: spell,
+		}
+		for i := range character.initialMajorCooldowns {
+			mcd := &character.initialMajorCooldowns[i]
+			if mcd.Spell == spell {
+				// The priority list decides when to use this cooldown instead.
+				mcd.disabled = true
+				action.isMajorCooldown = true
+			}
+		}
+		for _, conditionConfig := range actionConfig.Conditions {
+			condition, err := rotation.newCondition(spell, conditionConfig)
+			if err != nil {
+				return nil, err
+			}
+			action.conditions = append(action.conditions, condition)
+		}
+
+		rotation.actions = append(rotation.actions, action)
+	}
+
+	return rotation, nil
+}
+
+func (rotation *APLRotation) newCondition(actionSpell *Spell, config *proto.APLCondition) (aplCondition, error) {
+	unit := &rotation.character.Unit
+
+	compare := func(getValue func(sim *Simulation) float64) aplCondition {
+		threshold := config.Value
+		switch config.Comparison {
+		case proto.APLCondition_GreaterThan:
+			return func(sim *Simulation) bool { return getValue(sim) > threshold }
+		case proto.APLCondition_LessThanOrEqual:
+			return func(sim *Simulation) bool { return getValue(sim) <= threshold }
+		case proto.APLCondition_LessThan:
+			return func(sim *Simulation) bool { return getValue(sim) < threshold }
+		case proto.APLCondition_Equal:
+			return func(sim *Simulation) bool { return getValue(sim) == threshold }
+		default:
+			return func(sim *Simulation) bool { return getValue(sim) >= threshold }
+		}
+	}
+
+	spell := actionSpell
+	if config.ActionId != nil {
+		spell = unit.GetSpell(ProtoToActionID(*config.ActionId))
+	}
+	getAura := rotation.auraGetter(config)
+
+	var condition aplCondition
+	switch config.Type {
+	case proto.APLCondition_CurrentMana:
+		condition = compare(func(sim *Simulation) float64 { return unit.CurrentMana() })
+	case proto.APLCondition_CurrentManaPercent:
+		condition = compare(func(sim *Simulation) float64 { return unit.CurrentManaPercent() * 100 })
+	case proto.APLCondition_CurrentRage:
+		condition = compare(func(sim *Simulation) float64 { return unit.CurrentRage() })
+	case proto.APLCondition_CurrentEnergy:
+		condition = compare(func(sim *Simulation) float64 { return unit.CurrentEnergy() })
+	case proto.APLCondition_ComboPoints:
+		condition = compare(func(sim *Simulation) float64 { return float64(unit.ComboPoints()) })
+	case proto.APLCondition_AuraActive:
+		condition = func(sim *Simulation) bool {
+			aura := getAura()
+			return aura != nil && aura.IsActive()
+		}
+	case proto.APLCondition_AuraRemainingTime:
+		condition = compare(func(sim *Simulation) float64 {
+			aura := getAura()
+			if aura == nil || !aura.IsActive() {
+				return 0
+			}
+			return aura.RemainingDuration(sim).Seconds()
+		})
+	case proto.APLCondition_AuraStacks:
+		condition = compare(func(sim *Simulation) float64 {
+			aura := getAura()
+			if aura == nil || !aura.IsActive() {
+				return 0
+			}
+			return float64(aura.GetStacks())
+		})
+	case proto.APLCondition_SpellReady:
+		if spell == nil {
+			return nil, fmt.Errorf("APL condition refers to a spell not known by %s", unit.Label)
+		}
+		condition = func(sim *Simulation) bool { return spell.IsReady(sim) }
+	case proto.APLCondition_SpellCooldownRemaining:
+		if spell == nil {
+			return nil, fmt.Errorf("APL condition refers to a spell not known by %s", unit.Label)
+		}
+		condition = compare(func(sim *Simulation) float64 { return spell.TimeToReady(sim).Seconds() })
+	case proto.APLCondition_TargetHealthPercent:
+		condition = compare(func(sim *Simulation) float64 { return unit.CurrentTarget.CurrentHealthPercent() * 100 })
+	case proto.APLCondition_ExecutePhase:
+		condition = func(sim *Simulation) bool { return sim.IsExecutePhase() }
+	case proto.APLCondition_TimeRemaining:
+		condition = compare(func(sim *Simulation) float64 { return sim.GetRemainingDuration().Seconds() })
+	default:
+		return nil, fmt.Errorf("Unknown APL condition type: %s", config.Type)
+	}
+
+	if config.Not {
+		innerCondition := condition
+		condition = func(sim *Simulation) bool { return !innerCondition(sim) }
+	}
+	return condition, nil
+}
+
+// Returns a function which looks up the aura for an aura condition. Target
+// auras are looked up on the current target, which can change during the fight.
+func (rotation *APLRotation) auraGetter(config *proto.APLCondition) func() *Aura {
+	if config.ActionId == nil {
+		return func() *Aura { return nil }
+	}
+	auraID := ProtoToActionID(*config.ActionId)
+	unit := &rotation.character.Unit
+
+	if !config.OnTarget {
+		aura := unit.GetAuraByID(auraID)
+		return func() *Aura { return aura }
+	}
+
+	targetAuras := make(map[*Unit]*Aura)
+	return func() *Aura {
+		target := unit.CurrentTarget
+		aura, ok := targetAuras[target]
+		if !ok {
+			aura = target.GetAuraByID(auraID)
+			targetAuras[target] = aura
+		}
+		return aura
+	}
+}
+
+// Uses the first action in the priority list which is ready and whose
+// conditions pass. Actions which don't trigger the GCD, like most major
+// cooldowns, don't end the evaluation.
+func (rotation *APLRotation) doNextAction(sim *Simulation) {
+	character := rotation.character
+	target := character.CurrentTarget
+
+	for _, action := range rotation.actions {
+		if !character.GCD.IsReady(sim) {
+			return
+		}
+		if !action.spell.IsReady(sim) || !action.conditionsPass(sim) {
+			continue
+		}
+
+		if action.isMajorCooldown {
+			mcd := character.GetMajorCooldown(action.spell.ActionID)
+			if mcd != nil && mcd.CanActivate(sim, character) {
+				mcd.use(sim, character)
+				character.UpdateMajorCooldowns()
+			}
+		} else {
+			action.spell.Cast(sim, target)
+		}
+	}
+
+	if character.GCD.IsReady(sim) {
+		character.WaitUntil(sim, rotation.nextEvaluationAt(sim))
+	}
+}
+
+func (action *aplAction) conditionsPass(sim *Simulation) bool {
+	for _, condition := range action.conditions {
+		if !condition(sim) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns when to evaluate the priority list again, if no action was used.
+func (rotation *APLRotation) nextEvaluationAt(sim *Simulation) time.Duration {
+	nextAt := sim.CurrentTime + aplMaxWaitTime
+	for _, action := range rotation.actions {
+		if readyAt := action.spell.ReadyAt(); readyAt > sim.CurrentTime && readyAt < nextAt {
+			nextAt = readyAt
+		}
+	}
+	return nextAt
+}
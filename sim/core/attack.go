@@ -596,6 +596,14 @@ type PPMManager struct {
 	mhProcChance     float64
 	ohProcChance     float64
 	rangedProcChance float64
+
+	// If set, successful procs are recorded here.
+	metrics *ProcMetrics
+}
+
+// Records successful procs in the given metrics.
+func (ppmm *PPMManager) SetMetrics(metrics *ProcMetrics) {
+	ppmm.metrics = metrics
 }
 
 // For manually overriding proc chance.
@@ -623,17 +631,38 @@ func (ppmm *PPMManager) Proc(sim *Simulation, isMH bool, isRanged bool, label st
 
 // Returns whether the effect procced, assuming MH.
 func (ppmm *PPMManager) ProcMH(sim *Simulation, label string) bool {
-	return ppmm.mhProcChance > 0 && sim.RandomFloat(label) < ppmm.mhProcChance
+	return ppmm.roll(sim, ppmm.mhProcChance, label)
 }
 
 // Returns whether the effect procced, assuming OH.
 func (ppmm *PPMManager) ProcOH(sim *Simulation, label string) bool {
-	return ppmm.ohProcChance > 0 && sim.RandomFloat(label) < ppmm.ohProcChance
+	return ppmm.roll(sim, ppmm.ohProcChance, label)
 }
 
 // Returns whether the effect procced, assuming Ranged.
 func (ppmm *PPMManager) ProcRanged(sim *Simulation, label string) bool {
-	return ppmm.rangedProcChance > 0 && sim.RandomFloat(label) < ppmm.rangedProcChance
+	return ppmm.roll(sim, ppmm.rangedProcChance, label)
+}
+
+func (ppmm *PPMManager) roll(sim *Simulation, procChance float64, label string) bool {
+	if procChance <= 0 || sim.RandomFloat(label) >= procChance {
+		return false
+	}
+	if ppmm.metrics != nil {
+		ppmm.metrics.AddProc()
+	}
+	return true
+}
+
+// Returns the chance for a hit from the given weapon to proc the effect.
+func (ppmm *PPMManager) ProcChance(isMH bool, isRanged bool) float64 {
+	if isMH {
+		return ppmm.mhProcChance
+	} else if !isRanged {
+		return ppmm.ohProcChance
+	} else {
+		return ppmm.rangedProcChance
+	}
 }
 
 // PPMToChance converts a unit proc-per-minute into mh/oh proc chances
@@ -52,6 +52,8 @@ type Aura struct {
 	onSpellHitTakenIndex       int32 // Position of this aura's index in the onSpellHitAuras array.
 	onPeriodicDamageDealtIndex int32 // Position of this aura's index in the onPeriodicDamageAuras array.
 	onPeriodicDamageTakenIndex int32 // Position of this aura's index in the onPeriodicDamageAuras array.
+	onHealDealtIndex           int32 // Position of this aura's index in the onHealDealtAuras array.
+	onHealTakenIndex           int32 // Position of this aura's index in the onHealTakenAuras array.
 
 	// The number of stacks, or charges, of this aura. If this aura doesn't care
 	// about charges, is just 0.
@@ -75,10 +77,15 @@ type Aura struct {
 	OnSpellHitTaken       OnSpellHit       // Invoked when a spell hits and this unit is the target.
 	OnPeriodicDamageDealt OnPeriodicDamage // Invoked when a dot tick occurs and this unit is the caster.
 	OnPeriodicDamageTaken OnPeriodicDamage // Invoked when a dot tick occurs and this unit is the target.
+	OnHealDealt           OnSpellHit       // Invoked when a heal or hot tick lands and this unit is the caster.
+	OnHealTaken           OnSpellHit       // Invoked when a heal or hot tick lands and this unit is the target.
 
 	// Metrics for this aura.
 	metrics AuraMetrics
 
+	// If set, refreshes of this aura are recorded in these proc metrics.
+	procMetrics *ProcMetrics
+
 	initialized bool
 }
 
@@ -221,6 +228,8 @@ type auraTracker struct {
 	onSpellHitTakenAuras       []*Aura
 	onPeriodicDamageDealtAuras []*Aura
 	onPeriodicDamageTakenAuras []*Aura
+	onHealDealtAuras           []*Aura
+	onHealTakenAuras           []*Aura
 }
 
 func newAuraTracker() auraTracker {
@@ -232,6 +241,8 @@ func newAuraTracker() auraTracker {
 		onSpellHitTakenAuras:       make([]*Aura, 0, 16),
 		onPeriodicDamageDealtAuras: make([]*Aura, 0, 16),
 		onPeriodicDamageTakenAuras: make([]*Aura, 0, 16),
+		onHealDealtAuras:           make([]*Aura, 0, 16),
+		onHealTakenAuras:           make([]*Aura, 0, 16),
 		auras:                      make([]*Aura, 0, 16),
 		aurasByTag:                 make(map[string][]*Aura),
 	}
@@ -245,6 +256,16 @@ func (at *auraTracker) GetAura(label string) *Aura {
 	}
 	return nil
 }
+
+// Returns the first aura with the given action ID, or nil if there is none.
+func (at *auraTracker) GetAuraByID(actionID ActionID) *Aura {
+	for _, aura := range at.auras {
+		if aura.ActionID.SameAction(actionID) {
+			return aura
+		}
+	}
+	return nil
+}
 func (at *auraTracker) HasAura(label string) bool {
 	aura := at.GetAura(label)
 	return aura != nil
@@ -281,6 +302,8 @@ func (at *auraTracker) registerAura(unit *Unit, aura Aura) *Aura {
 	newAura.onSpellHitTakenIndex = Inactive
 	newAura.onPeriodicDamageDealtIndex = Inactive
 	newAura.onPeriodicDamageTakenIndex = Inactive
+	newAura.onHealDealtIndex = Inactive
+	newAura.onHealTakenIndex = Inactive
 
 	at.auras = append(at.auras, newAura)
 	if newAura.Tag != "" {
@@ -303,6 +326,8 @@ func (unit *Unit) GetOrRegisterAura(aura Aura) *Aura {
 		curAura.OnSpellHitTaken = aura.OnSpellHitTaken
 		curAura.OnPeriodicDamageDealt = aura.OnPeriodicDamageDealt
 		curAura.OnPeriodicDamageTaken = aura.OnPeriodicDamageTaken
+		curAura.OnHealDealt = aura.OnHealDealt
+		curAura.OnHealTaken = aura.OnHealTaken
 		return curAura
 	}
 }
@@ -349,6 +374,8 @@ func (at *auraTracker) reset(sim *Simulation) {
 	at.onSpellHitTakenAuras = at.onSpellHitTakenAuras[:0]
 	at.onPeriodicDamageDealtAuras = at.onPeriodicDamageDealtAuras[:0]
 	at.onPeriodicDamageTakenAuras = at.onPeriodicDamageTakenAuras[:0]
+	at.onHealDealtAuras = at.onHealDealtAuras[:0]
+	at.onHealTakenAuras = at.onHealTakenAuras[:0]
 
 	for _, resetEffect := range at.resetEffects {
 		resetEffect(sim)
@@ -378,9 +405,9 @@ restart:
 	at.minExpires = minExpires
 }
 
-func (at *auraTracker) doneIteration(sim *Simulation) {
-	// Expire all the remaining auras. Need to keep looping because sometimes
-	// expiring auras can trigger other auras.
+// Expires all active auras. Need to keep looping because sometimes expiring
+// auras can trigger other auras.
+func (at *auraTracker) expireAll(sim *Simulation) {
 	foundUnexpired := true
 	for foundUnexpired {
 		foundUnexpired = false
@@ -391,6 +418,11 @@ func (at *auraTracker) doneIteration(sim *Simulation) {
 			}
 		}
 	}
+}
+
+func (at *auraTracker) doneIteration(sim *Simulation) {
+	// Expire all the remaining auras.
+	at.expireAll(sim)
 
 	for _, aura := range at.auras {
 		aura.doneIteration(sim)
@@ -409,6 +441,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 		}
+		if aura.procMetrics != nil {
+			aura.procMetrics.AddRefresh()
+		}
 		aura.Refresh(sim)
 		return
 	}
@@ -470,9 +505,22 @@ func (aura *Aura) Activate(sim *Simulation) {
 		aura.Unit.onPeriodicDamageTakenAuras = append(aura.Unit.onPeriodicDamageTakenAuras, aura)
 	}
 
+	if aura.OnHealDealt != nil {
+		aura.onHealDealtIndex = int32(len(aura.Unit.onHealDealtAuras))
+		aura.Unit.onHealDealtAuras = append(aura.Unit.onHealDealtAuras, aura)
+	}
+
+	if aura.OnHealTaken != nil {
+		aura.onHealTakenIndex = int32(len(aura.Unit.onHealTakenAuras))
+		aura.Unit.onHealTakenAuras = append(aura.Unit.onHealTakenAuras, aura)
+	}
+
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.logCombatEvents && !aura.ActionID.IsEmptyAction() {
+		aura.logCombatEvent(sim, proto.CombatLogEvent_AuraGained)
+	}
 
 	if aura.OnGain != nil {
 		aura.OnGain(aura, sim)
@@ -520,6 +568,22 @@ func (aura *Aura) Prioritize() {
 		otherAura.onPeriodicDamageTakenIndex = aura.onPeriodicDamageTakenIndex
 		aura.onPeriodicDamageTakenIndex = 0
 	}
+
+	if aura.onHealDealtIndex > 0 {
+		otherAura := aura.Unit.onHealDealtAuras[0]
+		aura.Unit.onHealDealtAuras[0] = aura
+		aura.Unit.onHealDealtAuras[len(aura.Unit.onHealDealtAuras)-1] = otherAura
+		otherAura.onHealDealtIndex = aura.onHealDealtIndex
+		aura.onHealDealtIndex = 0
+	}
+
+	if aura.onHealTakenIndex > 0 {
+		otherAura := aura.Unit.onHealTakenAuras[0]
+		aura.Unit.onHealTakenAuras[0] = aura
+		aura.Unit.onHealTakenAuras[len(aura.Unit.onHealTakenAuras)-1] = otherAura
+		otherAura.onHealTakenIndex = aura.onHealTakenIndex
+		aura.onHealTakenIndex = 0
+	}
 }
 
 // Remove an aura by its ID
@@ -537,16 +601,17 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 	}
 
 	if !aura.ActionID.IsEmptyAction() {
-		if sim.CurrentTime > aura.expires {
-			aura.metrics.Uptime += aura.expires - aura.startTime
-		} else {
-			aura.metrics.Uptime += sim.CurrentTime - aura.startTime
-		}
+		endTime := MinDuration(sim.CurrentTime, aura.expires)
+		aura.metrics.Uptime += endTime - aura.startTime
+		aura.Unit.Metrics.timeline.addAuraUptime(&aura.metrics, aura.startTime, endTime)
 	}
 
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
 	}
+	if sim.logCombatEvents && !aura.ActionID.IsEmptyAction() {
+		aura.logCombatEvent(sim, proto.CombatLogEvent_AuraFaded)
+	}
 
 	if aura.activeIndex != Inactive {
 		removeActiveIndex := aura.activeIndex
@@ -603,6 +668,24 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		}
 		aura.onPeriodicDamageTakenIndex = Inactive
 	}
+
+	if aura.onHealDealtIndex != Inactive {
+		removeOnHealDealt := aura.onHealDealtIndex
+		aura.Unit.onHealDealtAuras = removeBySwappingToBack(aura.Unit.onHealDealtAuras, removeOnHealDealt)
+		if removeOnHealDealt < int32(len(aura.Unit.onHealDealtAuras)) {
+			aura.Unit.onHealDealtAuras[removeOnHealDealt].onHealDealtIndex = removeOnHealDealt
+		}
+		aura.onHealDealtIndex = Inactive
+	}
+
+	if aura.onHealTakenIndex != Inactive {
+		removeOnHealTaken := aura.onHealTakenIndex
+		aura.Unit.onHealTakenAuras = removeBySwappingToBack(aura.Unit.onHealTakenAuras, removeOnHealTaken)
+		if removeOnHealTaken < int32(len(aura.Unit.onHealTakenAuras)) {
+			aura.Unit.onHealTakenAuras[removeOnHealTaken].onHealTakenIndex = removeOnHealTaken
+		}
+		aura.onHealTakenIndex = Inactive
+	}
 }
 
 // Constant-time removal from slice by swapping with the last element before removing.
@@ -652,6 +735,24 @@ func (at *auraTracker) OnPeriodicDamageTaken(sim *Simulation, spell *Spell, spel
 	}
 }
 
+// Invokes the OnHeal event for all tracked Auras, for both direct heals and hot ticks.
+func (at *auraTracker) OnHealDealt(sim *Simulation, spell *Spell, spellEffect *SpellEffect) {
+	for _, aura := range at.onHealDealtAuras {
+		if !aura.active {
+			continue
+		}
+		aura.OnHealDealt(aura, sim, spell, spellEffect)
+	}
+}
+func (at *auraTracker) OnHealTaken(sim *Simulation, spell *Spell, spellEffect *SpellEffect) {
+	for _, aura := range at.onHealTakenAuras {
+		if !aura.active {
+			continue
+		}
+		aura.OnHealTaken(aura, sim, spell, spellEffect)
+	}
+}
+
 func (at *auraTracker) GetMetricsProto(numIterations int32) []*proto.AuraMetrics {
 	metrics := make([]*proto.AuraMetrics, 0, len(at.auras))
 
@@ -686,6 +787,15 @@ func (character *Character) NewTemporaryStatsAura(auraLabel string, actionID Act
 	return character.NewTemporaryStatsAuraWrapped(auraLabel, actionID, tempStats, duration, nil)
 }
 
+// Like NewTemporaryStatsAura, for the aura gained from a proc. Its refreshes
+// and stats are recorded in procMetrics; the procs themselves should be
+// recorded where they are rolled.
+func (character *Character) NewTemporaryStatsProcAura(auraLabel string, actionID ActionID, tempStats stats.Stats, duration time.Duration, procMetrics *ProcMetrics) *Aura {
+	return character.NewTemporaryStatsAuraWrapped(auraLabel, actionID, tempStats, duration, func(config *Aura) {
+		procMetrics.TrackAura(config, tempStats)
+	})
+}
+
 // Alternative that allows modifying the Aura config.
 func (character *Character) NewTemporaryStatsAuraWrapped(auraLabel string, actionID ActionID, tempStats stats.Stats, duration time.Duration, modConfig func(*Aura)) *Aura {
 	var buffs stats.Stats
@@ -805,6 +805,7 @@ func InnervateAura(character *Character, expectedBonusManaReduction float64, act
 
 			expectedBonusManaPerTick := expectedBonusManaReduction / 10
 			StartPeriodicAction(sim, PeriodicActionOptions{
+				Unit:     &character.Unit,
 				Period:   InnervateDuration / 10,
 				NumTicks: 10,
 				OnAction: func(sim *Simulation) {
@@ -893,6 +894,7 @@ func ManaTideTotemAura(character *Character, actionTag int32) *Aura {
 			if character.HasManaBar() {
 				manaPerTick := ManaTideTotemAmount(character) / 4
 				StartPeriodicAction(sim, PeriodicActionOptions{
+					Unit:     &character.Unit,
 					Period:   ManaTideTotemDuration / 4,
 					NumTicks: 4,
 					OnAction: func(sim *Simulation) {
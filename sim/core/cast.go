@@ -24,6 +24,31 @@ func (hc *Hardcast) OnExpire(sim *Simulation) {
 	hc.OnComplete(sim, hc.Target)
 }
 
+// Records a channel by this unit which ends at the given time. If the channel
+// is interrupted, e.g. by movement, onInterrupt is called to stop its effects.
+// Dots for channeled spells call this when applied.
+func (unit *Unit) StartChannel(sim *Simulation, endsAt time.Duration, onInterrupt func(*Simulation)) {
+	unit.channelEndsAt = endsAt
+	unit.channelInterrupt = onInterrupt
+}
+
+func (unit *Unit) IsChanneling(sim *Simulation) bool {
+	return unit.channelEndsAt > sim.CurrentTime
+}
+
+// Stops the current channel and its effects.
+func (unit *Unit) interruptChannel(sim *Simulation) {
+	if !unit.IsChanneling(sim) {
+		return
+	}
+	onInterrupt := unit.channelInterrupt
+	unit.channelEndsAt = 0
+	unit.channelInterrupt = nil
+	if onInterrupt != nil {
+		onInterrupt(sim)
+	}
+}
+
 // Input for constructing the CastSpell function for a spell.
 type CastConfig struct {
 	// Default cast values with all static effects applied.
@@ -67,12 +92,13 @@ type CastSuccessFunc func(*Simulation, *Unit) bool
 
 func (spell *Spell) makeCastFunc(config CastConfig, onCastComplete CastFunc) CastSuccessFunc {
 	return spell.wrapCastFuncInit(config,
-		spell.wrapCastFuncResources(config,
-			spell.wrapCastFuncHaste(config,
-				spell.wrapCastFuncGCD(config,
-					spell.wrapCastFuncCooldown(config,
-						spell.wrapCastFuncSharedCooldown(config,
-							spell.makeCastFuncWait(config, onCastComplete)))))))
+		spell.wrapCastFuncMovement(config,
+			spell.wrapCastFuncResources(config,
+				spell.wrapCastFuncHaste(config,
+					spell.wrapCastFuncGCD(config,
+						spell.wrapCastFuncCooldown(config,
+							spell.wrapCastFuncSharedCooldown(config,
+								spell.makeCastFuncWait(config, onCastComplete))))))))
 }
 
 func (spell *Spell) ApplyCostModifiers(cost float64) float64 {
@@ -106,6 +132,25 @@ func (spell *Spell) wrapCastFuncInit(config CastConfig, onCastComplete CastSucce
 	}
 }
 
+// Spells with a cast time or channel can't be cast while moving. Instead the
+// GCD is paused until the movement ends.
+func (spell *Spell) wrapCastFuncMovement(config CastConfig, onCastComplete CastSuccessFunc) CastSuccessFunc {
+	if config.DefaultCast.CastTime == 0 && config.DefaultCast.ChannelTime == 0 {
+		return onCastComplete
+	}
+
+	return func(sim *Simulation, target *Unit) bool {
+		if spell.Unit.IsMoving(sim) && (spell.CurCast.CastTime > 0 || spell.CurCast.ChannelTime > 0) {
+			if sim.Log != nil && !spell.SpellExtras.Matches(SpellExtrasNoLogs) {
+				spell.Unit.Log(sim, "Failed casting %s, can't cast while moving.", spell.ActionID)
+			}
+			spell.Unit.WaitUntil(sim, MaxDuration(spell.Unit.MovementEndsAt(sim), spell.Unit.GCD.ReadyAt()))
+			return false
+		}
+		return onCastComplete(sim, target)
+	}
+}
+
 func (spell *Spell) wrapCastFuncResources(config CastConfig, onCastComplete CastFunc) CastSuccessFunc {
 	if spell.ResourceType == 0 || config.DefaultCast.Cost == 0 {
 		if spell.ResourceType != 0 {
@@ -281,6 +326,10 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 						spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 					}
 				}
+				if sim.logCombatEvents && !spell.ActionID.IsEmptyAction() {
+					spell.logCastStart(sim, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime)
+					spell.logCastComplete(sim)
+				}
 				onCastComplete(sim, target)
 			}
 		}
@@ -294,6 +343,9 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 						spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 					}
 				}
+				if sim.logCombatEvents && !spell.ActionID.SameAction(ActionID{}) {
+					spell.logCastComplete(sim)
+				}
 				oldOnCastComplete3(sim, target)
 			}
 		}
@@ -303,6 +355,9 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s)",
 					spell.ActionID, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime)
 			}
+			if sim.logCombatEvents && !spell.SpellExtras.Matches(SpellExtrasNoLogs) {
+				spell.logCastStart(sim, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime)
+			}
 
 			// For instant-cast spells we can skip creating an aura.
 			if spell.CurCast.CastTime == 0 {
@@ -38,6 +38,12 @@ type Character struct {
 	// Provides major cooldown management behavior.
 	majorCooldownManager
 
+	// User-specified priority list which replaces the Agent's rotation, if set.
+	aplRotationConfig *proto.APLRotation
+	aplRotation       *APLRotation
+	// Set if aplRotationConfig is invalid, in which case the sim can't be run.
+	aplRotationError error
+
 	// Up reference to this Character's Party.
 	Party *Party
 
@@ -80,6 +86,7 @@ func NewCharacter(party *Party, partyIndex int, player proto.Player) Character {
 		PartyIndex: partyIndex,
 
 		majorCooldownManager: newMajorCooldownManager(player.Cooldowns),
+		aplRotationConfig:    player.AplRotation,
 	}
 
 	character.GCD = character.NewTimer()
@@ -186,6 +193,18 @@ func (character *Character) GetPet(name string) PetAgent {
 	panic(character.Name + " has no pet with name " + name)
 }
 
+// Returns the player a healer should focus on, given the heal target option.
+// Defaults to the healer itself if the option is unset or doesn't point to a
+// player in the raid.
+func (character *Character) GetHealTarget(raidTarget *proto.RaidTarget) *Character {
+	if raidTarget != nil {
+		if healTargetAgent := character.Party.Raid.GetPlayerFromRaidTarget(*raidTarget); healTargetAgent != nil {
+			return healTargetAgent.GetCharacter()
+		}
+	}
+	return character
+}
+
 func (character *Character) AddStatsDynamic(sim *Simulation, stat stats.Stats) {
 	character.Unit.AddStatsDynamic(sim, stat)
 
@@ -279,13 +298,25 @@ func (character *Character) initialize(agent Agent) {
 		Priority: ActionPriorityGCD,
 		OnAction: func(sim *Simulation) {
 			character.TryUseCooldowns(sim)
-			if character.GCD.IsReady(sim) {
+			if !character.GCD.IsReady(sim) {
+				return
+			}
+			if character.aplRotation != nil {
+				character.aplRotation.doNextAction(sim)
+			} else {
 				agent.OnGCDReady(sim)
 			}
 		},
 	}
 }
 
+// Returns whether this character's actions are chosen by an APL rotation
+// instead of its spec's rotation. Specs which also drive their rotation from
+// OnManaTick, OnAutoAttack or resource gains skip it when this is set.
+func (character *Character) HasAPLRotation() bool {
+	return character.aplRotation != nil
+}
+
 func (character *Character) Finalize() {
 	if character.Env.IsFinalized() {
 		return
@@ -296,9 +327,21 @@ func (character *Character) Finalize() {
 
 	character.PseudoStats.ParryHaste = character.PseudoStats.CanParry
 
+	if len(character.Pets) > 0 {
+		character.addOnDeath(func(sim *Simulation) {
+			for _, petAgent := range character.Pets {
+				petAgent.GetPet().Disable(sim)
+			}
+		})
+	}
+
 	character.Unit.finalize()
 
 	character.majorCooldownManager.finalize(character)
+
+	if character.aplRotationConfig != nil && len(character.aplRotationConfig.PriorityList) > 0 {
+		character.aplRotation, character.aplRotationError = newAPLRotation(character, character.aplRotationConfig)
+	}
 }
 
 func (character *Character) init(sim *Simulation, agent Agent) {
@@ -440,6 +483,7 @@ func (character *Character) GetMetricsProto(numIterations int32) *proto.UnitMetr
 	metrics := character.Metrics.ToProto(numIterations)
 	metrics.Name = character.Name
 	metrics.Auras = character.auraTracker.GetMetricsProto(numIterations)
+	metrics.Timeline = character.Metrics.timeline.ToProto(character.auraTracker.auras)
 
 	metrics.Pets = []*proto.UnitMetrics{}
 	for _, petAgent := range character.Pets {
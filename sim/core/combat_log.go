@@ -0,0 +1,109 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// Adds an event to the structured combat log, filling in its iteration and
+// timestamp. Callers should check sim.logCombatEvents first, to avoid building
+// events which won't be recorded.
+func (sim *Simulation) addCombatLogEvent(event *proto.CombatLogEvent) {
+	event.Iteration = sim.iteration
+	event.Timestamp = sim.CurrentTime.Seconds()
+	sim.combatLogEvents = append(sim.combatLogEvents, event)
+}
+
+func (unit *Unit) unitReference() *proto.UnitReference {
+	ref := &proto.UnitReference{
+		Index: unit.Index,
+	}
+	switch unit.Type {
+	case PlayerUnit:
+		ref.Type = proto.UnitReference_Player
+	case EnemyUnit:
+		ref.Type = proto.UnitReference_Target
+	case PetUnit:
+		ref.Type = proto.UnitReference_Pet
+	}
+	return ref
+}
+
+func (ho HitOutcome) ToProto() proto.HitOutcome {
+	if ho.Matches(OutcomeMiss) {
+		return proto.HitOutcome_HitOutcomeMiss
+	} else if ho.Matches(OutcomeDodge) {
+		return proto.HitOutcome_HitOutcomeDodge
+	} else if ho.Matches(OutcomeParry) {
+		return proto.HitOutcome_HitOutcomeParry
+	} else if ho.Matches(OutcomeGlance) {
+		return proto.HitOutcome_HitOutcomeGlance
+	} else if ho.Matches(OutcomeBlock) {
+		return proto.HitOutcome_HitOutcomeBlock
+	} else if ho.Matches(OutcomeCrit) {
+		return proto.HitOutcome_HitOutcomeCrit
+	} else if ho.Matches(OutcomeHit) {
+		return proto.HitOutcome_HitOutcomeHit
+	} else if ho.Matches(OutcomeCrush) {
+		return proto.HitOutcome_HitOutcomeCrush
+	} else {
+		return proto.HitOutcome_HitOutcomeUnknown
+	}
+}
+
+func (aura *Aura) logCombatEvent(sim *Simulation, eventType proto.CombatLogEvent_Type) {
+	sim.addCombatLogEvent(&proto.CombatLogEvent{
+		Type:     eventType,
+		Unit:     aura.Unit.unitReference(),
+		ActionId: aura.ActionID.ToProto(),
+	})
+}
+
+func (spell *Spell) logCastStart(sim *Simulation, cost float64, castTime time.Duration) {
+	sim.addCombatLogEvent(&proto.CombatLogEvent{
+		Type:     proto.CombatLogEvent_CastStart,
+		Unit:     spell.Unit.unitReference(),
+		ActionId: spell.ActionID.ToProto(),
+		Amount:   cost,
+		CastTime: castTime.Seconds(),
+	})
+}
+
+func (spell *Spell) logCastComplete(sim *Simulation) {
+	sim.addCombatLogEvent(&proto.CombatLogEvent{
+		Type:     proto.CombatLogEvent_CastComplete,
+		Unit:     spell.Unit.unitReference(),
+		ActionId: spell.ActionID.ToProto(),
+	})
+}
+
+// Returns the current level of a resource tracked by timelines.
+func (unit *Unit) resourceLevel(resourceType proto.ResourceType) float64 {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana:
+		return unit.CurrentMana()
+	case proto.ResourceType_ResourceTypeRage:
+		return unit.CurrentRage()
+	case proto.ResourceType_ResourceTypeEnergy:
+		return unit.CurrentEnergy()
+	}
+	return 0
+}
+
+// Adds a resource change to the unit's metrics and the combat log. This must
+// be called before the resource level is updated.
+func (unit *Unit) addResourceEvent(sim *Simulation, actionID ActionID, resourceType proto.ResourceType, gain float64, actualGain float64) {
+	unit.Metrics.AddResourceEvent(actionID, resourceType, gain, actualGain)
+	unit.Metrics.timeline.addResourceLevel(sim.CurrentTime, resourceType, unit.resourceLevel(resourceType), actualGain)
+
+	if sim.logCombatEvents {
+		sim.addCombatLogEvent(&proto.CombatLogEvent{
+			Type:         proto.CombatLogEvent_ResourceChange,
+			Unit:         unit.unitReference(),
+			ActionId:     actionID.ToProto(),
+			Amount:       actualGain,
+			ResourceType: resourceType,
+		})
+	}
+}
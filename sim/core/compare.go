@@ -0,0 +1,63 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// Z-score for a two-sided 95% confidence interval.
+const confidenceZ95 = 1.959964
+
+// The result of comparing the mean of the same metric from two sets of sims.
+type DistributionComparison struct {
+	// Difference between the means, other - baseline.
+	Difference float64
+
+	// Standard error of the difference.
+	Stderr float64
+
+	// 95% confidence interval for the true difference.
+	Ci95Lower float64
+	Ci95Upper float64
+
+	// Two-sided p-value for the hypothesis that both means are the same.
+	PValue float64
+
+	// Whether the difference is significant at the 95% level.
+	Significant bool
+}
+
+// Compares the means of two distributions with a two-sample z-test, which is
+// a close approximation of Welch's t-test at the iteration counts used by sims.
+//
+// The test assumes the two sims are independent, i.e. were run with different
+// random seeds. Sims with the same seed share much of their randomness, which
+// makes their results positively correlated. The stderr of the difference is
+// then overestimated, so the test is conservative: significant differences are
+// still real, but smaller ones may be missed.
+func CompareDistributions(baseline *proto.DistributionMetrics, other *proto.DistributionMetrics) DistributionComparison {
+	comparison := DistributionComparison{
+		Difference: other.Avg - baseline.Avg,
+		Stderr:     math.Sqrt(baseline.Stderr*baseline.Stderr + other.Stderr*other.Stderr),
+	}
+	comparison.Ci95Lower = comparison.Difference - confidenceZ95*comparison.Stderr
+	comparison.Ci95Upper = comparison.Difference + confidenceZ95*comparison.Stderr
+
+	if comparison.Stderr == 0 {
+		if comparison.Difference == 0 {
+			comparison.PValue = 1
+		}
+	} else {
+		z := math.Abs(comparison.Difference) / comparison.Stderr
+		comparison.PValue = math.Erfc(z / math.Sqrt2)
+	}
+	comparison.Significant = comparison.PValue < 0.05
+
+	return comparison
+}
+
+// Compares the raid DPS of two sim results, e.g. from two different raid setups.
+func CompareRaidSimResults(baseline *proto.RaidSimResult, other *proto.RaidSimResult) DistributionComparison {
+	return CompareDistributions(baseline.RaidMetrics.Dps, other.RaidMetrics.Dps)
+}
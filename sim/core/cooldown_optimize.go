@@ -0,0 +1,391 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Default number of candidate usage times across the fight.
+const defaultCooldownOptimizeSteps = 20
+
+// Upper bound on the number of full passes over all cooldowns during the search.
+const cooldownOptimizeMaxPasses = 3
+
+type CooldownOptimizeResult struct {
+	Cooldowns    *proto.Cooldowns
+	BaselineDps  *proto.DistributionMetrics
+	OptimizedDps *proto.DistributionMetrics
+	ErrorMsg     string
+}
+
+func (result CooldownOptimizeResult) ToProto() *proto.CooldownOptimizeResult {
+	resultProto := &proto.CooldownOptimizeResult{
+		Cooldowns:    result.Cooldowns,
+		BaselineDps:  result.BaselineDps,
+		OptimizedDps: result.OptimizedDps,
+		ErrorMsg:     result.ErrorMsg,
+	}
+	if result.BaselineDps != nil && result.OptimizedDps != nil {
+		resultProto.DpsGain = result.OptimizedDps.Avg - result.BaselineDps.Avg
+	}
+	return resultProto
+}
+
+// A cooldown whose usage times are being searched.
+type optimizedCooldown struct {
+	actionID ActionID
+
+	// Minimum time between usages.
+	cooldown time.Duration
+
+	// Best timings found so far, in seconds. Empty means the default behavior.
+	timings []float64
+}
+
+// Returns the maximum number of times the cooldown can be used in the fight.
+func (cd *optimizedCooldown) maxUsages(fightDuration time.Duration) int {
+	if cd.cooldown <= 0 {
+		return 1
+	}
+	return int(fightDuration/cd.cooldown) + 1
+}
+
+// Returns the best timings found so far, with the usage at usageIdx delayed
+// until t. Earlier usages without a timing happen as soon as possible. Since
+// each pass moves one usage at a time, several usages can end up delayed.
+func (cd *optimizedCooldown) timingsForUsageAt(usageIdx int, t time.Duration) []float64 {
+	timings := make([]float64, MaxInt(len(cd.timings), usageIdx+1))
+	copy(timings, cd.timings)
+	timings[usageIdx] = t.Seconds()
+	return timings
+}
+
+// cooldownOptimizer searches cooldown timings with presims.
+type cooldownOptimizer struct {
+	presimRequest *proto.RaidSimRequest
+
+	// The user's cooldown settings, which are kept for cooldowns which aren't
+	// being optimized.
+	baseCooldowns *proto.Cooldowns
+
+	cooldowns []*optimizedCooldown
+
+	fightDuration time.Duration
+	timingStep    time.Duration
+
+	// Times the cooldowns may be aligned with, e.g. Bloodlust or execute phase.
+	anchors []time.Duration
+
+	// Cached presim results, keyed by timingsKey.
+	results    map[string]float64
+	resultsMut sync.Mutex
+}
+
+// Whether the action is a raid cooldown used as an alignment point.
+func isCooldownOptimizeAnchor(actionID ActionID) bool {
+	return actionID.SpellID == 2825 ||
+		actionID.SameActionIgnoreTag(DrumsOfBattleActionID) ||
+		actionID.SameActionIgnoreTag(DrumsOfRestorationActionID) ||
+		actionID.SameActionIgnoreTag(DrumsOfWarActionID)
+}
+
+func timingsKey(allTimings [][]float64) string {
+	var sb strings.Builder
+	for _, timings := range allTimings {
+		for _, timing := range timings {
+			fmt.Fprintf(&sb, "%0.3f,", timing)
+		}
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+// Returns the cooldown settings with the given timings for each optimized cooldown.
+func (copt *cooldownOptimizer) makeCooldowns(allTimings [][]float64) *proto.Cooldowns {
+	cooldowns := &proto.Cooldowns{}
+	for _, config := range copt.baseCooldowns.Cooldowns {
+		optimized := false
+		for _, cd := range copt.cooldowns {
+			if config.Id != nil && ProtoToActionID(*config.Id).SameAction(cd.actionID) {
+				optimized = true
+			}
+		}
+		if !optimized {
+			cooldowns.Cooldowns = append(cooldowns.Cooldowns, config)
+		}
+	}
+
+	for i, cd := range copt.cooldowns {
+		if len(allTimings[i]) > 0 {
+			cooldowns.Cooldowns = append(cooldowns.Cooldowns, &proto.Cooldown{
+				Id:      cd.actionID.ToProto(),
+				Timings: allTimings[i],
+			})
+		}
+	}
+	return cooldowns
+}
+
+func (copt *cooldownOptimizer) currentTimings() [][]float64 {
+	allTimings := make([][]float64, len(copt.cooldowns))
+	for i, cd := range copt.cooldowns {
+		allTimings[i] = cd.timings
+	}
+	return allTimings
+}
+
+// Returns the average presim DPS with the given timings, or 0 if the presim
+// failed or was cancelled.
+func (copt *cooldownOptimizer) evaluate(ctx context.Context, allTimings [][]float64) float64 {
+	key := timingsKey(allTimings)
+	copt.resultsMut.Lock()
+	dps, ok := copt.results[key]
+	copt.resultsMut.Unlock()
+	if ok {
+		return dps
+	}
+
+	simRequest := googleProto.Clone(copt.presimRequest).(*proto.RaidSimRequest)
+	simRequest.Raid.Parties[0].Players[0].Cooldowns = copt.makeCooldowns(allTimings)
+	result := RunSimWithContext(ctx, *simRequest, nil)
+	if result.ErrorMsg != "" {
+		return 0
+	}
+	dps = result.RaidMetrics.Parties[0].Players[0].Dps.Avg
+
+	copt.resultsMut.Lock()
+	copt.results[key] = dps
+	copt.resultsMut.Unlock()
+	return dps
+}
+
+// Finds the times at which Bloodlust and Drums are gained in the first iteration.
+func (copt *cooldownOptimizer) findAnchors(ctx context.Context, playerIndex int32) {
+	anchorRequest := googleProto.Clone(copt.presimRequest).(*proto.RaidSimRequest)
+	anchorRequest.SimOptions.Iterations = 1
+	anchorRequest.SimOptions.Concurrency = 0
+	anchorRequest.SimOptions.CombatLog = true
+	result := RunSimWithContext(ctx, *anchorRequest, nil)
+
+	for _, event := range result.CombatLog {
+		if event.Type != proto.CombatLogEvent_AuraGained || event.Unit.GetType() != proto.UnitReference_Player || event.Unit.GetIndex() != playerIndex {
+			continue
+		}
+		if event.ActionId != nil && isCooldownOptimizeAnchor(ProtoToActionID(*event.ActionId)) {
+			copt.anchors = append(copt.anchors, DurationFromSeconds(event.Timestamp).Round(time.Millisecond))
+		}
+	}
+}
+
+// Returns the candidate timings for a cooldown: each of its usages moved to each
+// time on the step grid, each anchor, and the first usage of each other
+// optimized cooldown.
+func (copt *cooldownOptimizer) candidateTimings(cdIdx int) [][]float64 {
+	cd := copt.cooldowns[cdIdx]
+
+	times := append([]time.Duration{}, copt.anchors...)
+	for t := time.Duration(0); t < copt.fightDuration; t += copt.timingStep {
+		times = append(times, t)
+	}
+	for i, other := range copt.cooldowns {
+		if i != cdIdx && len(other.timings) > 0 {
+			times = append(times, DurationFromSeconds(other.timings[0]))
+		}
+	}
+
+	candidates := [][]float64{{}}
+	seen := map[string]bool{"": true}
+	for usageIdx := 0; usageIdx < cd.maxUsages(copt.fightDuration); usageIdx++ {
+		for _, t := range times {
+			// The cooldown can't come up for this usage before then anyway.
+			if t < cd.cooldown*time.Duration(usageIdx) || t >= copt.fightDuration {
+				continue
+			}
+			timings := cd.timingsForUsageAt(usageIdx, t)
+			key := timingsKey([][]float64{timings})
+			if !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, timings)
+			}
+		}
+	}
+	return candidates
+}
+
+// CalcCooldownOptimize searches for the cooldown usage times which give a player
+// the most DPS, by changing one cooldown at a time and comparing presims. The
+// best timings are then confirmed against the original timings with full sims.
+// The search stops early if ctx is cancelled, and the result then only has an
+// error message.
+func CalcCooldownOptimize(ctx context.Context, request proto.CooldownOptimizeRequest, progress chan *proto.ProgressMetrics) CooldownOptimizeResult {
+	if request.Player == nil {
+		return CooldownOptimizeResult{ErrorMsg: "Missing player"}
+	}
+	if request.SimOptions == nil {
+		return CooldownOptimizeResult{ErrorMsg: "Missing sim options"}
+	}
+	if request.Encounter == nil || request.Encounter.Duration <= 0 {
+		return CooldownOptimizeResult{ErrorMsg: "Missing encounter"}
+	}
+
+	raidProto := SinglePlayerRaidProto(request.Player, request.PartyBuffs, request.RaidBuffs, request.Debuffs)
+	raidProto.Tanks = request.Tanks
+	if err := validateRaidTalents(raidProto); err != nil {
+		return CooldownOptimizeResult{ErrorMsg: err.Error()}
+	}
+	baseRequest := proto.RaidSimRequest{
+		Raid:       raidProto,
+		Encounter:  request.Encounter,
+		SimOptions: request.SimOptions,
+	}
+
+	copt := &cooldownOptimizer{
+		presimRequest: newPresimRequest(baseRequest),
+		baseCooldowns: &proto.Cooldowns{},
+		fightDuration: DurationFromSeconds(request.Encounter.Duration),
+		results:       make(map[string]float64),
+	}
+	if request.Player.Cooldowns != nil {
+		copt.baseCooldowns = request.Player.Cooldowns
+	}
+
+	copt.timingStep = DurationFromSeconds(request.TimingStep)
+	if copt.timingStep <= 0 {
+		copt.timingStep = copt.fightDuration / defaultCooldownOptimizeSteps
+	}
+	copt.timingStep = MaxDuration(copt.timingStep, time.Second)
+
+	// Find the cooldowns to optimize.
+	env := NewEnvironment(*googleProto.Clone(raidProto).(*proto.Raid), *request.Encounter)
+	if err := env.validate(); err != nil {
+		return CooldownOptimizeResult{ErrorMsg: err.Error()}
+	}
+	character := env.Raid.Parties[0].Players[0].GetCharacter()
+	addCooldown := func(mcd MajorCooldown) {
+		cd := &optimizedCooldown{
+			actionID: mcd.Spell.ActionID,
+			cooldown: MaxDuration(mcd.Spell.CD.Duration, mcd.Spell.SharedCD.Duration),
+		}
+		for _, config := range copt.baseCooldowns.Cooldowns {
+			if config.Id != nil && ProtoToActionID(*config.Id).SameAction(cd.actionID) {
+				cd.timings = config.Timings
+			}
+		}
+		copt.cooldowns = append(copt.cooldowns, cd)
+	}
+	if len(request.CooldownIds) > 0 {
+		for _, id := range request.CooldownIds {
+			actionID := ProtoToActionID(*id)
+			mcd := character.GetInitialMajorCooldown(actionID)
+			if mcd.Spell == nil {
+				return CooldownOptimizeResult{ErrorMsg: fmt.Sprintf("No cooldown with id: %s", actionID)}
+			}
+			addCooldown(mcd)
+		}
+	} else {
+		for _, mcd := range character.initialMajorCooldowns {
+			if mcd.Type == CooldownTypeDPS && !isCooldownOptimizeAnchor(mcd.Spell.ActionID) {
+				addCooldown(mcd)
+			}
+		}
+	}
+	if len(copt.cooldowns) == 0 {
+		return CooldownOptimizeResult{ErrorMsg: "No cooldowns to optimize"}
+	}
+
+	copt.anchors = []time.Duration{0}
+	if request.Encounter.ExecuteProportion > 0 {
+		copt.anchors = append(copt.anchors, DurationFromSeconds(request.Encounter.Duration*(1-request.Encounter.ExecuteProportion)))
+	}
+	copt.findAnchors(ctx, character.Index)
+
+	// Each pass tries every candidate for every cooldown, keeping the best.
+	numCandidates := 0
+	for i := range copt.cooldowns {
+		numCandidates += len(copt.candidateTimings(i))
+	}
+	var simsCompleted int32
+	simsTotal := int32(1 + numCandidates*cooldownOptimizeMaxPasses + 2)
+	bestDps := copt.evaluate(ctx, copt.currentTimings())
+	reportProgress := func() {
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				CompletedSims:       simsCompleted,
+				TotalSims:           simsTotal,
+				CompletedIterations: simsCompleted * numPresimIterations,
+				TotalIterations:     simsTotal * numPresimIterations,
+				Dps:                 bestDps,
+			}
+		}
+	}
+	simsCompleted++
+	reportProgress()
+
+	for pass := 0; pass < cooldownOptimizeMaxPasses; pass++ {
+		improved := false
+		for i, cd := range copt.cooldowns {
+			candidates := copt.candidateTimings(i)
+			candidateDps := make([]float64, len(candidates))
+
+			runInWorkerPool(len(candidates), func(c int) {
+				allTimings := copt.currentTimings()
+				allTimings[i] = candidates[c]
+				candidateDps[c] = copt.evaluate(ctx, allTimings)
+			})
+			if ctx.Err() != nil {
+				return CooldownOptimizeResult{ErrorMsg: cancelledSimResult().ErrorMsg}
+			}
+
+			for c, dps := range candidateDps {
+				if dps > bestDps {
+					bestDps = dps
+					cd.timings = candidates[c]
+					improved = true
+				}
+			}
+			simsCompleted += int32(len(candidates))
+			reportProgress()
+		}
+		if !improved {
+			break
+		}
+	}
+	simsTotal = simsCompleted + 2
+
+	// Confirm with full sims, using the requested options.
+	confirm := func(cooldowns *proto.Cooldowns) *proto.RaidSimResult {
+		simRequest := googleProto.Clone(&baseRequest).(*proto.RaidSimRequest)
+		simRequest.Raid.Parties[0].Players[0].Cooldowns = cooldowns
+		result := RunSimWithContext(ctx, *simRequest, nil)
+		simsCompleted++
+		reportProgress()
+		return result
+	}
+
+	optimizedCooldowns := copt.makeCooldowns(copt.currentTimings())
+	baselineResult := confirm(copt.baseCooldowns)
+	if baselineResult.ErrorMsg != "" {
+		return CooldownOptimizeResult{ErrorMsg: baselineResult.ErrorMsg}
+	}
+	optimizedResult := confirm(optimizedCooldowns)
+	if optimizedResult.ErrorMsg != "" {
+		return CooldownOptimizeResult{ErrorMsg: optimizedResult.ErrorMsg}
+	}
+	result := CooldownOptimizeResult{
+		Cooldowns:    optimizedCooldowns,
+		BaselineDps:  baselineResult.RaidMetrics.Parties[0].Players[0].Dps,
+		OptimizedDps: optimizedResult.RaidMetrics.Parties[0].Players[0].Dps,
+	}
+	// The presims can favor timings which don't hold up in the full sims.
+	if result.OptimizedDps.Avg <= result.BaselineDps.Avg {
+		result.Cooldowns = copt.baseCooldowns
+		result.OptimizedDps = result.BaselineDps
+	}
+	return result
+}
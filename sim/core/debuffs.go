@@ -32,7 +32,9 @@ func applyDebuffEffects(target *Unit, debuffs proto.Debuffs) {
 		MakePermanent(CurseOfElementsAura(target, GetTristateValueInt32(debuffs.CurseOfElements, 0, 3)))
 	}
 
-	if debuffs.IsbUptime > 0.0 {
+	if debuffs.IsbWarlocks > 0 {
+		estimatedImprovedShadowBolt(target, debuffs)
+	} else if debuffs.IsbUptime > 0.0 {
 		uptime := MinFloat(1.0, debuffs.IsbUptime)
 		isbAura := MakePermanent(ImprovedShadowBoltAura(target, 5, uptime))
 		if uptime != 1.0 {
@@ -295,6 +297,7 @@ func ImprovedShadowBoltAura(target *Unit, points int32, uptime float64) *Aura {
 			if !spellEffect.Landed() || spellEffect.Damage == 0 || !spellEffect.ProcMask.Matches(ProcMaskSpellDamage) {
 				return
 			}
+			aura.metrics.ChargesConsumed++
 			aura.RemoveStack(sim)
 		}
 	}
@@ -39,6 +39,10 @@ func (dot *Dot) Apply(sim *Simulation) {
 		dot.Aura.Duration = dot.tickPeriod * time.Duration(dot.NumberOfTicks)
 	}
 	dot.Aura.Activate(sim)
+
+	if dot.Spell.SpellExtras.Matches(SpellExtrasChanneled) {
+		dot.Spell.Unit.StartChannel(sim, sim.CurrentTime+dot.Aura.Duration, dot.Cancel)
+	}
 }
 
 func (dot *Dot) Cancel(sim *Simulation) {
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"time"
+)
+
+// Iterations of health-based encounters never last longer than this. Every
+// boss has enraged long before then, so the raid would have wiped anyway.
+const maxHealthBasedDuration = time.Minute * 20
+
+// How much to extend an iteration by when its estimated duration has passed
+// but targets are still alive.
+const healthBasedDurationExtension = time.Second * 10
+
+func (encounter *Encounter) initializeHealth() {
+	if !encounter.UseHealth {
+		return
+	}
+
+	primaryTarget := encounter.Targets[0]
+	primaryTarget.addOnHealthLost(func(sim *Simulation) {
+		if !sim.executePhase && primaryTarget.CurrentHealthPercent() < 0.2 {
+			sim.beginExecutePhase()
+		}
+	})
+}
+
+// Removes a dead target from the fight, and ends the iteration if it was the
+// last one alive.
+func (encounter *Encounter) onTargetDeath(sim *Simulation, target *Target) {
+	target.Despawn(sim)
+
+	if !encounter.anyTargetAlive() {
+		if sim.Log != nil {
+			sim.Log("All targets have died.")
+		}
+		sim.Duration = sim.CurrentTime
+	}
+}
+
+// Whether any target still needs to be killed. Targets spawned by encounter
+// phases only count while they are in the fight.
+func (encounter *Encounter) anyTargetAlive() bool {
+	for _, target := range encounter.Targets {
+		if target.canDie && !target.isDead && (target.active || !target.spawnedByPhase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extends the current iteration of a health-based encounter so that an action
+// at nextActionAt can still happen. Returns false if the iteration should end
+// instead, because all targets are dead or the duration limit was reached.
+func (sim *Simulation) extendDuration(nextActionAt time.Duration) bool {
+	if !sim.Encounter.UseHealth || nextActionAt > maxHealthBasedDuration || !sim.Encounter.anyTargetAlive() {
+		return false
+	}
+
+	sim.Duration = MinDuration(maxHealthBasedDuration, MaxDuration(nextActionAt, sim.Duration+healthBasedDurationExtension))
+	return true
+}
+
+// Records the fight length of a health-based iteration, and updates the
+// estimated duration used by the next iterations to the average so far.
+// Iterations which reached maxHealthBasedDuration with targets still alive
+// aren't kills, so they're left out.
+func (encounter *Encounter) doneHealthIteration(sim *Simulation) {
+	if encounter.anyTargetAlive() {
+		return
+	}
+
+	encounter.killTimeMetrics.addSample(sim.Duration.Seconds(), 1)
+	encounter.numKillTimeSamples++
+
+	sim.BaseDuration += (sim.Duration - sim.BaseDuration) / time.Duration(encounter.numKillTimeSamples)
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// A period of the fight during which some players move, as configured by a
+// proto.MovementEvent.
+type movementEvent struct {
+	startTime      time.Duration
+	duration       time.Duration
+	repeatInterval time.Duration
+
+	role        proto.MovementEvent_Role
+	raidIndices []int32
+
+	// Players and pets who move, resolved once the raid is constructed.
+	units []*Unit
+	pets  []*Pet
+}
+
+func newMovementEvent(config *proto.MovementEvent) *movementEvent {
+	return &movementEvent{
+		startTime:      DurationFromSeconds(config.StartTime),
+		duration:       DurationFromSeconds(config.Duration),
+		repeatInterval: DurationFromSeconds(config.RepeatInterval),
+		role:           config.Role,
+		raidIndices:    config.RaidIndices,
+	}
+}
+
+// Whether the given player or pet moves during this event. Pets are selected by
+// their owner's raid index.
+func (event *movementEvent) appliesTo(character *Character, raidIndex int32) bool {
+	isMelee := character.AutoAttacks.IsEnabled() && character.AutoAttacks.AutoSwingMelee
+	if event.role == proto.MovementEvent_Melee && !isMelee {
+		return false
+	}
+	if event.role == proto.MovementEvent_Ranged && isMelee {
+		return false
+	}
+
+	if len(event.raidIndices) == 0 {
+		return true
+	}
+	for _, eventRaidIndex := range event.raidIndices {
+		if eventRaidIndex == raidIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the next time at or after t when this event begins, or NeverExpires
+// if it doesn't begin again.
+func (event *movementEvent) nextStartAt(t time.Duration) time.Duration {
+	if t <= event.startTime {
+		return event.startTime
+	}
+	if event.repeatInterval <= 0 {
+		return NeverExpires
+	}
+	numRepeats := (t - event.startTime + event.repeatInterval - 1) / event.repeatInterval
+	return event.startTime + numRepeats*event.repeatInterval
+}
+
+func (event *movementEvent) reset(sim *Simulation) {
+	if len(event.units) == 0 && len(event.pets) == 0 {
+		return
+	}
+
+	pa := &PendingAction{
+		NextActionAt: event.startTime,
+	}
+	pa.OnAction = func(sim *Simulation) {
+		for _, unit := range event.units {
+			unit.startMoving(sim, sim.CurrentTime+event.duration)
+		}
+		for _, pet := range event.pets {
+			if pet.IsEnabled() {
+				pet.startMoving(sim, sim.CurrentTime+event.duration)
+			}
+		}
+		if event.repeatInterval > 0 {
+			pa.NextActionAt = sim.CurrentTime + event.repeatInterval
+			sim.AddPendingAction(pa)
+		}
+	}
+	sim.AddPendingAction(pa)
+}
+
+func (encounter *Encounter) initializeMovement(raid *Raid) {
+	for _, event := range encounter.movements {
+		if event.duration <= 0 {
+			continue
+		}
+		for _, party := range raid.Parties {
+			for _, player := range party.Players {
+				character := player.GetCharacter()
+				if event.appliesTo(character, character.Index) {
+					event.units = append(event.units, &character.Unit)
+					character.movements = append(character.movements, event)
+				}
+				for _, petAgent := range character.Pets {
+					pet := petAgent.GetPet()
+					if event.appliesTo(&pet.Character, character.Index) {
+						event.pets = append(event.pets, pet)
+						pet.movements = append(pet.movements, event)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (encounter *Encounter) resetMovement(sim *Simulation) {
+	for _, event := range encounter.movements {
+		event.reset(sim)
+	}
+}
+
+// Begins moving until the given time. The current hardcast or channel is
+// interrupted, and melee swings are paused since the unit is out of range.
+func (unit *Unit) startMoving(sim *Simulation, movingUntil time.Duration) {
+	if unit.IsDead() {
+		return
+	}
+	unit.movingUntil = MaxDuration(unit.movingUntil, movingUntil)
+
+	if sim.Log != nil {
+		unit.Log(sim, "Moving for %s.", unit.movingUntil-sim.CurrentTime)
+	}
+
+	if unit.Hardcast.Expires > sim.CurrentTime {
+		if sim.Log != nil {
+			unit.Log(sim, "Cast interrupted by movement.")
+		}
+		if unit.hardcastAction != nil {
+			unit.hardcastAction.Cancel(sim)
+			unit.hardcastAction = nil
+		}
+		unit.Hardcast = Hardcast{}
+
+		// The GCD was set to the end of the cast, so free it up to allow instant
+		// casts while moving.
+		unit.SetGCDTimer(sim, sim.CurrentTime)
+	}
+
+	if unit.IsChanneling(sim) {
+		if sim.Log != nil {
+			unit.Log(sim, "Channel interrupted by movement.")
+		}
+		unit.interruptChannel(sim)
+
+		// Like a hardcast, the GCD was set to the end of the channel.
+		unit.SetGCDTimer(sim, sim.CurrentTime)
+	}
+
+	if unit.AutoAttacks.IsEnabled() && unit.AutoAttacks.AutoSwingMelee && !unit.AutoAttacks.RangedSwingInProgress {
+		unit.AutoAttacks.DelayAllUntil(sim, unit.movingUntil)
+	}
+}
+
+// Whether this unit is currently moving. Spells with a cast time can't be cast
+// while moving.
+func (unit *Unit) IsMoving(sim *Simulation) bool {
+	return unit.movingUntil > sim.CurrentTime
+}
+
+// Returns the time at which the current movement ends, or the current time if
+// the unit isn't moving.
+func (unit *Unit) MovementEndsAt(sim *Simulation) time.Duration {
+	return MaxDuration(unit.movingUntil, sim.CurrentTime)
+}
+
+// Returns the time until this unit next has to move, 0 if it is currently
+// moving, or NeverExpires if it won't move again.
+func (unit *Unit) TimeUntilNextMovement(sim *Simulation) time.Duration {
+	if unit.IsMoving(sim) {
+		return 0
+	}
+
+	nextMovementAt := NeverExpires
+	for _, event := range unit.movements {
+		nextMovementAt = MinDuration(nextMovementAt, event.nextStartAt(sim.CurrentTime))
+	}
+	if nextMovementAt == NeverExpires {
+		return NeverExpires
+	}
+	return nextMovementAt - sim.CurrentTime
+}
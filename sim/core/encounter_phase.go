@@ -0,0 +1,319 @@
+package core
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// A phase of the encounter, as configured by a proto.EncounterPhase.
+type encounterPhase struct {
+	index int
+
+	startTime      time.Duration
+	repeatInterval time.Duration
+	duration       time.Duration
+
+	// For health-based phases, the target whose health triggers the phase.
+	healthTarget  *Target
+	healthPercent float64
+
+	spawnTargets   []*Target
+	despawnTargets []*Target
+	immuneTargets  []*Target
+
+	damageTakenMultiplier float64
+	damageTakenTargets    []*Target
+	damageTakenAuras      []*Aura
+
+	// Whether a health-based phase has begun in the current iteration.
+	triggered bool
+}
+
+func (encounter *Encounter) newPhase(index int, config *proto.EncounterPhase) *encounterPhase {
+	getTargets := func(indices []int32) []*Target {
+		var targets []*Target
+		for _, targetIndex := range indices {
+			if targetIndex >= 0 && int(targetIndex) < len(encounter.Targets) {
+				targets = append(targets, encounter.Targets[targetIndex])
+			}
+		}
+		return targets
+	}
+
+	phase := &encounterPhase{
+		index:                 index,
+		startTime:             DurationFromSeconds(config.StartTime),
+		repeatInterval:        DurationFromSeconds(config.RepeatInterval),
+		duration:              DurationFromSeconds(config.Duration),
+		healthPercent:         config.HealthPercent,
+		spawnTargets:          getTargets(config.SpawnTargets),
+		despawnTargets:        getTargets(config.DespawnTargets),
+		immuneTargets:         getTargets(config.ImmuneTargets),
+		damageTakenMultiplier: config.DamageTakenMultiplier,
+		damageTakenTargets:    getTargets(config.DamageTakenTargets),
+	}
+	if phase.healthPercent > 0 {
+		if healthTargets := getTargets([]int32{config.HealthTargetIndex}); len(healthTargets) > 0 {
+			phase.healthTarget = healthTargets[0]
+		}
+	}
+
+	for _, target := range phase.spawnTargets {
+		target.spawnedByPhase = true
+	}
+	return phase
+}
+
+// Registers the auras used by the phase. Called once targets are initialized.
+func (phase *encounterPhase) initialize() {
+	if phase.damageTakenMultiplier != 0 {
+		multiplier := phase.damageTakenMultiplier
+		for _, target := range phase.damageTakenTargets {
+			phase.damageTakenAuras = append(phase.damageTakenAuras, target.RegisterAura(Aura{
+				Label:    "Phase " + strconv.Itoa(phase.index+1) + " Damage Taken",
+				Duration: NeverExpires,
+				OnGain: func(aura *Aura, sim *Simulation) {
+					aura.Unit.PseudoStats.DamageTakenMultiplier *= multiplier
+				},
+				OnExpire: func(aura *Aura, sim *Simulation) {
+					aura.Unit.PseudoStats.DamageTakenMultiplier /= multiplier
+				},
+			}))
+		}
+	}
+	for _, target := range phase.immuneTargets {
+		target.registerImmuneAura()
+	}
+}
+
+func (phase *encounterPhase) reset(sim *Simulation) {
+	phase.triggered = false
+	if phase.healthTarget != nil {
+		return
+	}
+
+	pa := &PendingAction{
+		NextActionAt: phase.startTime,
+	}
+	pa.OnAction = func(sim *Simulation) {
+		phase.begin(sim)
+		if phase.repeatInterval > 0 {
+			pa.NextActionAt = sim.CurrentTime + phase.repeatInterval
+			sim.AddPendingAction(pa)
+		}
+	}
+	sim.AddPendingAction(pa)
+}
+
+// Begins a health-based phase if its target has dropped below the threshold.
+func (phase *encounterPhase) checkHealth(sim *Simulation) {
+	if phase.triggered || phase.healthTarget.CurrentHealthPercent()*100 >= phase.healthPercent {
+		return
+	}
+	phase.triggered = true
+	phase.begin(sim)
+}
+
+func (phase *encounterPhase) begin(sim *Simulation) {
+	if sim.Log != nil {
+		sim.Log("Encounter phase %d begins.", phase.index+1)
+	}
+
+	for _, target := range phase.despawnTargets {
+		target.Despawn(sim)
+	}
+	for _, target := range phase.spawnTargets {
+		target.Spawn(sim)
+	}
+
+	auraDuration := NeverExpires
+	if phase.duration > 0 {
+		auraDuration = phase.duration
+	}
+	for _, target := range phase.immuneTargets {
+		target.immuneAura.Duration = auraDuration
+		target.immuneAura.Activate(sim)
+	}
+	for _, aura := range phase.damageTakenAuras {
+		aura.Duration = auraDuration
+		aura.Activate(sim)
+	}
+
+	if phase.duration > 0 && (len(phase.spawnTargets) > 0 || len(phase.despawnTargets) > 0) {
+		sim.AddPendingAction(&PendingAction{
+			NextActionAt: sim.CurrentTime + phase.duration,
+			OnAction: func(sim *Simulation) {
+				for _, target := range phase.spawnTargets {
+					target.Despawn(sim)
+				}
+				for _, target := range phase.despawnTargets {
+					target.Spawn(sim)
+				}
+			},
+		})
+	}
+}
+
+func (target *Target) registerImmuneAura() {
+	if target.immuneAura != nil {
+		return
+	}
+	target.immuneAura = target.RegisterAura(Aura{
+		Label:    "Phase Immunity",
+		Duration: NeverExpires,
+		OnGain: func(aura *Aura, sim *Simulation) {
+			target.updateImmunity()
+		},
+		OnExpire: func(aura *Aura, sim *Simulation) {
+			target.updateImmunity()
+		},
+	})
+}
+
+func (target *Target) updateImmunity() {
+	target.PseudoStats.Immune = !target.active || (target.immuneAura != nil && target.immuneAura.IsActive())
+}
+
+// Whether this target is currently in the fight. Targets can be spawned and
+// despawned by encounter phases.
+func (target *Target) IsActive() bool {
+	return target.active
+}
+
+// Brings this target into the fight. Dead targets can't be spawned.
+func (target *Target) Spawn(sim *Simulation) {
+	if target.active || target.isDead {
+		return
+	}
+	target.active = true
+	target.activeSince = sim.CurrentTime
+	target.updateImmunity()
+
+	if sim.Log != nil {
+		target.Log(sim, "Spawned.")
+	}
+
+	if target.AutoAttacks.IsEnabled() {
+		target.AutoAttacks.EnableAutoSwing(sim)
+	}
+	if len(target.abilities) > 0 {
+		target.SetGCDTimer(sim, sim.CurrentTime)
+	}
+
+	for _, callback := range sim.targetSpawnCallbacks {
+		callback(sim, target)
+	}
+}
+
+// Removes this target from the fight. Units targeting it switch to the first
+// active target, if there is one.
+func (target *Target) Despawn(sim *Simulation) {
+	if !target.active {
+		return
+	}
+	target.active = false
+	target.activeTime += sim.CurrentTime - target.activeSince
+	target.updateImmunity()
+
+	if sim.Log != nil {
+		target.Log(sim, "Despawned.")
+	}
+
+	target.stopActions(sim)
+
+	if newTarget := sim.Encounter.firstActiveTarget(); newTarget != nil {
+		for _, unit := range sim.Raid.AllUnits {
+			if unit.CurrentTarget == &target.Unit {
+				unit.CurrentTarget = &newTarget.Unit
+			}
+		}
+	}
+
+	for _, callback := range sim.targetDespawnCallbacks {
+		callback(sim, target)
+	}
+}
+
+// Cancels the auto attacks and abilities of this target.
+func (target *Target) stopActions(sim *Simulation) {
+	target.AutoAttacks.CancelAutoSwing(sim)
+	if len(target.abilities) > 0 {
+		target.CancelGCDTimer(sim)
+	}
+	if target.hardcastAction != nil {
+		target.hardcastAction.Cancel(sim)
+		target.hardcastAction = nil
+	}
+	target.Hardcast = Hardcast{}
+}
+
+// Returns the number of seconds this target was active in the current iteration.
+func (target *Target) activeSeconds(sim *Simulation) float64 {
+	activeTime := target.activeTime
+	if target.active {
+		activeTime += sim.Duration - target.activeSince
+	}
+	return activeTime.Seconds()
+}
+
+func (encounter *Encounter) firstActiveTarget() *Target {
+	for _, target := range encounter.Targets {
+		if target.active {
+			return target
+		}
+	}
+	return nil
+}
+
+// Returns the targets which are currently in the fight.
+func (encounter *Encounter) ActiveTargets() []*Target {
+	var targets []*Target
+	for _, target := range encounter.Targets {
+		if target.active {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func (encounter *Encounter) initializePhases() {
+	for _, phase := range encounter.phases {
+		phase.initialize()
+	}
+
+	for _, target := range encounter.Targets {
+		var healthPhases []*encounterPhase
+		for _, phase := range encounter.phases {
+			if phase.healthTarget == target {
+				healthPhases = append(healthPhases, phase)
+			}
+		}
+		if len(healthPhases) > 0 {
+			target.addOnHealthLost(func(sim *Simulation) {
+				for _, phase := range healthPhases {
+					phase.checkHealth(sim)
+				}
+			})
+		}
+	}
+}
+
+func (encounter *Encounter) resetPhases(sim *Simulation) {
+	for _, phase := range encounter.phases {
+		phase.reset(sim)
+	}
+}
+
+// Registers a callback which is invoked whenever a target spawns. Like
+// RegisterExecutePhaseCallback, this must be called again every iteration.
+func (sim *Simulation) RegisterTargetSpawnCallback(callback func(*Simulation, *Target)) {
+	sim.targetSpawnCallbacks = append(sim.targetSpawnCallbacks, callback)
+}
+
+// Registers a callback which is invoked whenever a target despawns. Like
+// RegisterExecutePhaseCallback, this must be called again every iteration.
+func (sim *Simulation) RegisterTargetDespawnCallback(callback func(*Simulation, *Target)) {
+	sim.targetDespawnCallbacks = append(sim.targetDespawnCallbacks, callback)
+}
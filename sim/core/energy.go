@@ -56,7 +56,7 @@ func (eb *energyBar) addEnergyInternal(sim *Simulation, amount float64, actionID
 	}
 
 	newEnergy := MinFloat(eb.currentEnergy+amount, eb.maxEnergy)
-	eb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeEnergy, amount, newEnergy-eb.currentEnergy)
+	eb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeEnergy, amount, newEnergy-eb.currentEnergy)
 
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f).", amount, actionID, eb.currentEnergy, newEnergy)
@@ -75,7 +75,7 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, actionID Actio
 	}
 
 	newEnergy := eb.currentEnergy - amount
-	eb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeEnergy, -amount, -amount)
+	eb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeEnergy, -amount, -amount)
 
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f).", amount, actionID, eb.currentEnergy, newEnergy)
@@ -90,7 +90,7 @@ func (eb *energyBar) ComboPoints() int32 {
 
 func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, actionID ActionID) {
 	newComboPoints := MinInt32(eb.comboPoints+pointsToAdd, 5)
-	eb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeComboPoints, float64(pointsToAdd), float64(newComboPoints-eb.comboPoints))
+	eb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeComboPoints, float64(pointsToAdd), float64(newComboPoints-eb.comboPoints))
 
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %d combo points from %s (%d --> %d)", pointsToAdd, actionID, eb.comboPoints, newComboPoints)
@@ -114,7 +114,7 @@ func (eb *energyBar) SpendComboPoints(sim *Simulation, actionID ActionID) {
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %d combo points from %s (%d --> %d).", eb.comboPoints, actionID, eb.comboPoints, 0)
 	}
-	eb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeComboPoints, float64(-eb.comboPoints), float64(-eb.comboPoints))
+	eb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeComboPoints, float64(-eb.comboPoints), float64(-eb.comboPoints))
 	eb.comboPoints = 0
 }
 
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/wowsims/tbc/sim/core/proto"
@@ -59,7 +60,12 @@ func (env *Environment) construct(raidProto proto.Raid, encounterProto proto.Enc
 
 	// Apply extra debuffs from raid.
 	if raidProto.Debuffs != nil && len(env.Encounter.Targets) > 0 {
-		applyDebuffEffects(&env.Encounter.Targets[0].Unit, *raidProto.Debuffs)
+		debuffs := *raidProto.Debuffs
+		if raidHasImprovedShadowBolt(raidProto) {
+			// ISB comes from the real warlocks instead.
+			debuffs.IsbUptime = 0
+		}
+		applyDebuffEffects(&env.Encounter.Targets[0].Unit, debuffs)
 	}
 
 	// Assign target or target using Tanks field.
@@ -91,6 +97,9 @@ func (env *Environment) initialize(raidProto proto.Raid, encounterProto proto.En
 			target.initialize(nil)
 		}
 	}
+	env.Encounter.initializePhases()
+	env.Encounter.initializeHealth()
+	env.Encounter.initializeMovement(env.Raid)
 
 	for _, party := range env.Raid.Parties {
 		for _, playerOrPet := range party.PlayersAndPets {
@@ -138,6 +147,20 @@ func (env *Environment) finalize(raidProto proto.Raid, encounterProto proto.Enco
 	env.State = Finalized
 }
 
+// Returns an error if any player's settings can't be simmed, e.g. an invalid
+// APL rotation.
+func (env *Environment) validate() error {
+	for _, party := range env.Raid.Parties {
+		for _, player := range party.Players {
+			character := player.GetCharacter()
+			if character.aplRotationError != nil {
+				return fmt.Errorf("Invalid rotation for %s: %s", character.Name, character.aplRotationError)
+			}
+		}
+	}
+	return nil
+}
+
 func (env *Environment) IsFinalized() bool {
 	return env.State >= Finalized
 }
@@ -51,6 +51,8 @@ const (
 	ProcMaskRangedSpecial
 	ProcMaskSpellDamage
 	ProcMaskPeriodicDamage
+	ProcMaskSpellHealing
+	ProcMaskPeriodicHealing
 )
 
 const (
@@ -177,6 +179,7 @@ const (
 	SpellExtrasNoOnCastComplete                         // Disables OnCastComplete callbacks.
 	SpellExtrasNoMetrics                                // Disables metrics for a spell.
 	SpellExtrasNoLogs                                   // Disables logs for a spell.
+	SpellExtrasHealing                                  // Spell heals friendly units, so metrics are tracked per raid member.
 
 	// Used to let agents categorize their spells.
 	SpellExtrasAgentReserved1
@@ -28,6 +28,9 @@ func (unit *Unit) NextGCDAt() time.Duration {
 }
 
 func (unit *Unit) SetGCDTimer(sim *Simulation, gcdReadyAt time.Duration) {
+	if unit.IsDead() {
+		return
+	}
 	unit.GCD.Set(gcdReadyAt)
 
 	unit.gcdAction.Cancel(sim)
@@ -95,6 +98,11 @@ func (unit *Unit) HardcastWaitUntil(sim *Simulation, readyTime time.Duration, on
 }
 
 func (unit *Unit) WaitForMana(sim *Simulation, desiredMana float64) {
+	if unit.IsMoving(sim) && !unit.GCD.IsReady(sim) {
+		// The cast failed because of movement, not mana, and the GCD is already
+		// paused until the movement ends.
+		return
+	}
 	if !unit.IsWaitingForMana() {
 		unit.waitStartTime = sim.CurrentTime
 	}
@@ -0,0 +1,558 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const defaultGearOptimizeCandidates = 5
+
+// Number of items per slot which are kept after ranking items on their own.
+const gearOptimizeItemsPerSlot = 4
+
+// Upper bound on the number of full passes over all slots during the search.
+const gearOptimizeMaxPasses = 10
+
+type GearOptimizeCandidate struct {
+	Equipment     items.Equipment
+	WeightedScore float64
+	SimScore      *proto.DistributionMetrics
+}
+
+func (candidate GearOptimizeCandidate) ToProto() *proto.GearOptimizeCandidate {
+	return &proto.GearOptimizeCandidate{
+		Equipment:     candidate.Equipment.ToEquipmentSpecProto(),
+		WeightedScore: candidate.WeightedScore,
+		SimScore:      candidate.SimScore,
+	}
+}
+
+type GearOptimizeResult struct {
+	Candidates []GearOptimizeCandidate
+	ErrorMsg   string
+}
+
+func (result GearOptimizeResult) ToProto() *proto.GearOptimizeResult {
+	resultProto := &proto.GearOptimizeResult{
+		ErrorMsg: result.ErrorMsg,
+	}
+	for _, candidate := range result.Candidates {
+		resultProto.Candidates = append(resultProto.Candidates, candidate.ToProto())
+	}
+	return resultProto
+}
+
+// gearOptimizer scores gear sets using stat weights.
+type gearOptimizer struct {
+	weights stats.Stats
+
+	// Hit rating from sources other than gear, and the caps it applies to.
+	spellHitFromOther float64
+	meleeHitFromOther float64
+	spellHitCap       float64
+	meleeHitCap       float64
+
+	gems     []items.Gem
+	enchants []items.Enchant
+
+	// Candidate items for each slot, without gems or enchants.
+	slotItems [items.ItemSlotRanged + 1][]items.Item
+}
+
+// Returns the weighted score of a set of gear stats, ignoring hit beyond the caps.
+func (gopt *gearOptimizer) scoreStats(gearStats stats.Stats) float64 {
+	if gopt.spellHitCap != 0 {
+		gearStats[stats.SpellHit] = MinFloat(gearStats[stats.SpellHit], MaxFloat(0, gopt.spellHitCap-gopt.spellHitFromOther))
+	}
+	if gopt.meleeHitCap != 0 {
+		gearStats[stats.MeleeHit] = MinFloat(gearStats[stats.MeleeHit], MaxFloat(0, gopt.meleeHitCap-gopt.meleeHitFromOther))
+	}
+
+	score := 0.0
+	for stat, weight := range gopt.weights {
+		score += weight * gearStats[stat]
+	}
+	return score
+}
+
+func (gopt *gearOptimizer) scoreItem(item items.Item) float64 {
+	equipment := items.Equipment{}
+	equipment[0] = item
+	return gopt.scoreEquipment(equipment)
+}
+
+func (gopt *gearOptimizer) scoreEquipment(equipment items.Equipment) float64 {
+	return gopt.scoreStats(equipment.Stats())
+}
+
+func enchantFitsItem(enchant items.Enchant, item items.Item) bool {
+	if enchant.ItemType != item.Type {
+		return false
+	}
+	if item.Type != proto.ItemType_ItemTypeWeapon {
+		return true
+	}
+
+	isShield := item.WeaponType == proto.WeaponType_WeaponTypeShield
+	switch enchant.EnchantType {
+	case proto.EnchantType_EnchantTypeTwoHand:
+		return item.HandType == proto.HandType_HandTypeTwoHand
+	case proto.EnchantType_EnchantTypeShield:
+		return isShield
+	default:
+		return !isShield && item.WeaponType != proto.WeaponType_WeaponTypeOffHand
+	}
+}
+
+// Applies the best allowed enchant to item. Keeps the current enchant if no
+// enchants are allowed.
+func (gopt *gearOptimizer) enchantItem(item items.Item) items.Item {
+	if len(gopt.enchants) == 0 {
+		return item
+	}
+
+	bestScore := 0.0
+	item.Enchant = items.Enchant{}
+	for _, enchant := range gopt.enchants {
+		if !enchantFitsItem(enchant, item) {
+			continue
+		}
+		if score := gopt.scoreStats(enchant.Bonus); score > bestScore {
+			bestScore = score
+			item.Enchant = enchant
+		}
+	}
+	return item
+}
+
+// Returns the best allowed gem which fits the socket, or an empty Gem if none
+// do. If matchColor is set, only gems which match the socket color are used.
+// Unique gems which are already in usedUnique are skipped.
+func (gopt *gearOptimizer) bestGem(socket proto.GemColor, matchColor bool, usedUnique map[int32]bool) items.Gem {
+	best := items.Gem{}
+	bestScore := 0.0
+	for _, gem := range gopt.gems {
+		if (gem.Color == proto.GemColor_GemColorMeta) != (socket == proto.GemColor_GemColorMeta) {
+			continue
+		}
+		if matchColor && !items.ColorIntersects(gem.Color, socket) {
+			continue
+		}
+		if gem.Unique && usedUnique[gem.ID] {
+			continue
+		}
+		if score := gopt.scoreStats(gem.Stats); best.ID == 0 || score > bestScore {
+			best = gem
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// Fills the sockets of item with the best allowed gems, either matching the
+// socket colors to get the socket bonus or ignoring it, whichever scores higher.
+// Keeps the current gems if no gems are allowed.
+func (gopt *gearOptimizer) gemItem(item items.Item, usedUnique map[int32]bool) items.Item {
+	if len(gopt.gems) == 0 || len(item.GemSockets) == 0 {
+		return item
+	}
+
+	gemSockets := func(matchColor bool) (items.Item, map[int32]bool) {
+		gemmed := item
+		gemmed.Gems = make([]items.Gem, len(item.GemSockets))
+		used := make(map[int32]bool, len(usedUnique))
+		for id := range usedUnique {
+			used[id] = true
+		}
+		for i, socket := range item.GemSockets {
+			gemmed.Gems[i] = gopt.bestGem(socket, matchColor, used)
+			if gemmed.Gems[i].Unique {
+				used[gemmed.Gems[i].ID] = true
+			}
+		}
+		return gemmed, used
+	}
+
+	matched, matchedUsed := gemSockets(true)
+	unmatched, unmatchedUsed := gemSockets(false)
+	if gopt.scoreItem(unmatched) > gopt.scoreItem(matched) {
+		matched, matchedUsed = unmatched, unmatchedUsed
+	}
+	for id := range matchedUsed {
+		usedUnique[id] = true
+	}
+	return matched
+}
+
+// Gems and enchants every item in the set, then makes sure the meta gem is active.
+func (gopt *gearOptimizer) finishEquipment(equipment items.Equipment) items.Equipment {
+	usedUnique := make(map[int32]bool)
+	for slot, item := range equipment {
+		if item.ID == 0 {
+			continue
+		}
+		equipment[slot] = gopt.gemItem(gopt.enchantItem(item), usedUnique)
+	}
+	return gopt.activateMetaGem(equipment)
+}
+
+// Swaps gems until the color requirements of the meta gem are met, picking the
+// swap which loses the least score each time. If the requirements can't be met
+// the meta gem is removed, since it would give nothing.
+func (gopt *gearOptimizer) activateMetaGem(equipment items.Equipment) items.Equipment {
+	if len(gopt.gems) == 0 {
+		return equipment
+	}
+
+	for equipment.MetaGemDeficit() > 0 {
+		deficit := equipment.MetaGemDeficit()
+		bestScore := 0.0
+		var best *items.Equipment
+
+		for slot, item := range equipment {
+			for gemIdx, curGem := range item.Gems {
+				if curGem.Color == proto.GemColor_GemColorMeta {
+					continue
+				}
+				for _, gem := range gopt.gems {
+					if gem.ID == curGem.ID || gem.Color == proto.GemColor_GemColorMeta || (gem.Unique && equipmentHasGem(equipment, gem.ID)) {
+						continue
+					}
+
+					swapped := equipment
+					swapped[slot].Gems = append([]items.Gem{}, item.Gems...)
+					swapped[slot].Gems[gemIdx] = gem
+					if swapped.MetaGemDeficit() >= deficit {
+						continue
+					}
+					if score := gopt.scoreEquipment(swapped); best == nil || score > bestScore {
+						best = &swapped
+						bestScore = score
+					}
+				}
+			}
+		}
+
+		if best == nil {
+			head := equipment[items.ItemSlotHead]
+			head.Gems = append([]items.Gem{}, head.Gems...)
+			for i, gem := range head.Gems {
+				if gem.Color == proto.GemColor_GemColorMeta {
+					head.Gems[i] = items.Gem{}
+				}
+			}
+			equipment[items.ItemSlotHead] = head
+			break
+		}
+		equipment = *best
+	}
+	return equipment
+}
+
+func equipmentHasGem(equipment items.Equipment, gemID int32) bool {
+	for _, item := range equipment {
+		for _, gem := range item.Gems {
+			if gem.ID == gemID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Whether the set breaks unique-equipped restrictions or has an invalid weapon setup.
+func isValidGearSet(equipment items.Equipment) bool {
+	seen := make(map[int32]bool)
+	for _, item := range equipment {
+		if item.ID == 0 || !item.Unique {
+			continue
+		}
+		if seen[item.ID] {
+			return false
+		}
+		seen[item.ID] = true
+	}
+
+	if equipment[items.ItemSlotMainHand].HandType == proto.HandType_HandTypeTwoHand && equipment[items.ItemSlotOffHand].ID != 0 {
+		return false
+	}
+	return true
+}
+
+func gearSetKey(equipment items.Equipment) string {
+	var sb strings.Builder
+	for _, item := range equipment {
+		fmt.Fprintf(&sb, "%d:%d:", item.ID, item.Enchant.ID)
+		for _, gem := range item.Gems {
+			fmt.Fprintf(&sb, "%d,", gem.ID)
+		}
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+// Returns the slots an item can go in.
+func gearOptimizeSlots(item items.Item, canDualWield bool) []items.ItemSlot {
+	switch item.Type {
+	case proto.ItemType_ItemTypeFinger:
+		return []items.ItemSlot{items.ItemSlotFinger1, items.ItemSlotFinger2}
+	case proto.ItemType_ItemTypeTrinket:
+		return []items.ItemSlot{items.ItemSlotTrinket1, items.ItemSlotTrinket2}
+	case proto.ItemType_ItemTypeWeapon:
+		switch item.HandType {
+		case proto.HandType_HandTypeOffHand:
+			return []items.ItemSlot{items.ItemSlotOffHand}
+		case proto.HandType_HandTypeOneHand:
+			if canDualWield {
+				return []items.ItemSlot{items.ItemSlotMainHand, items.ItemSlotOffHand}
+			}
+			return []items.ItemSlot{items.ItemSlotMainHand}
+		default:
+			if item.WeaponType == proto.WeaponType_WeaponTypeShield || item.WeaponType == proto.WeaponType_WeaponTypeOffHand {
+				return []items.ItemSlot{items.ItemSlotOffHand}
+			}
+			return []items.ItemSlot{items.ItemSlotMainHand}
+		}
+	default:
+		return []items.ItemSlot{items.ItemTypeToSlot(item.Type)}
+	}
+}
+
+// Searches the candidate pool for the gear sets with the best weighted score,
+// starting from the best item in each slot and swapping one slot at a time
+// until no swap improves the score. Returns up to numSets sets, best first.
+func (gopt *gearOptimizer) search(baseEquipment items.Equipment, numSets int) []GearOptimizeCandidate {
+	// Rank items on their own and keep only the best few for each slot.
+	for slot := range gopt.slotItems {
+		candidates := gopt.slotItems[slot]
+		scores := make(map[int32]float64, len(candidates))
+		for _, item := range candidates {
+			scores[item.ID] = gopt.scoreItem(gopt.gemItem(gopt.enchantItem(item), map[int32]bool{}))
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return scores[candidates[i].ID] > scores[candidates[j].ID]
+		})
+		if len(candidates) > gearOptimizeItemsPerSlot {
+			candidates = candidates[:gearOptimizeItemsPerSlot]
+		}
+		gopt.slotItems[slot] = candidates
+	}
+
+	seen := make(map[string]GearOptimizeCandidate)
+	evaluate := func(equipment items.Equipment) (GearOptimizeCandidate, bool) {
+		if !isValidGearSet(equipment) {
+			return GearOptimizeCandidate{}, false
+		}
+		equipment = gopt.finishEquipment(equipment)
+		key := gearSetKey(equipment)
+		if candidate, ok := seen[key]; ok {
+			return candidate, true
+		}
+		candidate := GearOptimizeCandidate{
+			Equipment:     equipment,
+			WeightedScore: gopt.scoreEquipment(equipment),
+		}
+		seen[key] = candidate
+		return candidate, true
+	}
+
+	withItem := func(equipment items.Equipment, slot items.ItemSlot, item items.Item) items.Equipment {
+		equipment[slot] = item
+		if slot == items.ItemSlotMainHand && item.HandType == proto.HandType_HandTypeTwoHand {
+			equipment[items.ItemSlotOffHand] = items.Item{}
+		}
+		return equipment
+	}
+
+	current := baseEquipment
+	for slot, candidates := range gopt.slotItems {
+		for _, item := range candidates {
+			if next := withItem(current, items.ItemSlot(slot), item); isValidGearSet(next) {
+				current = next
+				break
+			}
+		}
+	}
+	best, ok := evaluate(current)
+	if !ok {
+		best, _ = evaluate(baseEquipment)
+		current = baseEquipment
+	}
+
+	for pass := 0; pass < gearOptimizeMaxPasses; pass++ {
+		improved := false
+		for slot, candidates := range gopt.slotItems {
+			for _, item := range candidates {
+				candidate, ok := evaluate(withItem(current, items.ItemSlot(slot), item))
+				if ok && candidate.WeightedScore > best.WeightedScore {
+					best = candidate
+					current = withItem(current, items.ItemSlot(slot), item)
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	sets := make([]GearOptimizeCandidate, 0, len(seen))
+	for _, candidate := range seen {
+		sets = append(sets, candidate)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].WeightedScore != sets[j].WeightedScore {
+			return sets[i].WeightedScore > sets[j].WeightedScore
+		}
+		return gearSetKey(sets[i].Equipment) < gearSetKey(sets[j].Equipment)
+	})
+	if len(sets) > numSets {
+		sets = sets[:numSets]
+	}
+	return sets
+}
+
+// CalcGearOptimize finds the best gear set for a player from a pool of candidate
+// items, gems and enchants. Stat weights are used to quickly find the most
+// promising sets, which are then confirmed with full sims. The sims stop early
+// if ctx is cancelled, and the result then only has an error message.
+func CalcGearOptimize(ctx context.Context, request proto.GearOptimizeRequest, progress chan *proto.ProgressMetrics) GearOptimizeResult {
+	if request.Player == nil {
+		return GearOptimizeResult{ErrorMsg: "Missing player"}
+	}
+	if request.SimOptions == nil {
+		return GearOptimizeResult{ErrorMsg: "Missing sim options"}
+	}
+	if len(request.StatWeights) == 0 {
+		return GearOptimizeResult{ErrorMsg: "Missing stat weights"}
+	}
+	if request.Player.Equipment == nil {
+		request.Player.Equipment = &proto.EquipmentSpec{}
+	}
+
+	gopt := &gearOptimizer{
+		spellHitCap: request.SpellHitCap,
+		meleeHitCap: request.MeleeHitCap,
+	}
+	copy(gopt.weights[:], request.StatWeights)
+
+	for _, id := range request.GemIds {
+		gem, ok := items.GemsByID[id]
+		if !ok {
+			return GearOptimizeResult{ErrorMsg: fmt.Sprintf("No gem with id: %d", id)}
+		}
+		gopt.gems = append(gopt.gems, gem)
+	}
+	for _, id := range request.EnchantIds {
+		enchant, ok := items.EnchantsByID[id]
+		if !ok {
+			return GearOptimizeResult{ErrorMsg: fmt.Sprintf("No enchant with id: %d", id)}
+		}
+		gopt.enchants = append(gopt.enchants, enchant)
+	}
+
+	baseEquipment := items.ProtoToEquipment(*request.Player.Equipment)
+
+	// Only allow 1H weapons in the offhand if the player is already dual wielding.
+	offHand := baseEquipment[items.ItemSlotOffHand]
+	canDualWield := offHand.ID != 0 &&
+		(offHand.HandType == proto.HandType_HandTypeOneHand || offHand.HandType == proto.HandType_HandTypeOffHand) &&
+		offHand.WeaponType != proto.WeaponType_WeaponTypeShield &&
+		offHand.WeaponType != proto.WeaponType_WeaponTypeOffHand
+
+	for _, id := range request.ItemIds {
+		item, ok := items.ByID[id]
+		if !ok {
+			return GearOptimizeResult{ErrorMsg: fmt.Sprintf("No item with id: %d", id)}
+		}
+		for _, slot := range gearOptimizeSlots(item, canDualWield) {
+			gopt.slotItems[slot] = append(gopt.slotItems[slot], item)
+		}
+	}
+	// The currently equipped items are always candidates too.
+	for slot, item := range baseEquipment {
+		if item.ID != 0 && len(gopt.slotItems[slot]) > 0 {
+			item.Gems = nil
+			item.Enchant = items.Enchant{}
+			if len(gopt.gems) == 0 {
+				item.Gems = baseEquipment[slot].Gems
+			}
+			if len(gopt.enchants) == 0 {
+				item.Enchant = baseEquipment[slot].Enchant
+			}
+			gopt.slotItems[slot] = append(gopt.slotItems[slot], item)
+		}
+	}
+
+	raidProto := SinglePlayerRaidProto(request.Player, request.PartyBuffs, request.RaidBuffs, request.Debuffs)
+	raidProto.Tanks = request.Tanks
+
+	statsResult := ComputeStats(&proto.ComputeStatsRequest{
+		Raid: raidProto,
+	})
+	if statsResult.ErrorMsg != "" {
+		return GearOptimizeResult{ErrorMsg: statsResult.ErrorMsg}
+	}
+	playerStats := statsResult.RaidStats.Parties[0].Players[0]
+	gopt.spellHitFromOther = playerStats.FinalStats[stats.SpellHit] - playerStats.GearStats[stats.SpellHit]
+	gopt.meleeHitFromOther = playerStats.FinalStats[stats.MeleeHit] - playerStats.GearStats[stats.MeleeHit]
+
+	numCandidates := int(request.NumCandidates)
+	if numCandidates <= 0 {
+		numCandidates = defaultGearOptimizeCandidates
+	}
+	candidates := gopt.search(baseEquipment, numCandidates)
+
+	// Confirm the candidates with real sims.
+	batch := newSimBatch(progress)
+	batch.addSims(int32(len(candidates)), request.SimOptions.Iterations)
+	simErrors := make([]string, len(candidates))
+
+	runInWorkerPool(len(candidates), func(candidateIdx int) {
+		if ctx.Err() != nil {
+			return
+		}
+		candidate := &candidates[candidateIdx]
+		simRequest := &proto.RaidSimRequest{
+			Raid:       googleProto.Clone(raidProto).(*proto.Raid),
+			Encounter:  googleProto.Clone(request.Encounter).(*proto.Encounter),
+			SimOptions: googleProto.Clone(request.SimOptions).(*proto.SimOptions),
+		}
+		simRequest.Raid.Parties[0].Players[0].Equipment = candidate.Equipment.ToEquipmentSpecProto()
+
+		simResult := batch.runSim(ctx, simRequest, request.SimOptions.Iterations)
+		if simResult.ErrorMsg != "" {
+			simErrors[candidateIdx] = simResult.ErrorMsg
+			return
+		}
+		playerMetrics := simResult.RaidMetrics.Parties[0].Players[0]
+		if request.MaximizeHps {
+			candidate.SimScore = playerMetrics.Hps
+		} else {
+			candidate.SimScore = playerMetrics.Dps
+		}
+	})
+	if ctx.Err() != nil {
+		return GearOptimizeResult{ErrorMsg: cancelledSimResult().ErrorMsg}
+	}
+	for _, errorMsg := range simErrors {
+		if errorMsg != "" {
+			return GearOptimizeResult{ErrorMsg: errorMsg}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].SimScore.Avg > candidates[j].SimScore.Avg
+	})
+
+	return GearOptimizeResult{
+		Candidates: candidates,
+	}
+}
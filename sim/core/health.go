@@ -0,0 +1,244 @@
+package core
+
+import (
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+type healthBar struct {
+	unit *Unit
+
+	currentHealth float64
+
+	// Whether this unit dies when its health reaches 0.
+	canDie bool
+	isDead bool
+
+	// Active absorb shields, in the order they were applied.
+	absorbShields []*AbsorbShield
+
+	// Invoked whenever this unit loses health, if set.
+	onHealthLost func(sim *Simulation)
+
+	// Invoked when this unit dies, if set.
+	onDeath func(sim *Simulation)
+}
+
+// Adds a handler which is invoked whenever this unit loses health.
+func (unit *Unit) addOnHealthLost(handler func(sim *Simulation)) {
+	oldOnHealthLost := unit.onHealthLost
+	if oldOnHealthLost == nil {
+		unit.onHealthLost = handler
+		return
+	}
+	unit.onHealthLost = func(sim *Simulation) {
+		oldOnHealthLost(sim)
+		handler(sim)
+	}
+}
+
+// Adds a handler which is invoked when this unit dies.
+func (unit *Unit) addOnDeath(handler func(sim *Simulation)) {
+	oldOnDeath := unit.onDeath
+	if oldOnDeath == nil {
+		unit.onDeath = handler
+		return
+	}
+	unit.onDeath = func(sim *Simulation) {
+		oldOnDeath(sim)
+		handler(sim)
+	}
+}
+
+func (unit *Unit) MaxHealth() float64 {
+	return unit.stats[stats.Health]
+}
+
+func (hb *healthBar) CurrentHealth() float64 {
+	return hb.currentHealth
+}
+
+func (hb *healthBar) CurrentHealthPercent() float64 {
+	maxHealth := hb.unit.MaxHealth()
+	if maxHealth == 0 {
+		return 1
+	}
+	return hb.currentHealth / maxHealth
+}
+
+func (hb *healthBar) IsDead() bool {
+	return hb.isDead
+}
+
+// Restores health, up to the unit's max health. Returns the amount of health
+// that was actually restored, i.e. not including overhealing.
+func (hb *healthBar) GainHealth(sim *Simulation, amount float64) float64 {
+	if amount < 0 {
+		panic("Trying to gain negative health!")
+	}
+	if hb.isDead {
+		return 0
+	}
+
+	newHealth := MinFloat(hb.currentHealth+amount, hb.unit.MaxHealth())
+	actualGain := MaxFloat(0, newHealth-hb.currentHealth)
+	hb.currentHealth = MaxFloat(hb.currentHealth, newHealth)
+	return actualGain
+}
+
+func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
+	if amount < 0 {
+		panic("Trying to remove negative health!")
+	}
+	if hb.isDead {
+		return
+	}
+
+	hb.currentHealth = MaxFloat(0, hb.currentHealth-amount)
+	if hb.onHealthLost != nil {
+		hb.onHealthLost(sim)
+	}
+
+	if hb.currentHealth == 0 && hb.canDie {
+		hb.unit.die(sim)
+	}
+}
+
+// Removes this unit from the fight for the rest of the iteration. All of its
+// auras are removed, its channel is interrupted, and its GCD, hardcast, auto
+// attack and periodic actions are cancelled. A character's pets are dismissed.
+func (unit *Unit) die(sim *Simulation) {
+	unit.isDead = true
+	unit.Metrics.MarkDeath(unit, sim.CurrentTime)
+
+	if sim.Log != nil {
+		unit.Log(sim, "Died.")
+	}
+
+	if unit.gcdAction != nil {
+		unit.CancelGCDTimer(sim)
+	}
+	if unit.hardcastAction != nil {
+		unit.hardcastAction.Cancel(sim)
+		unit.hardcastAction = nil
+	}
+	unit.Hardcast = Hardcast{}
+	unit.AutoAttacks.CancelAutoSwing(sim)
+	unit.interruptChannel(sim)
+	for _, pa := range unit.periodicActions {
+		pa.Cancel(sim)
+	}
+	unit.periodicActions = nil
+
+	unit.auraTracker.expireAll(sim)
+
+	if unit.onDeath != nil {
+		unit.onDeath(sim)
+	}
+
+	if unit.Type == EnemyUnit {
+		sim.Encounter.onTargetDeath(sim, sim.Encounter.Targets[unit.Index])
+	}
+}
+
+// Absorbs as much of the damage from spell as possible using the active absorb
+// shields. Returns the amount of damage absorbed.
+func (hb *healthBar) absorbDamage(sim *Simulation, spell *Spell, damage float64) float64 {
+	totalAbsorbed := 0.0
+	i := 0
+	for i < len(hb.absorbShields) && damage > 0 {
+		shield := hb.absorbShields[i]
+		if shield.SpellSchool != SpellSchoolNone && !shield.SpellSchool.Matches(spell.SpellSchool) {
+			i++
+			continue
+		}
+
+		absorbed := MinFloat(damage, shield.remaining)
+		shield.remaining -= absorbed
+		damage -= absorbed
+		totalAbsorbed += absorbed
+
+		if shield.Spell != nil {
+			shield.Spell.SpellMetrics[hb.unit.Index].TotalHealing += absorbed
+		}
+		if sim.Log != nil {
+			hb.unit.Log(sim, "%s absorbed %0.3f damage from %s (%0.3f remaining).", shield.ActionID, absorbed, spell.ActionID, shield.remaining)
+		}
+
+		if shield.remaining <= 0 {
+			// Deactivating removes the shield from the list, so don't advance.
+			shield.Aura.Deactivate(sim)
+		} else {
+			i++
+		}
+	}
+	return totalAbsorbed
+}
+
+func (hb *healthBar) reset(sim *Simulation) {
+	hb.currentHealth = hb.unit.MaxHealth()
+	hb.isDead = false
+	hb.absorbShields = hb.absorbShields[:0]
+}
+
+// AbsorbShield is a damage absorption effect attached to an aura, e.g. Power
+// Word: Shield. While the aura is active, damage taken by the aura's unit is
+// absorbed before it is removed from health. The aura is removed once the
+// shield is depleted.
+type AbsorbShield struct {
+	// Spell which places this shield. Absorbed damage is added to its healing
+	// metrics, so it must have SpellExtrasHealing. May be nil.
+	Spell *Spell
+
+	// Embed Aura so we can use IsActive/Refresh/etc directly.
+	*Aura
+
+	// If set, only damage of these schools is absorbed.
+	SpellSchool SpellSchool
+
+	remaining float64
+}
+
+// Activates the shield aura, with enough capacity to absorb amount damage.
+func (shield *AbsorbShield) Apply(sim *Simulation, amount float64) {
+	shield.remaining = amount
+	if shield.Aura.IsActive() {
+		shield.Aura.Refresh(sim)
+	} else {
+		shield.Aura.Activate(sim)
+	}
+}
+
+// Returns the amount of damage this shield can still absorb.
+func (shield *AbsorbShield) Remaining() float64 {
+	return shield.remaining
+}
+
+func NewAbsorbShield(config AbsorbShield) *AbsorbShield {
+	shield := &AbsorbShield{}
+	*shield = config
+
+	oldOnGain := shield.Aura.OnGain
+	shield.Aura.OnGain = func(aura *Aura, sim *Simulation) {
+		aura.Unit.absorbShields = append(aura.Unit.absorbShields, shield)
+		if oldOnGain != nil {
+			oldOnGain(aura, sim)
+		}
+	}
+
+	oldOnExpire := shield.Aura.OnExpire
+	shield.Aura.OnExpire = func(aura *Aura, sim *Simulation) {
+		shield.remaining = 0
+		shields := aura.Unit.absorbShields
+		for i, other := range shields {
+			if other == shield {
+				aura.Unit.absorbShields = append(shields[:i], shields[i+1:]...)
+				break
+			}
+		}
+		if oldOnExpire != nil {
+			oldOnExpire(aura, sim)
+		}
+	}
+
+	return shield
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+const (
+	// Time between Shadow Bolts for each estimated warlock, i.e. a 3s cast with
+	// 5/5 Bane.
+	estimatedIsbCastInterval = time.Millisecond * 2500
+
+	// Time between charge-consuming hits for each estimated shadow caster.
+	estimatedIsbShadowCasterInterval = time.Millisecond * 2500
+
+	defaultEstimatedIsbCritChance = 0.2
+)
+
+// Whether any player in the raid is a warlock with Improved Shadow Bolt.
+func raidHasImprovedShadowBolt(raidProto proto.Raid) bool {
+	for _, party := range raidProto.Parties {
+		for _, player := range party.Players {
+			if player.GetWarlock().GetTalents().GetImprovedShadowBolt() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Models ISB from warlocks which aren't part of the sim. Each estimated warlock
+// casts Shadow Bolt on a fixed interval, refreshing ISB to full charges on a
+// crit and consuming a charge otherwise, and each estimated shadow caster
+// consumes a charge on a fixed interval. The ISB aura itself is the same one
+// used by real warlocks, so charges are also consumed by the shadow hits of
+// the players in the sim.
+func estimatedImprovedShadowBolt(target *Unit, debuffs proto.Debuffs) {
+	isbAura := ImprovedShadowBoltAura(target, 5, 0)
+
+	critChance := debuffs.IsbCritChance
+	if critChance <= 0 {
+		critChance = defaultEstimatedIsbCritChance
+	}
+
+	numWarlocks := int(debuffs.IsbWarlocks)
+	numShadowCasters := int(debuffs.IsbShadowCasters)
+
+	consumeCharge := func(sim *Simulation) {
+		if isbAura.IsActive() {
+			isbAura.metrics.ChargesConsumed++
+			isbAura.RemoveStack(sim)
+		}
+	}
+
+	// Starts a repeating action with a random initial offset, so the estimated
+	// casters aren't all in sync.
+	startCaster := func(sim *Simulation, interval time.Duration, onCast func(sim *Simulation)) {
+		pa := &PendingAction{
+			NextActionAt: DurationFromSeconds(sim.RandomFloat("Estimated ISB Offset") * interval.Seconds()),
+		}
+		pa.OnAction = func(sim *Simulation) {
+			onCast(sim)
+			pa.NextActionAt = sim.CurrentTime + interval
+			sim.AddPendingAction(pa)
+		}
+		sim.AddPendingAction(pa)
+	}
+
+	MakePermanent(target.RegisterAura(Aura{
+		Label: "Estimated ISB",
+		OnReset: func(aura *Aura, sim *Simulation) {
+			for i := 0; i < numWarlocks; i++ {
+				startCaster(sim, estimatedIsbCastInterval, func(sim *Simulation) {
+					if sim.RandomFloat("Estimated ISB Crit") < critChance {
+						isbAura.Activate(sim)
+						isbAura.SetStacks(sim, isbAura.MaxStacks)
+					} else {
+						consumeCharge(sim)
+					}
+				})
+			}
+			for i := 0; i < numShadowCasters; i++ {
+				startCaster(sim, estimatedIsbShadowCasterInterval, consumeCharge)
+			}
+		},
+	}))
+}
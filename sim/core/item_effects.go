@@ -1,8 +1,10 @@
 package core
 
 import (
-	"github.com/wowsims/tbc/sim/core/proto"
 	"log"
+
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
 )
 
 // Function for applying permanent effects to an Agent.
@@ -27,6 +29,16 @@ func HasWeaponEffect(id int32) bool {
 	return ok
 }
 
+// Returns whether the item's tooltip has an on-use or proc effect but there is
+// no registered effect or item set for it, so the sim only uses its stats.
+func HasUnimplementedEffect(item items.Item) bool {
+	if !item.HasEffect {
+		return false
+	}
+	_, inSet := itemSetLookup[item.ID]
+	return !HasItemEffect(item.ID) && !HasWeaponEffect(item.ID) && !inSet
+}
+
 // Registers an ApplyEffect function which will be called before the Sim
 // starts, for any Agent that is wearing the item.
 func AddItemEffect(id int32, itemEffect ApplyEffect) {
@@ -88,6 +88,10 @@ type Item struct {
 	Ilvl       int32
 	SetName    string // Empty string if not part of a set.
 
+	// Whether the item's tooltip has an on-use or proc effect, which the sim
+	// only models if the effect is registered.
+	HasEffect bool
+
 	// Hidden variable used for a few obscure mechanics (Seal of Righteousness).
 	// Intuitively, this is a measure of the difference between the expected stats
 	// and the actual stats of an item, e.g. decreased weapon DPS on caster weapons.
@@ -122,6 +126,7 @@ func (item Item) ToProto() *proto.Item {
 		Ilvl:             item.Ilvl,
 		GemSockets:       item.GemSockets,
 		SocketBonus:      item.SocketBonus[:],
+		HasEffect:        item.HasEffect,
 	}
 }
 
@@ -0,0 +1,93 @@
+package items
+
+import (
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// MetaGemRequirement describes the gem colors needed to activate a meta gem.
+type MetaGemRequirement struct {
+	MinRed    int
+	MinYellow int
+	MinBlue   int
+
+	// If both are set, there must be more gems of MoreColor than of FewerColor.
+	MoreColor  proto.GemColor
+	FewerColor proto.GemColor
+}
+
+var MetaGemRequirements = map[int32]MetaGemRequirement{
+	25890: {MinRed: 2, MinYellow: 2, MinBlue: 2},                                               // Destructive Skyfire Diamond
+	25893: {MoreColor: proto.GemColor_GemColorBlue, FewerColor: proto.GemColor_GemColorYellow}, // Mystical Skyfire Diamond
+	25894: {MinRed: 1, MinYellow: 2},                                                           // Swift Skyfire Diamond
+	25895: {MoreColor: proto.GemColor_GemColorRed, FewerColor: proto.GemColor_GemColorYellow},  // Enigmatic Skyfire Diamond
+	25896: {MinBlue: 3},                                                                        // Powerful Earthstorm Diamond
+	25897: {MoreColor: proto.GemColor_GemColorRed, FewerColor: proto.GemColor_GemColorBlue},    // Bracing Earthstorm Diamond
+	25898: {MinBlue: 5},                                                                        // Tenacious Earthstorm Diamond
+	25899: {MinRed: 2, MinYellow: 2, MinBlue: 2},                                               // Brutal Earthstorm Diamond
+	25901: {MinRed: 2, MinYellow: 2, MinBlue: 2},                                               // Insightful Earthstorm Diamond
+	28556: {MinRed: 1, MinYellow: 2},                                                           // Swift Windfire Diamond
+	28557: {MinRed: 1, MinYellow: 2},                                                           // Swift Starfire Diamond
+	32409: {MinRed: 2, MinYellow: 2, MinBlue: 2},                                               // Relentless Earthstorm Diamond
+	32640: {MoreColor: proto.GemColor_GemColorBlue, FewerColor: proto.GemColor_GemColorYellow}, // Potent Unstable Diamond
+	32641: {MinYellow: 3},                                                                      // Imbued Unstable Diamond
+	34220: {MinBlue: 2},                                                                        // Chaotic Skyfire Diamond
+	35501: {MinYellow: 1, MinBlue: 2},                                                          // Eternal Earthstorm Diamond
+	35503: {MinRed: 3},                                                                         // Ember Skyfire Diamond
+}
+
+// Returns the number of equipped gems which count as the given color.
+func (equipment Equipment) GemColorCount(color proto.GemColor) int {
+	count := 0
+	for _, item := range equipment {
+		for _, gem := range item.Gems {
+			if gem.ID != 0 && gem.Color != proto.GemColor_GemColorMeta && ColorIntersects(gem.Color, color) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Returns the equipped meta gem, or an empty Gem if there is none.
+func (equipment Equipment) MetaGem() Gem {
+	for _, gem := range equipment[ItemSlotHead].Gems {
+		if gem.Color == proto.GemColor_GemColorMeta {
+			return gem
+		}
+	}
+	return Gem{}
+}
+
+// Returns how many more gems are needed to meet the color requirements of the
+// equipped meta gem. Returns 0 if there is no meta gem or its requirements are
+// unknown.
+func (equipment Equipment) MetaGemDeficit() int {
+	req, ok := MetaGemRequirements[equipment.MetaGem().ID]
+	if !ok {
+		return 0
+	}
+
+	deficit := 0
+	if count := equipment.GemColorCount(proto.GemColor_GemColorRed); count < req.MinRed {
+		deficit += req.MinRed - count
+	}
+	if count := equipment.GemColorCount(proto.GemColor_GemColorYellow); count < req.MinYellow {
+		deficit += req.MinYellow - count
+	}
+	if count := equipment.GemColorCount(proto.GemColor_GemColorBlue); count < req.MinBlue {
+		deficit += req.MinBlue - count
+	}
+	if req.MoreColor != proto.GemColor_GemColorUnknown {
+		more := equipment.GemColorCount(req.MoreColor)
+		fewer := equipment.GemColorCount(req.FewerColor)
+		if more <= fewer {
+			deficit += fewer - more + 1
+		}
+	}
+	return deficit
+}
+
+// Whether the color requirements of the equipped meta gem are met.
+func (equipment Equipment) MetaGemActive() bool {
+	return equipment.MetaGemDeficit() == 0
+}
@@ -129,16 +129,28 @@ func (mcd *MajorCooldown) tryActivateHelper(sim *Simulation, character *Characte
 	}
 
 	if shouldActivate {
-		mcd.activate(sim, character)
-		mcd.numUsages++
-		if sim.Log != nil {
-			character.Log(sim, "Major cooldown used: %s", mcd.Spell.ActionID)
-		}
+		mcd.use(sim, character)
 	}
 
 	return shouldActivate
 }
 
+// Activates this MCD unconditionally.
+func (mcd *MajorCooldown) use(sim *Simulation, character *Character) {
+	mcd.activate(sim, character)
+	mcd.numUsages++
+	if sim.Log != nil {
+		character.Log(sim, "Major cooldown used: %s", mcd.Spell.ActionID)
+	}
+	if sim.logCombatEvents {
+		sim.addCombatLogEvent(&proto.CombatLogEvent{
+			Type:     proto.CombatLogEvent_CooldownUsed,
+			Unit:     character.unitReference(),
+			ActionId: mcd.Spell.ActionID.ToProto(),
+		})
+	}
+}
+
 type majorCooldownManager struct {
 	// The Character whose cooldowns are being managed.
 	character *Character
@@ -58,7 +58,7 @@ func (unit *Unit) AddMana(sim *Simulation, amount float64, actionID ActionID, is
 
 	oldMana := unit.CurrentMana()
 	newMana := MinFloat(oldMana+amount, unit.MaxMana())
-	unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeMana, amount, newMana-oldMana)
+	unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeMana, amount, newMana-oldMana)
 
 	if sim.Log != nil {
 		unit.Log(sim, "Gained %0.3f mana from %s (%0.3f --> %0.3f).", amount, actionID, oldMana, newMana)
@@ -77,7 +77,7 @@ func (unit *Unit) SpendMana(sim *Simulation, amount float64, actionID ActionID)
 	}
 
 	newMana := unit.CurrentMana() - amount
-	unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeMana, -amount, -amount)
+	unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeMana, -amount, -amount)
 
 	if sim.Log != nil {
 		unit.Log(sim, "Spent %0.3f mana from %s (%0.3f --> %0.3f).", amount, actionID, unit.CurrentMana(), newMana)
@@ -226,6 +226,9 @@ func (sim *Simulation) initManaTickAction() {
 	}
 	pa.OnAction = func(sim *Simulation) {
 		for _, player := range playersWithManaBars {
+			if player.GetCharacter().IsDead() {
+				continue
+			}
 			player.GetCharacter().ManaTick(sim)
 			player.OnManaTick(sim)
 		}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
 )
 
 type ResourceKey struct {
@@ -21,6 +22,9 @@ type DistributionMetrics struct {
 	sumSquared float64
 	max        float64
 	hist       map[int32]int32 // rounded DPS to count
+
+	// For estimating percentiles, without keeping every sample.
+	quantiles quantileSketch
 }
 
 func (distMetrics *DistributionMetrics) reset() {
@@ -30,29 +34,69 @@ func (distMetrics *DistributionMetrics) reset() {
 // This should be called when a Sim iteration is complete.
 func (distMetrics *DistributionMetrics) doneIteration(encounterDurationSeconds float64) {
 	dps := distMetrics.Total / encounterDurationSeconds
+	distMetrics.addSample(dps, 10)
+}
+
+// Adds a single value to the aggregates, with histogram buckets of the given size.
+func (distMetrics *DistributionMetrics) addSample(value float64, bucketSize float64) {
+	distMetrics.sum += value
+	distMetrics.sumSquared += value * value
+	distMetrics.max = MaxFloat(distMetrics.max, value)
+	distMetrics.quantiles.add(value)
 
-	distMetrics.sum += dps
-	distMetrics.sumSquared += dps * dps
-	distMetrics.max = MaxFloat(distMetrics.max, dps)
+	rounded := int32(math.Round(value/bucketSize) * bucketSize)
+	distMetrics.hist[rounded]++
+}
 
-	dpsRounded := int32(math.Round(dps/10) * 10)
-	distMetrics.hist[dpsRounded]++
+// Adds the aggregate values from another DistributionMetrics into this one.
+func (distMetrics *DistributionMetrics) merge(other *DistributionMetrics) {
+	distMetrics.sum += other.sum
+	distMetrics.sumSquared += other.sumSquared
+	distMetrics.max = MaxFloat(distMetrics.max, other.max)
+	for dpsRounded, count := range other.hist {
+		distMetrics.hist[dpsRounded] += count
+	}
+	distMetrics.quantiles.merge(&other.quantiles)
+}
+
+// Returns the standard error of the mean after numIterations samples.
+func (distMetrics *DistributionMetrics) stderr(numIterations int32) float64 {
+	avg := distMetrics.sum / float64(numIterations)
+	variance := (distMetrics.sumSquared / float64(numIterations)) - (avg * avg)
+	return math.Sqrt(math.Max(variance, 0) / float64(numIterations))
 }
 
 func (distMetrics *DistributionMetrics) ToProto(numIterations int32) *proto.DistributionMetrics {
 	dpsAvg := distMetrics.sum / float64(numIterations)
+	stdev := math.Sqrt((distMetrics.sumSquared / float64(numIterations)) - (dpsAvg * dpsAvg))
+	stderr := stdev / math.Sqrt(float64(numIterations))
+
+	percentiles := distMetrics.quantiles.quantiles(0.05, 0.25, 0.5, 0.75, 0.95)
 
 	return &proto.DistributionMetrics{
 		Avg:   dpsAvg,
-		Stdev: math.Sqrt((distMetrics.sumSquared / float64(numIterations)) - (dpsAvg * dpsAvg)),
+		Stdev: stdev,
 		Max:   distMetrics.max,
 		Hist:  distMetrics.hist,
+
+		Min: distMetrics.quantiles.min,
+		P5:  percentiles[0],
+		P25: percentiles[1],
+		P50: percentiles[2],
+		P75: percentiles[3],
+		P95: percentiles[4],
+
+		Stderr:    stderr,
+		Ci95Lower: dpsAvg - confidenceZ95*stderr,
+		Ci95Upper: dpsAvg + confidenceZ95*stderr,
+		Count:     distMetrics.quantiles.count,
 	}
 }
 
 func NewDistributionMetrics() DistributionMetrics {
 	return DistributionMetrics{
-		hist: make(map[int32]int32),
+		hist:      make(map[int32]int32),
+		quantiles: newQuantileSketch(),
 	}
 }
 
@@ -60,13 +104,23 @@ type UnitMetrics struct {
 	dps    DistributionMetrics
 	threat DistributionMetrics
 	dtps   DistributionMetrics
+	hps    DistributionMetrics
+	ohps   DistributionMetrics
+
+	// Time of death, only sampled for iterations in which the unit died.
+	timeToDeath DistributionMetrics
+
+	timeline TimelineMetrics
 
 	CharacterIterationMetrics
 
 	// Aggregate values. These are updated after each iteration.
-	oomTimeSum float64
-	actions    map[ActionID]*ActionMetrics
-	resources  map[ResourceKey]*ResourceMetrics
+	oomTimeSum    float64
+	activeTimeSum float64
+	deaths        int32
+	actions       map[ActionID]*ActionMetrics
+	resources     map[ResourceKey]*ResourceMetrics
+	procs         map[ActionID]*ProcMetrics
 }
 
 // Metrics for the current iteration, for 1 agent. Keep this as a separate
@@ -79,6 +133,9 @@ type CharacterIterationMetrics struct {
 	BonusManaGained float64 // Only includes amount from mana pots / runes / innervates.
 
 	OOMTime time.Duration // time spent not casting and waiting for regen.
+
+	Died        bool          // Whether the unit died in this iteration.
+	TimeOfDeath time.Duration // Only valid if Died is true.
 }
 
 type ActionMetrics struct {
@@ -112,8 +169,26 @@ type TargetedActionMetrics struct {
 	Blocks  int32
 	Glances int32
 
-	Damage float64
-	Threat float64
+	Damage      float64
+	Threat      float64
+	Healing     float64
+	Overhealing float64
+}
+
+func (tam *TargetedActionMetrics) merge(other *TargetedActionMetrics) {
+	tam.Casts += other.Casts
+	tam.Hits += other.Hits
+	tam.Crits += other.Crits
+	tam.Crushes += other.Crushes
+	tam.Misses += other.Misses
+	tam.Dodges += other.Dodges
+	tam.Parries += other.Parries
+	tam.Blocks += other.Blocks
+	tam.Glances += other.Glances
+	tam.Damage += other.Damage
+	tam.Threat += other.Threat
+	tam.Healing += other.Healing
+	tam.Overhealing += other.Overhealing
 }
 
 func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
@@ -129,16 +204,24 @@ func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 		Glances: tam.Glances,
 		Damage:  tam.Damage,
 		Threat:  tam.Threat,
+
+		Healing:     tam.Healing,
+		Overhealing: tam.Overhealing,
 	}
 }
 
 func NewUnitMetrics() UnitMetrics {
 	return UnitMetrics{
-		dps:       NewDistributionMetrics(),
-		threat:    NewDistributionMetrics(),
-		dtps:      NewDistributionMetrics(),
-		actions:   make(map[ActionID]*ActionMetrics),
-		resources: make(map[ResourceKey]*ResourceMetrics),
+		dps:         NewDistributionMetrics(),
+		threat:      NewDistributionMetrics(),
+		dtps:        NewDistributionMetrics(),
+		hps:         NewDistributionMetrics(),
+		ohps:        NewDistributionMetrics(),
+		timeToDeath: NewDistributionMetrics(),
+		timeline:    TimelineMetrics{bucketSize: DefaultTimelineBucketSize},
+		actions:     make(map[ActionID]*ActionMetrics),
+		resources:   make(map[ResourceKey]*ResourceMetrics),
+		procs:       make(map[ActionID]*ProcMetrics),
 	}
 }
 
@@ -190,6 +273,17 @@ func (unitMetrics *UnitMetrics) AddResourceEvent(actionID ActionID, resourceType
 	resourceMetrics.ActualGain += actualGain
 }
 
+// Returns the metrics for a proc effect, creating them if needed. Effects with
+// the same ActionID share metrics.
+func (unitMetrics *UnitMetrics) GetOrRegisterProcMetrics(actionID ActionID) *ProcMetrics {
+	procMetrics, ok := unitMetrics.procs[actionID]
+	if !ok {
+		procMetrics = &ProcMetrics{ID: actionID}
+		unitMetrics.procs[actionID] = procMetrics
+	}
+	return procMetrics
+}
+
 // Adds the results of a spell to the character metrics.
 func (unitMetrics *UnitMetrics) addSpell(spell *Spell) {
 	actionMetrics, ok := unitMetrics.actions[spell.ActionID]
@@ -216,11 +310,23 @@ func (unitMetrics *UnitMetrics) addSpell(spell *Spell) {
 		tam.Glances += spellTargetMetrics.Glances
 		tam.Damage += spellTargetMetrics.TotalDamage
 		tam.Threat += spellTargetMetrics.TotalThreat
+		tam.Healing += spellTargetMetrics.TotalHealing
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
 		unitMetrics.dps.Total += spellTargetMetrics.TotalDamage
 		unitMetrics.threat.Total += spellTargetMetrics.TotalThreat
+		unitMetrics.hps.Total += spellTargetMetrics.TotalHealing
+		unitMetrics.ohps.Total += spellTargetMetrics.TotalOverhealing
+
+		// Healing spell metrics are indexed by raid member, not enemy.
+		if spell.SpellExtras.Matches(SpellExtrasHealing) {
+			continue
+		}
 
-		target := spell.Unit.AttackTables[i].Defender
-		target.Metrics.dtps.Total += spellTargetMetrics.TotalDamage
+		// Enemy attack tables are indexed by raid unit, so there are gaps for
+		// empty raid slots.
+		if attackTable := spell.Unit.AttackTables[i]; attackTable != nil {
+			attackTable.Defender.Metrics.dtps.Total += spellTargetMetrics.TotalDamage
+		}
 	}
 }
 
@@ -229,6 +335,7 @@ func (unitMetrics *UnitMetrics) addSpell(spell *Spell) {
 // Assumes that doneIteration() has already been called on the pet metrics.
 func (unitMetrics *UnitMetrics) AddFinalPetMetrics(petMetrics *UnitMetrics) {
 	unitMetrics.dps.Total += petMetrics.dps.Total
+	unitMetrics.timeline.addPetTimeline(&petMetrics.timeline)
 }
 
 func (unitMetrics *UnitMetrics) MarkOOM(unit *Unit, dur time.Duration) {
@@ -236,15 +343,26 @@ func (unitMetrics *UnitMetrics) MarkOOM(unit *Unit, dur time.Duration) {
 	unitMetrics.CharacterIterationMetrics.WentOOM = true
 }
 
+func (unitMetrics *UnitMetrics) MarkDeath(unit *Unit, timeOfDeath time.Duration) {
+	unitMetrics.CharacterIterationMetrics.Died = true
+	unitMetrics.CharacterIterationMetrics.TimeOfDeath = timeOfDeath
+}
+
 func (unitMetrics *UnitMetrics) reset() {
 	unitMetrics.dps.reset()
 	unitMetrics.threat.reset()
 	unitMetrics.dtps.reset()
+	unitMetrics.hps.reset()
+	unitMetrics.ohps.reset()
+	unitMetrics.timeline.reset()
 	unitMetrics.CharacterIterationMetrics = CharacterIterationMetrics{}
 
 	for _, resourceMetrics := range unitMetrics.resources {
 		resourceMetrics.reset()
 	}
+	for _, procMetrics := range unitMetrics.procs {
+		procMetrics.reset()
+	}
 }
 
 // This should be called when a Sim iteration is complete.
@@ -252,7 +370,61 @@ func (unitMetrics *UnitMetrics) doneIteration(encounterDurationSeconds float64)
 	unitMetrics.dps.doneIteration(encounterDurationSeconds)
 	unitMetrics.threat.doneIteration(encounterDurationSeconds)
 	unitMetrics.dtps.doneIteration(encounterDurationSeconds)
+	unitMetrics.hps.doneIteration(encounterDurationSeconds)
+	unitMetrics.ohps.doneIteration(encounterDurationSeconds)
 	unitMetrics.oomTimeSum += float64(unitMetrics.OOMTime.Seconds())
+
+	for _, procMetrics := range unitMetrics.procs {
+		procMetrics.doneIteration(encounterDurationSeconds)
+	}
+
+	if unitMetrics.Died {
+		unitMetrics.deaths++
+		unitMetrics.timeToDeath.addSample(unitMetrics.TimeOfDeath.Seconds(), 1)
+	}
+}
+
+// Adds the aggregate values from another UnitMetrics into this one.
+func (unitMetrics *UnitMetrics) merge(other *UnitMetrics) {
+	unitMetrics.dps.merge(&other.dps)
+	unitMetrics.threat.merge(&other.threat)
+	unitMetrics.dtps.merge(&other.dtps)
+	unitMetrics.hps.merge(&other.hps)
+	unitMetrics.ohps.merge(&other.ohps)
+	unitMetrics.oomTimeSum += other.oomTimeSum
+	unitMetrics.activeTimeSum += other.activeTimeSum
+	unitMetrics.timeToDeath.merge(&other.timeToDeath)
+	unitMetrics.deaths += other.deaths
+	unitMetrics.timeline.merge(&other.timeline)
+
+	for actionID, otherAction := range other.actions {
+		action, ok := unitMetrics.actions[actionID]
+		if !ok {
+			action = &ActionMetrics{IsMelee: otherAction.IsMelee}
+			unitMetrics.actions[actionID] = action
+		}
+		if len(action.Targets) == 0 {
+			action.Targets = make([]TargetedActionMetrics, len(otherAction.Targets))
+		}
+		for i := range otherAction.Targets {
+			action.Targets[i].merge(&otherAction.Targets[i])
+		}
+	}
+
+	for resourceKey, otherResource := range other.resources {
+		resource, ok := unitMetrics.resources[resourceKey]
+		if !ok {
+			resource = &ResourceMetrics{}
+			unitMetrics.resources[resourceKey] = resource
+		}
+		resource.Events += otherResource.Events
+		resource.Gain += otherResource.Gain
+		resource.ActualGain += otherResource.ActualGain
+	}
+
+	for actionID, otherProc := range other.procs {
+		unitMetrics.GetOrRegisterProcMetrics(actionID).merge(otherProc)
+	}
 }
 
 func (unitMetrics *UnitMetrics) ToProto(numIterations int32) *proto.UnitMetrics {
@@ -260,7 +432,15 @@ func (unitMetrics *UnitMetrics) ToProto(numIterations int32) *proto.UnitMetrics
 		Dps:           unitMetrics.dps.ToProto(numIterations),
 		Threat:        unitMetrics.threat.ToProto(numIterations),
 		Dtps:          unitMetrics.dtps.ToProto(numIterations),
+		Hps:           unitMetrics.hps.ToProto(numIterations),
+		Ohps:          unitMetrics.ohps.ToProto(numIterations),
 		SecondsOomAvg: unitMetrics.oomTimeSum / float64(numIterations),
+
+		PercentSurvived:  100 * (1 - float64(unitMetrics.deaths)/float64(numIterations)),
+		SecondsActiveAvg: unitMetrics.activeTimeSum / float64(numIterations),
+	}
+	if unitMetrics.deaths > 0 {
+		protoMetrics.TimeToDeath = unitMetrics.timeToDeath.ToProto(unitMetrics.deaths)
 	}
 
 	for actionID, action := range unitMetrics.actions {
@@ -269,6 +449,9 @@ func (unitMetrics *UnitMetrics) ToProto(numIterations int32) *proto.UnitMetrics
 	for rk, resource := range unitMetrics.resources {
 		protoMetrics.Resources = append(protoMetrics.Resources, resource.ToProto(rk.ActionID, rk.Type))
 	}
+	for _, procMetrics := range unitMetrics.procs {
+		protoMetrics.Procs = append(protoMetrics.Procs, procMetrics.ToProto(numIterations))
+	}
 
 	return protoMetrics
 }
@@ -277,21 +460,28 @@ type AuraMetrics struct {
 	ID ActionID
 
 	// Metrics for the current iteration.
-	Uptime time.Duration
+	Uptime          time.Duration
+	ChargesConsumed int32
 
 	// Aggregate values. These are updated after each iteration.
-	uptimeSum        time.Duration
-	uptimeSumSquared time.Duration
+	uptimeSum          time.Duration
+	uptimeSumSquared   time.Duration
+	chargesConsumedSum int32
+
+	// Seconds active within each timeline bucket, summed across iterations.
+	activeTimeSums []float64
 }
 
 func (auraMetrics *AuraMetrics) reset() {
 	auraMetrics.Uptime = 0
+	auraMetrics.ChargesConsumed = 0
 }
 
 // This should be called when a Sim iteration is complete.
 func (auraMetrics *AuraMetrics) doneIteration() {
 	auraMetrics.uptimeSum += auraMetrics.Uptime
 	auraMetrics.uptimeSumSquared += auraMetrics.Uptime * auraMetrics.Uptime
+	auraMetrics.chargesConsumedSum += auraMetrics.ChargesConsumed
 }
 
 func (auraMetrics *AuraMetrics) ToProto(numIterations int32) *proto.AuraMetrics {
@@ -302,9 +492,135 @@ func (auraMetrics *AuraMetrics) ToProto(numIterations int32) *proto.AuraMetrics
 
 		UptimeSecondsAvg:   uptimeAvg,
 		UptimeSecondsStdev: math.Sqrt((auraMetrics.uptimeSumSquared.Seconds() / float64(numIterations)) - (uptimeAvg * uptimeAvg)),
+
+		ChargesConsumedAvg: float64(auraMetrics.chargesConsumedSum) / float64(numIterations),
 	}
 }
 
+type ProcMetrics struct {
+	ID ActionID
+
+	// Metrics for the current iteration.
+	Procs int32
+	// Procs which only refreshed the proc's aura, because it was still active.
+	Refreshes int32
+	// Expected procs lost to the internal cooldown.
+	ICDBlockedProcs float64
+
+	// Stats currently given by the proc, and since when.
+	activeStats      stats.Stats
+	activeStatsSince time.Duration
+	// Stats given by the proc multiplied by the seconds they were active.
+	statSeconds stats.Stats
+
+	// Aggregate values. These are updated after each iteration.
+	procsSum           int32
+	refreshesSum       int32
+	icdBlockedProcsSum float64
+	statSecondsSum     stats.Stats
+	durationSecondsSum float64
+}
+
+func (procMetrics *ProcMetrics) AddProc() {
+	procMetrics.Procs++
+}
+
+func (procMetrics *ProcMetrics) AddRefresh() {
+	procMetrics.Refreshes++
+}
+
+// Records a trigger which couldn't proc because of the internal cooldown,
+// given the chance it would have had to proc.
+func (procMetrics *ProcMetrics) AddICDBlockedProc(procChance float64) {
+	procMetrics.ICDBlockedProcs += procChance
+}
+
+// Sets the stats currently given by the proc, e.g. its bonus when the proc
+// aura is gained and no stats when it expires.
+func (procMetrics *ProcMetrics) SetActiveStats(sim *Simulation, activeStats stats.Stats) {
+	elapsed := (sim.CurrentTime - procMetrics.activeStatsSince).Seconds()
+	procMetrics.statSeconds = procMetrics.statSeconds.Add(procMetrics.activeStats.Multiply(elapsed))
+	procMetrics.activeStats = activeStats
+	procMetrics.activeStatsSince = sim.CurrentTime
+}
+
+// Modifies the config of a proc's aura so that activations of the aura while
+// it is already active are recorded as refreshes, and its stats are recorded
+// as the proc's active stats, for statsPerStack times the aura's stacks (or
+// once, for auras without stacks). Procs themselves are recorded where they
+// are rolled, with AddProc or PPMManager.SetMetrics.
+func (procMetrics *ProcMetrics) TrackAura(config *Aura, statsPerStack stats.Stats) {
+	config.procMetrics = procMetrics
+
+	if config.MaxStacks > 0 {
+		oldOnStacksChange := config.OnStacksChange
+		config.OnStacksChange = func(aura *Aura, sim *Simulation, oldStacks int32, newStacks int32) {
+			if oldOnStacksChange != nil {
+				oldOnStacksChange(aura, sim, oldStacks, newStacks)
+			}
+			procMetrics.SetActiveStats(sim, statsPerStack.Multiply(float64(newStacks)))
+		}
+	} else {
+		oldOnGain := config.OnGain
+		config.OnGain = func(aura *Aura, sim *Simulation) {
+			if oldOnGain != nil {
+				oldOnGain(aura, sim)
+			}
+			procMetrics.SetActiveStats(sim, statsPerStack)
+		}
+	}
+
+	oldOnExpire := config.OnExpire
+	config.OnExpire = func(aura *Aura, sim *Simulation) {
+		if oldOnExpire != nil {
+			oldOnExpire(aura, sim)
+		}
+		procMetrics.SetActiveStats(sim, stats.Stats{})
+	}
+}
+
+func (procMetrics *ProcMetrics) reset() {
+	procMetrics.Procs = 0
+	procMetrics.Refreshes = 0
+	procMetrics.ICDBlockedProcs = 0
+	procMetrics.activeStats = stats.Stats{}
+	procMetrics.activeStatsSince = 0
+	procMetrics.statSeconds = stats.Stats{}
+}
+
+// This should be called when a Sim iteration is complete.
+func (procMetrics *ProcMetrics) doneIteration(encounterDurationSeconds float64) {
+	procMetrics.procsSum += procMetrics.Procs
+	procMetrics.refreshesSum += procMetrics.Refreshes
+	procMetrics.icdBlockedProcsSum += procMetrics.ICDBlockedProcs
+	procMetrics.statSecondsSum = procMetrics.statSecondsSum.Add(procMetrics.statSeconds)
+	procMetrics.durationSecondsSum += encounterDurationSeconds
+}
+
+func (procMetrics *ProcMetrics) merge(other *ProcMetrics) {
+	procMetrics.procsSum += other.procsSum
+	procMetrics.refreshesSum += other.refreshesSum
+	procMetrics.icdBlockedProcsSum += other.icdBlockedProcsSum
+	procMetrics.statSecondsSum = procMetrics.statSecondsSum.Add(other.statSecondsSum)
+	procMetrics.durationSecondsSum += other.durationSecondsSum
+}
+
+func (procMetrics *ProcMetrics) ToProto(numIterations int32) *proto.ProcMetrics {
+	protoMetrics := &proto.ProcMetrics{
+		Id: procMetrics.ID.ToProto(),
+
+		ProcsAvg:           float64(procMetrics.procsSum) / float64(numIterations),
+		RefreshesAvg:       float64(procMetrics.refreshesSum) / float64(numIterations),
+		IcdBlockedProcsAvg: procMetrics.icdBlockedProcsSum / float64(numIterations),
+	}
+	if procMetrics.durationSecondsSum > 0 {
+		protoMetrics.ProcsPerMinute = float64(procMetrics.procsSum) / (procMetrics.durationSecondsSum / 60)
+		statsAvg := procMetrics.statSecondsSum.Multiply(1 / procMetrics.durationSecondsSum)
+		protoMetrics.StatsAvg = statsAvg[:]
+	}
+	return protoMetrics
+}
+
 // Calculates DPS for an action.
 func GetActionDPS(playerMetrics proto.UnitMetrics, iterations int32, duration time.Duration, actionID ActionID, ignoreTag bool) float64 {
 	totalDPS := 0.0
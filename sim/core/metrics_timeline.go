@@ -0,0 +1,259 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+const DefaultTimelineBucketSize = time.Second * 5
+
+// Resources which are tracked in timelines.
+var timelineResourceTypes = []proto.ResourceType{
+	proto.ResourceType_ResourceTypeMana,
+	proto.ResourceType_ResourceTypeRage,
+	proto.ResourceType_ResourceTypeEnergy,
+}
+
+// Returns the index of the resource in timelineResourceTypes, or -1.
+func timelineResourceIndex(resourceType proto.ResourceType) int {
+	for i, timelineType := range timelineResourceTypes {
+		if timelineType == resourceType {
+			return i
+		}
+	}
+	return -1
+}
+
+// Resource levels during the current iteration.
+type timelineResource struct {
+	// Whether this resource changed in any iteration.
+	used bool
+
+	// Level before the first change in this iteration.
+	initialLevel float64
+	hasChanged   bool
+
+	// Latest level within each bucket, if it changed in that bucket.
+	levels  []float64
+	changed []bool
+
+	// Aggregate values. These are updated after each iteration.
+	levelSums []float64
+}
+
+// Time-bucketed metrics for 1 unit.
+type TimelineMetrics struct {
+	bucketSize time.Duration
+
+	// Damage in each bucket for the current iteration.
+	damage []float64
+
+	// Indexed like timelineResourceTypes.
+	resources [3]timelineResource
+
+	// Aggregate values. These are updated after each iteration.
+	damageSums []float64
+
+	// Total seconds of all iterations which fell into each bucket, and the
+	// number of iterations which reached each bucket.
+	coveredSums     []float64
+	iterationCounts []int32
+}
+
+func (timeline *TimelineMetrics) bucket(t time.Duration) int {
+	return int(t / timeline.bucketSize)
+}
+
+// Returns the slice with enough elements to index bucket.
+func growTimeline(values []float64, bucket int) []float64 {
+	for len(values) <= bucket {
+		values = append(values, 0)
+	}
+	return values
+}
+
+func (timeline *TimelineMetrics) addDamage(t time.Duration, damage float64) {
+	bucket := timeline.bucket(t)
+	timeline.damage = growTimeline(timeline.damage, bucket)
+	timeline.damage[bucket] += damage
+}
+
+// Records a resource change, given the level before the change.
+func (timeline *TimelineMetrics) addResourceLevel(t time.Duration, resourceType proto.ResourceType, oldLevel float64, actualGain float64) {
+	resourceIdx := timelineResourceIndex(resourceType)
+	if resourceIdx == -1 {
+		return
+	}
+	resource := &timeline.resources[resourceIdx]
+	bucket := timeline.bucket(t)
+
+	resource.used = true
+	if !resource.hasChanged {
+		resource.initialLevel = oldLevel
+		resource.hasChanged = true
+	}
+	resource.levels = growTimeline(resource.levels, bucket)
+	for len(resource.changed) <= bucket {
+		resource.changed = append(resource.changed, false)
+	}
+	resource.levels[bucket] = oldLevel + actualGain
+	resource.changed[bucket] = true
+}
+
+// Adds the time the aura was active to the aura's timeline sums.
+func (timeline *TimelineMetrics) addAuraUptime(auraMetrics *AuraMetrics, start time.Duration, end time.Duration) {
+	if end <= start {
+		return
+	}
+	bucketSize := timeline.bucketSize
+	for bucket := timeline.bucket(start); time.Duration(bucket)*bucketSize < end; bucket++ {
+		bucketStart := time.Duration(bucket) * bucketSize
+		overlap := MinDuration(end, bucketStart+bucketSize) - MaxDuration(start, bucketStart)
+		auraMetrics.activeTimeSums = growTimeline(auraMetrics.activeTimeSums, bucket)
+		auraMetrics.activeTimeSums[bucket] += overlap.Seconds()
+	}
+}
+
+// Adds a pet's damage for the current iteration into its owner's timeline.
+func (timeline *TimelineMetrics) addPetTimeline(petTimeline *TimelineMetrics) {
+	for bucket, damage := range petTimeline.damage {
+		timeline.damage = growTimeline(timeline.damage, bucket)
+		timeline.damage[bucket] += damage
+	}
+}
+
+func (timeline *TimelineMetrics) reset() {
+	for i := range timeline.damage {
+		timeline.damage[i] = 0
+	}
+	for i := range timeline.resources {
+		resource := &timeline.resources[i]
+		resource.hasChanged = false
+		resource.levels = resource.levels[:0]
+		resource.changed = resource.changed[:0]
+	}
+}
+
+// This should be called when a Sim iteration is complete.
+func (timeline *TimelineMetrics) doneIteration(duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	lastBucket := timeline.bucket(duration - 1)
+
+	timeline.coveredSums = growTimeline(timeline.coveredSums, lastBucket)
+	for len(timeline.iterationCounts) <= lastBucket {
+		timeline.iterationCounts = append(timeline.iterationCounts, 0)
+	}
+	for bucket := 0; bucket <= lastBucket; bucket++ {
+		bucketStart := time.Duration(bucket) * timeline.bucketSize
+		timeline.coveredSums[bucket] += (MinDuration(duration, bucketStart+timeline.bucketSize) - bucketStart).Seconds()
+		timeline.iterationCounts[bucket]++
+	}
+
+	timeline.damageSums = growTimeline(timeline.damageSums, lastBucket)
+	for bucket, damage := range timeline.damage {
+		if bucket <= lastBucket {
+			timeline.damageSums[bucket] += damage
+		}
+	}
+
+	for i := range timeline.resources {
+		resource := &timeline.resources[i]
+		if !resource.used {
+			continue
+		}
+		resource.levelSums = growTimeline(resource.levelSums, lastBucket)
+		level := resource.initialLevel
+		for bucket := 0; bucket <= lastBucket; bucket++ {
+			if bucket < len(resource.changed) && resource.changed[bucket] {
+				level = resource.levels[bucket]
+			}
+			resource.levelSums[bucket] += level
+		}
+	}
+}
+
+// Adds the aggregate values from another TimelineMetrics into this one.
+func (timeline *TimelineMetrics) merge(other *TimelineMetrics) {
+	timeline.damageSums = mergeTimeline(timeline.damageSums, other.damageSums)
+	timeline.coveredSums = mergeTimeline(timeline.coveredSums, other.coveredSums)
+	for bucket, count := range other.iterationCounts {
+		for len(timeline.iterationCounts) <= bucket {
+			timeline.iterationCounts = append(timeline.iterationCounts, 0)
+		}
+		timeline.iterationCounts[bucket] += count
+	}
+	for i := range timeline.resources {
+		timeline.resources[i].used = timeline.resources[i].used || other.resources[i].used
+		timeline.resources[i].levelSums = mergeTimeline(timeline.resources[i].levelSums, other.resources[i].levelSums)
+	}
+}
+
+func mergeTimeline(values []float64, other []float64) []float64 {
+	for bucket, value := range other {
+		values = growTimeline(values, bucket)
+		values[bucket] += value
+	}
+	return values
+}
+
+// Divides each sum by the matching value in divisors, skipping empty buckets.
+func averageTimeline(sums []float64, divisor func(bucket int) float64) []float64 {
+	averages := make([]float64, len(sums))
+	for bucket, sum := range sums {
+		if d := divisor(bucket); d > 0 {
+			averages[bucket] = sum / d
+		}
+	}
+	return averages
+}
+
+func (timeline *TimelineMetrics) ToProto(auras []*Aura) *proto.UnitTimeline {
+	covered := func(bucket int) float64 {
+		if bucket >= len(timeline.coveredSums) {
+			return 0
+		}
+		return timeline.coveredSums[bucket]
+	}
+	iterations := func(bucket int) float64 {
+		if bucket >= len(timeline.iterationCounts) {
+			return 0
+		}
+		return float64(timeline.iterationCounts[bucket])
+	}
+
+	timelineProto := &proto.UnitTimeline{
+		BucketSize: timeline.bucketSize.Seconds(),
+		Dps:        averageTimeline(timeline.damageSums, covered),
+	}
+
+	for i, resourceType := range timelineResourceTypes {
+		resource := &timeline.resources[i]
+		if !resource.used {
+			continue
+		}
+		levels := averageTimeline(resource.levelSums, iterations)
+		switch resourceType {
+		case proto.ResourceType_ResourceTypeMana:
+			timelineProto.Mana = levels
+		case proto.ResourceType_ResourceTypeRage:
+			timelineProto.Rage = levels
+		case proto.ResourceType_ResourceTypeEnergy:
+			timelineProto.Energy = levels
+		}
+	}
+
+	for _, aura := range auras {
+		if aura.metrics.ID.IsEmptyAction() || len(aura.metrics.activeTimeSums) == 0 {
+			continue
+		}
+		timelineProto.Auras = append(timelineProto.Auras, &proto.AuraTimeline{
+			Id:                aura.metrics.ID.ToProto(),
+			ActiveProbability: averageTimeline(growTimeline(aura.metrics.activeTimeSums, len(timeline.coveredSums)-1), covered),
+		})
+	}
+
+	return timelineProto
+}
@@ -30,6 +30,13 @@ type PendingAction struct {
 	CleanUp  func(*Simulation)
 
 	cancelled bool
+
+	// Position in the pending action queue, plus 1. 0 if not queued.
+	queueIndex int
+
+	// Breaks ties between actions with the same time and priority, so they
+	// happen in the order they were added.
+	seq uint64
 }
 
 func (pa *PendingAction) Cancel(sim *Simulation) {
@@ -43,4 +50,123 @@ func (pa *PendingAction) Cancel(sim *Simulation) {
 	}
 
 	pa.cancelled = true
+	sim.pendingActions.remove(pa)
+}
+
+// A binary min-heap of pending actions. Actions are ordered by time, then by
+// priority (highest first), then by the order in which they were added.
+type pendingActionQueue struct {
+	actions []*PendingAction
+	nextSeq uint64
+}
+
+func (queue *pendingActionQueue) Len() int {
+	return len(queue.actions)
+}
+
+func (queue *pendingActionQueue) less(i, j int) bool {
+	a, b := queue.actions[i], queue.actions[j]
+	if a.NextActionAt != b.NextActionAt {
+		return a.NextActionAt < b.NextActionAt
+	}
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.seq < b.seq
+}
+
+func (queue *pendingActionQueue) swap(i, j int) {
+	queue.actions[i], queue.actions[j] = queue.actions[j], queue.actions[i]
+	queue.actions[i].queueIndex = i + 1
+	queue.actions[j].queueIndex = j + 1
+}
+
+func (queue *pendingActionQueue) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !queue.less(i, parent) {
+			return
+		}
+		queue.swap(i, parent)
+		i = parent
+	}
+}
+
+func (queue *pendingActionQueue) down(i int) {
+	n := len(queue.actions)
+	for {
+		smallest := i
+		if left := 2*i + 1; left < n && queue.less(left, smallest) {
+			smallest = left
+		}
+		if right := 2*i + 2; right < n && queue.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		queue.swap(i, smallest)
+		i = smallest
+	}
+}
+
+func (queue *pendingActionQueue) push(pa *PendingAction) {
+	if pa.queueIndex != 0 {
+		panic("Pending action is already queued!")
+	}
+
+	pa.seq = queue.nextSeq
+	queue.nextSeq++
+
+	queue.actions = append(queue.actions, pa)
+	pa.queueIndex = len(queue.actions)
+	queue.up(len(queue.actions) - 1)
+}
+
+// Removes and returns the next action, or nil if the queue is empty.
+func (queue *pendingActionQueue) pop() *PendingAction {
+	n := len(queue.actions)
+	if n == 0 {
+		return nil
+	}
+
+	queue.swap(0, n-1)
+	pa := queue.actions[n-1]
+	queue.actions[n-1] = nil
+	queue.actions = queue.actions[:n-1]
+	queue.down(0)
+
+	pa.queueIndex = 0
+	return pa
+}
+
+// Removes an action from the queue, if it's in it.
+func (queue *pendingActionQueue) remove(pa *PendingAction) {
+	if pa.queueIndex == 0 {
+		return
+	}
+
+	i := pa.queueIndex - 1
+	last := len(queue.actions) - 1
+	if i != last {
+		queue.swap(i, last)
+	}
+	queue.actions[last] = nil
+	queue.actions = queue.actions[:last]
+	if i != last {
+		queue.down(i)
+		queue.up(i)
+	}
+
+	pa.queueIndex = 0
+}
+
+// Removes all actions from the queue.
+func (queue *pendingActionQueue) clear() {
+	for i, pa := range queue.actions {
+		pa.queueIndex = 0
+		queue.actions[i] = nil
+	}
+	queue.actions = queue.actions[:0]
+	queue.nextSeq = 0
 }
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingActionQueueOrder(t *testing.T) {
+	sim := &Simulation{}
+	rand := NewSplitMix(1)
+
+	var actions []*PendingAction
+	for i := 0; i < 1000; i++ {
+		pa := &PendingAction{
+			NextActionAt: time.Duration(rand.Next()%50) * time.Second,
+			Priority:     ActionPriority(int(rand.Next()%5) - 1),
+		}
+		actions = append(actions, pa)
+		sim.AddPendingAction(pa)
+	}
+
+	// Cancelled actions are removed immediately.
+	for i := 0; i < len(actions); i += 3 {
+		actions[i].Cancel(sim)
+	}
+	if expected := len(actions) - (len(actions)+2)/3; sim.pendingActions.Len() != expected {
+		t.Fatalf("Expected %d actions after cancelling, got %d", expected, sim.pendingActions.Len())
+	}
+
+	var prev *PendingAction
+	for pa := sim.pendingActions.pop(); pa != nil; pa = sim.pendingActions.pop() {
+		if pa.cancelled {
+			t.Fatalf("Popped a cancelled action")
+		}
+		if prev != nil {
+			if pa.NextActionAt < prev.NextActionAt {
+				t.Fatalf("Actions out of time order: %s after %s", pa.NextActionAt, prev.NextActionAt)
+			}
+			if pa.NextActionAt == prev.NextActionAt {
+				if pa.Priority > prev.Priority {
+					t.Fatalf("Actions out of priority order: %d after %d", pa.Priority, prev.Priority)
+				}
+				if pa.Priority == prev.Priority && pa.seq < prev.seq {
+					t.Fatalf("Actions with the same time and priority out of insertion order")
+				}
+			}
+		}
+		prev = pa
+	}
+}
+
+func TestCleanUpPendingActionsWithCancel(t *testing.T) {
+	sim := &Simulation{}
+
+	cleanedUp := map[*PendingAction]int{}
+	actions := make([]*PendingAction, 20)
+	for i := range actions {
+		actions[i] = &PendingAction{NextActionAt: time.Duration(i) * time.Second}
+	}
+	for i, pa := range actions {
+		pa := pa
+		var next *PendingAction
+		if i%2 == 0 {
+			next = actions[i+1]
+		}
+		pa.CleanUp = func(sim *Simulation) {
+			cleanedUp[pa]++
+			// Cancelling another queued action changes the queue while it is
+			// being cleaned up.
+			if next != nil {
+				next.Cancel(sim)
+			}
+		}
+		sim.AddPendingAction(pa)
+	}
+
+	sim.cleanUpPendingActions()
+
+	if sim.pendingActions.Len() != 0 {
+		t.Fatalf("Expected an empty queue after clean up, got %d actions", sim.pendingActions.Len())
+	}
+	for i, pa := range actions {
+		if cleanedUp[pa] != 1 {
+			t.Fatalf("Expected action %d to be cleaned up once, got %d", i, cleanedUp[pa])
+		}
+	}
+}
+
+// Simulates the queue churn of a large raid: each popped action is re-added
+// later, and some actions are cancelled and replaced, like auto attacks after
+// a haste change.
+func BenchmarkPendingActionQueue(b *testing.B) {
+	const numActions = 300
+
+	sim := &Simulation{}
+	rand := NewSplitMix(1)
+	actions := make([]*PendingAction, numActions)
+	for i := range actions {
+		actions[i] = &PendingAction{
+			NextActionAt: time.Duration(rand.Next()%3000) * time.Millisecond,
+		}
+		sim.AddPendingAction(actions[i])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pa := sim.pendingActions.pop()
+		pa.NextActionAt += time.Duration(rand.Next()%3000) * time.Millisecond
+		sim.AddPendingAction(pa)
+
+		if i%4 == 0 {
+			victim := actions[rand.Next()%numActions]
+			if victim.queueIndex != 0 {
+				victim.Cancel(sim)
+				replacement := &PendingAction{NextActionAt: victim.NextActionAt}
+				actions[rand.Next()%numActions] = replacement
+				sim.AddPendingAction(replacement)
+			}
+		}
+	}
+}
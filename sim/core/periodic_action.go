@@ -14,6 +14,9 @@ type PeriodicActionOptions struct {
 
 	Priority ActionPriority
 
+	// The unit performing the action, if any. The action stops if it dies.
+	Unit *Unit
+
 	OnAction func(*Simulation)
 	CleanUp  func(*Simulation)
 }
@@ -44,6 +47,10 @@ func NewPeriodicAction(sim *Simulation, options PeriodicActionOptions) *PendingA
 		}
 	}
 
+	if options.Unit != nil {
+		options.Unit.periodicActions = append(options.Unit.periodicActions, pa)
+	}
+
 	return pa
 }
 
@@ -31,9 +31,22 @@ type PresimOptions struct {
 	OnPresimResult func(presimResult proto.UnitMetrics, iterations int32, duration time.Duration) bool
 }
 
-func (sim *Simulation) runPresims(request proto.RaidSimRequest) {
-	const numPresimIterations = 100
+const numPresimIterations = 100
+
+// Returns a copy of request with the options used for presims. Presims always
+// use the same seed so their results can be compared with each other.
+func newPresimRequest(request proto.RaidSimRequest) *proto.RaidSimRequest {
+	presimRequest := googleProto.Clone(&request).(*proto.RaidSimRequest)
+	presimRequest.SimOptions.RandomSeed = 1
+	presimRequest.SimOptions.Debug = false
+	presimRequest.SimOptions.DebugFirstIteration = false
+	presimRequest.SimOptions.CombatLog = false
+	presimRequest.SimOptions.Iterations = numPresimIterations
+	presimRequest.SimOptions.TargetDpsStderr = 0
+	return presimRequest
+}
 
+func (sim *Simulation) runPresims(request proto.RaidSimRequest) {
 	// Run presims if requested.
 	raidPresimOptions := make([]*PresimOptions, 25)
 	remainingAgents := 0
@@ -58,11 +71,7 @@ func (sim *Simulation) runPresims(request proto.RaidSimRequest) {
 	// Define this outside the loop so that, as Agents iteratively update their
 	// settings, we keep the most recent settings even after that Agent is
 	// done with presims.
-	presimRequest := googleProto.Clone(&request).(*proto.RaidSimRequest)
-	presimRequest.SimOptions.RandomSeed = 1
-	presimRequest.SimOptions.Debug = false
-	presimRequest.SimOptions.DebugFirstIteration = false
-	presimRequest.SimOptions.Iterations = numPresimIterations
+	presimRequest := newPresimRequest(request)
 	duration := DurationFromSeconds(presimRequest.Encounter.Duration)
 
 	for remainingAgents > 0 {
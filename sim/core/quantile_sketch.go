@@ -0,0 +1,102 @@
+package core
+
+import (
+	"math"
+	"sort"
+)
+
+// Relative accuracy of the quantiles estimated by quantileSketch.
+const quantileSketchAccuracy = 0.001
+
+// Values at or below this are counted as 0.
+const quantileSketchMinValue = 1e-9
+
+var quantileSketchGamma = (1 + quantileSketchAccuracy) / (1 - quantileSketchAccuracy)
+var quantileSketchLogGamma = math.Log(quantileSketchGamma)
+
+// Estimates quantiles with bounded memory, in the style of DDSketch. Values are
+// counted in buckets whose bounds grow geometrically, so each quantile is within
+// quantileSketchAccuracy of the exact one (relative to its value), and the
+// number of buckets only depends on the range of the values rather than on how
+// many values there are. Sketches are merged by adding their bucket counts, so
+// the result doesn't depend on how iterations were split across workers.
+//
+// Values are expected to be non-negative, as for all the sim's metrics.
+type quantileSketch struct {
+	buckets map[int32]int32 // bucket index to count
+	zeros   int32
+
+	count int32
+	min   float64
+	max   float64
+}
+
+func newQuantileSketch() quantileSketch {
+	return quantileSketch{
+		buckets: make(map[int32]int32),
+	}
+}
+
+func (sketch *quantileSketch) add(value float64) {
+	if sketch.count == 0 || value < sketch.min {
+		sketch.min = value
+	}
+	if sketch.count == 0 || value > sketch.max {
+		sketch.max = value
+	}
+	sketch.count++
+
+	if value <= quantileSketchMinValue {
+		sketch.zeros++
+		return
+	}
+	sketch.buckets[int32(math.Ceil(math.Log(value)/quantileSketchLogGamma))]++
+}
+
+func (sketch *quantileSketch) merge(other *quantileSketch) {
+	if other.count == 0 {
+		return
+	}
+	if sketch.count == 0 || other.min < sketch.min {
+		sketch.min = other.min
+	}
+	if sketch.count == 0 || other.max > sketch.max {
+		sketch.max = other.max
+	}
+	sketch.count += other.count
+	sketch.zeros += other.zeros
+	for index, count := range other.buckets {
+		sketch.buckets[index] += count
+	}
+}
+
+// Returns the estimated values at each of the given quantiles (0-1), which must
+// be in increasing order.
+func (sketch *quantileSketch) quantiles(ps ...float64) []float64 {
+	values := make([]float64, len(ps))
+	if sketch.count == 0 {
+		return values
+	}
+
+	indices := make([]int32, 0, len(sketch.buckets))
+	for index := range sketch.buckets {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	// Walks the buckets once, in value order, finding the bucket which holds the
+	// value ranked at each quantile.
+	i := 0
+	seen := sketch.zeros
+	bucketValue := 0.0
+	for pIdx, p := range ps {
+		rank := int32(p * float64(sketch.count-1))
+		for seen <= rank && i < len(indices) {
+			seen += sketch.buckets[indices[i]]
+			bucketValue = 2 * math.Pow(quantileSketchGamma, float64(indices[i])) / (quantileSketchGamma + 1)
+			i++
+		}
+		values[pIdx] = math.Min(math.Max(bucketValue, sketch.min), sketch.max)
+	}
+	return values
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestQuantileSketch(t *testing.T) {
+	x := SplitMix64{42}
+	whole := newQuantileSketch()
+	halves := [2]quantileSketch{newQuantileSketch(), newQuantileSketch()}
+	values := make([]float64, 100_000)
+	for i := range values {
+		// Roughly the shape of a DPS distribution.
+		values[i] = 1500 + 200*(x.NextFloat64()+x.NextFloat64()+x.NextFloat64())
+		whole.add(values[i])
+		halves[i%2].add(values[i])
+	}
+	halves[0].merge(&halves[1])
+	sort.Float64s(values)
+
+	ps := []float64{0, 0.05, 0.5, 0.95, 1}
+	estimates := whole.quantiles(ps...)
+	merged := halves[0].quantiles(ps...)
+	for i, p := range ps {
+		exact := values[int(p*float64(len(values)-1))]
+		if math.Abs(estimates[i]-exact) > exact*quantileSketchAccuracy {
+			t.Errorf("p%0.0f: expected %0.3f within %0.1f%%, got %0.3f", p*100, exact, quantileSketchAccuracy*100, estimates[i])
+		}
+		if merged[i] != estimates[i] {
+			t.Errorf("p%0.0f: expected merged sketches to match, got %0.3f vs %0.3f", p*100, merged[i], estimates[i])
+		}
+	}
+
+	// Memory is bounded by the range of the values.
+	if len(whole.buckets) > 1000 {
+		t.Errorf("Expected a bounded number of buckets, got %d", len(whole.buckets))
+	}
+}
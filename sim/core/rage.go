@@ -99,7 +99,7 @@ func (rb *rageBar) AddRage(sim *Simulation, amount float64, actionID ActionID) {
 	}
 
 	newRage := MinFloat(rb.currentRage+amount, MaxRage)
-	rb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeRage, amount, newRage-rb.currentRage)
+	rb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeRage, amount, newRage-rb.currentRage)
 
 	if sim.Log != nil {
 		rb.unit.Log(sim, "Gained %0.3f rage from %s (%0.3f --> %0.3f).", amount, actionID, rb.currentRage, newRage)
@@ -115,7 +115,7 @@ func (rb *rageBar) SpendRage(sim *Simulation, amount float64, actionID ActionID)
 	}
 
 	newRage := rb.currentRage - amount
-	rb.unit.Metrics.AddResourceEvent(actionID, proto.ResourceType_ResourceTypeRage, -amount, -amount)
+	rb.unit.addResourceEvent(sim, actionID, proto.ResourceType_ResourceTypeRage, -amount, -amount)
 
 	if sim.Log != nil {
 		rb.unit.Log(sim, "Spent %0.3f rage from %s (%0.3f --> %0.3f).", amount, actionID, rb.currentRage, newRage)
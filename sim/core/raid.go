@@ -174,6 +174,14 @@ func (raid *Raid) IsFull() bool {
 	return raid.Size() >= 25
 }
 
+// Number of distinct unit indices used by this raid, including pets.
+func (raid *Raid) numUnitSlots() int {
+	if len(raid.AllUnits) == 0 {
+		return 0
+	}
+	return int(raid.AllUnits[len(raid.AllUnits)-1].Index) + 1
+}
+
 func (raid *Raid) getNextPetIndex() int32 {
 	petIndex := raid.nextPetIndex
 	raid.nextPetIndex++
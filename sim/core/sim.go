@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"strings"
@@ -20,8 +21,12 @@ type Simulation struct {
 	isTest    bool
 	testRands map[string]Rand
 
+	// Mixed into the per-label test seeds, so concurrent workers in test mode
+	// don't all produce the same random streams. Always 0 for serial sims.
+	testRandSalt uint64
+
 	// Current Simulation State
-	pendingActions []*PendingAction
+	pendingActions pendingActionQueue
 	CurrentTime    time.Duration // duration that has elapsed in the sim since starting
 	Duration       time.Duration // Duration of current iteration
 
@@ -30,19 +35,48 @@ type Simulation struct {
 	Log  func(string, ...interface{})
 	logs []string
 
+	// Structured combat log, see SimOptions.CombatLog.
+	logCombatEvents bool
+	combatLogEvents []*proto.CombatLogEvent
+
+	// Index of the current iteration.
+	iteration int32
+
 	executePhase          bool
 	executePhaseCallbacks []func(*Simulation)
+
+	targetSpawnCallbacks   []func(*Simulation, *Target)
+	targetDespawnCallbacks []func(*Simulation, *Target)
 }
 
 func RunSim(rsr proto.RaidSimRequest, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	return RunSimWithContext(context.Background(), rsr, progress)
+}
+
+// Like RunSim, but stops early if ctx is cancelled. The final result then only
+// has an error message.
+func RunSimWithContext(ctx context.Context, rsr proto.RaidSimRequest, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	if err := validateRaidTalents(rsr.Raid); err != nil {
+		return errorSimResult(err.Error(), progress)
+	}
+	if rsr.SimOptions.TrinketContributions {
+		return runSimWithTrinketContributions(ctx, &rsr, progress)
+	}
+	if useConcurrentSim(rsr) {
+		return runConcurrentSim(ctx, rsr, progress)
+	}
+
 	sim := NewSim(rsr)
+	if err := sim.validate(); err != nil {
+		return errorSimResult(err.Error(), progress)
+	}
 	sim.runPresims(rsr)
 	if progress != nil {
 		sim.ProgressReport = func(progMetric *proto.ProgressMetrics) {
 			progress <- progMetric
 		}
 	}
-	return sim.run()
+	return sim.run(ctx)
 }
 
 func NewSim(rsr proto.RaidSimRequest) *Simulation {
@@ -76,7 +110,7 @@ func (sim *Simulation) RandomFloat(label string) float64 {
 
 	labelRand, isPresent := sim.testRands[label]
 	if !isPresent {
-		labelRand = NewSplitMix(uint64(hash(label)))
+		labelRand = NewSplitMix(uint64(hash(label)) ^ sim.testRandSalt)
 		sim.testRands[label] = labelRand
 	}
 	v := labelRand.NextFloat64()
@@ -97,15 +131,22 @@ func (sim *Simulation) reset() {
 		sim.Log("SIM RESET")
 		sim.Log("----------------------")
 	}
-	variation := sim.DurationVariation * 2
-
-	sim.Duration = sim.BaseDuration + time.Duration((sim.RandomFloat("sim duration") * float64(variation))) - sim.DurationVariation
+	if sim.Encounter.UseHealth {
+		// The real duration depends on how quickly the targets die, so use the
+		// current estimate.
+		sim.Duration = sim.BaseDuration
+	} else {
+		variation := sim.DurationVariation * 2
+		sim.Duration = sim.BaseDuration + time.Duration((sim.RandomFloat("sim duration") * float64(variation))) - sim.DurationVariation
+	}
 	sim.CurrentTime = 0.0
 
-	sim.pendingActions = make([]*PendingAction, 0, 64)
+	sim.pendingActions.clear()
 
 	sim.executePhase = false
 	sim.executePhaseCallbacks = []func(*Simulation){}
+	sim.targetSpawnCallbacks = nil
+	sim.targetDespawnCallbacks = nil
 
 	// Targets need to be reset before the raid, so that players can check for
 	// the presence of permanent target auras in their Reset handlers.
@@ -114,13 +155,107 @@ func (sim *Simulation) reset() {
 	}
 
 	sim.Raid.reset(sim)
+	sim.Encounter.resetPhases(sim)
+	sim.Encounter.resetMovement(sim)
 
 	sim.initManaTickAction()
 }
 
 // Run runs the simulation for the configured number of iterations, and
 // collects all the metrics together.
-func (sim *Simulation) run() *proto.RaidSimResult {
+func (sim *Simulation) run(ctx context.Context) *proto.RaidSimResult {
+	logs, firstIterationDuration, numIterations := sim.runIterations(ctx)
+
+	var result *proto.RaidSimResult
+	if ctx.Err() != nil {
+		result = cancelledSimResult()
+	} else {
+		result = sim.getResult(numIterations, logs, firstIterationDuration)
+	}
+
+	// Final progress report
+	if sim.ProgressReport != nil {
+		finalProgress := &proto.ProgressMetrics{TotalIterations: numIterations, CompletedIterations: numIterations, FinalRaidResult: result}
+		if result.RaidMetrics != nil {
+			finalProgress.Dps = result.RaidMetrics.Dps.Avg
+		}
+		sim.ProgressReport(finalProgress)
+	}
+
+	return result
+}
+
+func cancelledSimResult() *proto.RaidSimResult {
+	return &proto.RaidSimResult{
+		ErrorMsg: "Sim cancelled",
+	}
+}
+
+// Returns a result with only an error message, for a request which can't be
+// simmed. It's also sent as the final progress report.
+func errorSimResult(errorMsg string, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	result := &proto.RaidSimResult{
+		ErrorMsg: errorMsg,
+	}
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{FinalRaidResult: result}
+	}
+	return result
+}
+
+// Default minimum number of iterations when SimOptions.TargetDpsStderr is set.
+const defaultMinAdaptiveIterations = 100
+
+// Returns the minimum and maximum number of iterations to run. These are both
+// SimOptions.Iterations, unless a stderr target is set.
+func iterationCaps(options *proto.SimOptions) (int32, int32) {
+	if options.TargetDpsStderr <= 0 {
+		return options.Iterations, options.Iterations
+	}
+
+	maxIterations := options.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = options.Iterations
+	}
+	minIterations := options.MinIterations
+	if minIterations <= 0 {
+		minIterations = defaultMinAdaptiveIterations
+	}
+	// Stderr is meaningless with a single sample.
+	minIterations = MaxInt32(minIterations, 2)
+
+	return MinInt32(minIterations, maxIterations), maxIterations
+}
+
+// Checks the environment and options for errors which should fail the request
+// before any iterations are run.
+func (sim *Simulation) validate() error {
+	if err := sim.Environment.validate(); err != nil {
+		return err
+	}
+	if sim.Options.TargetDpsStderrPlayer != nil && sim.Raid.GetPlayerFromRaidTarget(*sim.Options.TargetDpsStderrPlayer) == nil {
+		return fmt.Errorf("Invalid target_dps_stderr_player: %d", sim.Options.TargetDpsStderrPlayer.TargetIndex)
+	}
+	return nil
+}
+
+// Returns the DPS metrics which are checked against SimOptions.TargetDpsStderr.
+// The target player has already been checked by validate.
+func (sim *Simulation) stderrDpsMetrics() *DistributionMetrics {
+	if sim.Options.TargetDpsStderrPlayer == nil {
+		return &sim.Raid.dpsMetrics
+	}
+
+	agent := sim.Raid.GetPlayerFromRaidTarget(*sim.Options.TargetDpsStderrPlayer)
+	return &agent.GetCharacter().Metrics.dps
+}
+
+// Runs all the iterations without building the final result, so that metrics
+// from multiple Simulations can be merged beforehand.
+//
+// Returns the debug logs, the duration of the first iteration, and the number
+// of iterations which were run. Stops early if ctx is cancelled.
+func (sim *Simulation) runIterations(ctx context.Context) (string, time.Duration, int32) {
 	logsBuffer := &strings.Builder{}
 	if sim.Options.Debug || sim.Options.DebugFirstIteration {
 		sim.Log = func(message string, vals ...interface{}) {
@@ -149,53 +284,85 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		}
 	}
 
+	sim.logCombatEvents = sim.Options.CombatLog
+
+	sim.iteration = 0
 	sim.runOnce()
 	firstIterationDuration := sim.Duration
 
 	if !sim.Options.Debug {
 		sim.Log = nil
+		sim.logCombatEvents = false
+	}
+
+	minIterations, maxIterations := iterationCaps(&sim.Options)
+	var stderrMetrics *DistributionMetrics
+	if sim.Options.TargetDpsStderr > 0 {
+		stderrMetrics = sim.stderrDpsMetrics()
 	}
 
 	st := time.Now()
-	for i := int32(1); i < sim.Options.Iterations; i++ {
-		// fmt.Printf("Iteration: %d\n", i)
+	numIterations := int32(1)
+	for ; numIterations < maxIterations; numIterations++ {
+		if stderrMetrics != nil && numIterations >= minIterations && stderrMetrics.stderr(numIterations) <= sim.Options.TargetDpsStderr {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		// fmt.Printf("Iteration: %d\n", numIterations)
 		if sim.ProgressReport != nil && time.Since(st) > time.Millisecond*100 {
-			metrics := sim.Raid.GetMetrics(i + 1)
-			sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: i + 1, Dps: metrics.Dps.Avg})
+			metrics := sim.Raid.GetMetrics(numIterations + 1)
+			sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: maxIterations, CompletedIterations: numIterations + 1, Dps: metrics.Dps.Avg})
 			runtime.Gosched() // ensure that reporting threads are given time to report, mostly only important in wasm (only 1 thread)
 			st = time.Now()
 		}
+		sim.iteration = numIterations
 		sim.runOnce()
 	}
+
+	return logsBuffer.String(), firstIterationDuration, numIterations
+}
+
+func (sim *Simulation) getResult(numIterations int32, logs string, firstIterationDuration time.Duration) *proto.RaidSimResult {
 	result := &proto.RaidSimResult{
-		RaidMetrics:      sim.Raid.GetMetrics(sim.Options.Iterations),
-		EncounterMetrics: sim.Encounter.GetMetricsProto(sim.Options.Iterations),
+		RaidMetrics:      sim.Raid.GetMetrics(numIterations),
+		EncounterMetrics: sim.Encounter.GetMetricsProto(numIterations),
 
-		Logs:                   logsBuffer.String(),
+		Logs:                   logs,
 		FirstIterationDuration: firstIterationDuration.Seconds(),
 	}
-
-	// Final progress report
-	if sim.ProgressReport != nil {
-		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: sim.Options.Iterations, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
+	if sim.Options.CombatLog {
+		result.CombatLog = sim.combatLogEvents
+	}
+	if sim.Encounter.UseHealth && sim.Encounter.numKillTimeSamples > 0 {
+		result.KillTime = sim.Encounter.killTimeMetrics.ToProto(sim.Encounter.numKillTimeSamples)
 	}
-
 	return result
 }
 
+// Calls CleanUp on every action still in the queue at the end of an iteration.
+// The queue is drained with pop rather than ranged over, because a CleanUp may
+// cancel other actions, which removes them from the queue.
+func (sim *Simulation) cleanUpPendingActions() {
+	for pa := sim.pendingActions.pop(); pa != nil; pa = sim.pendingActions.pop() {
+		if pa.CleanUp != nil {
+			pa.CleanUp(sim)
+		}
+	}
+}
+
 // RunOnce is the main event loop. It will run the simulation for number of seconds.
 func (sim *Simulation) runOnce() {
 	sim.reset()
 
 	for {
-		last := len(sim.pendingActions) - 1
-		pa := sim.pendingActions[last]
-		sim.pendingActions = sim.pendingActions[:last]
-		if pa.cancelled {
-			continue
+		pa := sim.pendingActions.pop()
+		if pa == nil {
+			break
 		}
 
-		if pa.NextActionAt > sim.Duration {
+		if pa.NextActionAt > sim.Duration && !sim.extendDuration(pa.NextActionAt) {
 			break
 		}
 
@@ -206,33 +373,34 @@ func (sim *Simulation) runOnce() {
 		pa.OnAction(sim)
 	}
 
-	for _, pa := range sim.pendingActions {
-		if pa.CleanUp != nil {
-			pa.CleanUp(sim)
-		}
-	}
+	sim.cleanUpPendingActions()
 
 	sim.Raid.doneIteration(sim)
 	sim.Encounter.doneIteration(sim)
+	if sim.Encounter.UseHealth {
+		sim.Encounter.doneHealthIteration(sim)
+	}
 
 	for _, unit := range sim.Raid.AllUnits {
 		unit.Metrics.doneIteration(sim.Duration.Seconds())
+		unit.Metrics.timeline.doneIteration(sim.Duration)
 	}
 	for _, target := range sim.Encounter.Targets {
-		target.Metrics.doneIteration(sim.Duration.Seconds())
+		target.Metrics.timeline.doneIteration(sim.Duration)
+		activeSeconds := target.activeSeconds(sim)
+		target.Metrics.activeTimeSum += activeSeconds
+		if activeSeconds == 0 {
+			activeSeconds = sim.Duration.Seconds()
+		}
+		target.Metrics.doneIteration(activeSeconds)
 	}
 }
 
 func (sim *Simulation) AddPendingAction(pa *PendingAction) {
-	for index, v := range sim.pendingActions {
-		if v.NextActionAt < pa.NextActionAt || (v.NextActionAt == pa.NextActionAt && v.Priority >= pa.Priority) {
-			sim.pendingActions = append(sim.pendingActions, pa)
-			copy(sim.pendingActions[index+1:], sim.pendingActions[index:])
-			sim.pendingActions[index] = pa
-			return
-		}
+	if pa.cancelled {
+		return
 	}
-	sim.pendingActions = append(sim.pendingActions, pa)
+	sim.pendingActions.push(pa)
 }
 
 // Advance moves time forward counting down auras, CDs, mana regen, etc
@@ -240,10 +408,7 @@ func (sim *Simulation) advance(elapsedTime time.Duration) {
 	sim.CurrentTime += elapsedTime
 
 	if !sim.executePhase && sim.CurrentTime >= sim.Encounter.executePhaseBegins {
-		sim.executePhase = true
-		for _, callback := range sim.executePhaseCallbacks {
-			callback(sim)
-		}
+		sim.beginExecutePhase()
 	}
 
 	for _, party := range sim.Raid.Parties {
@@ -257,6 +422,13 @@ func (sim *Simulation) advance(elapsedTime time.Duration) {
 	}
 }
 
+func (sim *Simulation) beginExecutePhase() {
+	sim.executePhase = true
+	for _, callback := range sim.executePhaseCallbacks {
+		callback(sim)
+	}
+}
+
 func (sim *Simulation) RegisterExecutePhaseCallback(callback func(*Simulation)) {
 	sim.executePhaseCallbacks = append(sim.executePhaseCallbacks, callback)
 }
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// Combines the progress of a batch of sims run for a single request, such as
+// stat weights or one of the optimizers, into a single progress stream.
+type simBatch struct {
+	iterationsTotal int32
+	iterationsDone  int32
+	simsTotal       int32
+	simsCompleted   int32
+
+	progress chan *proto.ProgressMetrics
+}
+
+func newSimBatch(progress chan *proto.ProgressMetrics) *simBatch {
+	return &simBatch{
+		progress: progress,
+	}
+}
+
+// Adds sims to the batch, each of which is planned to run the given number of iterations.
+func (batch *simBatch) addSims(numSims int32, iterationsPerSim int32) {
+	atomic.AddInt32(&batch.simsTotal, numSims)
+	atomic.AddInt32(&batch.iterationsTotal, numSims*iterationsPerSim)
+}
+
+// Runs one sim of the batch, forwarding its progress, and returns its result.
+// The sim must have been added with addSims beforehand, with plannedIterations
+// iterations.
+func (batch *simBatch) runSim(ctx context.Context, request *proto.RaidSimRequest, plannedIterations int32) *proto.RaidSimResult {
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	go RunSimWithContext(ctx, *request, reporter)
+
+	var localIterations int32
+	for metrics := range reporter {
+		atomic.AddInt32(&batch.iterationsDone, metrics.CompletedIterations-localIterations)
+		localIterations = metrics.CompletedIterations
+		if metrics.FinalRaidResult != nil {
+			atomic.AddInt32(&batch.simsCompleted, 1)
+			// The sim may have stopped before or after the planned number of iterations,
+			// e.g. because of a stderr target.
+			atomic.AddInt32(&batch.iterationsTotal, metrics.CompletedIterations-plannedIterations)
+		}
+		batch.report()
+		if metrics.FinalRaidResult != nil {
+			return metrics.FinalRaidResult
+		}
+	}
+	return &proto.RaidSimResult{ErrorMsg: "Sim ended without a result"}
+}
+
+func (batch *simBatch) report() {
+	if batch.progress == nil {
+		return
+	}
+	batch.progress <- &proto.ProgressMetrics{
+		TotalIterations:     atomic.LoadInt32(&batch.iterationsTotal),
+		CompletedIterations: atomic.LoadInt32(&batch.iterationsDone),
+		CompletedSims:       atomic.LoadInt32(&batch.simsCompleted),
+		TotalSims:           atomic.LoadInt32(&batch.simsTotal),
+	}
+}
+
+// Calls fn for each index in [0, n), spread over at most one goroutine per CPU,
+// and waits for all of them to finish.
+func runInWorkerPool(n int, fn func(i int)) {
+	numWorkers := runtime.NumCPU()
+	if numWorkers > n {
+		numWorkers = n
+	}
+
+	indices := make(chan int)
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer waitGroup.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	waitGroup.Wait()
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Whether a request should be split across multiple worker Simulations.
+func useConcurrentSim(rsr proto.RaidSimRequest) bool {
+	options := rsr.SimOptions
+	if options == nil || options.Concurrency <= 1 {
+		return false
+	}
+
+	// Full debug logs are only meaningful for a single event stream.
+	if options.Debug {
+		return false
+	}
+
+	_, maxIterations := iterationCaps(options)
+	return maxIterations >= options.Concurrency
+}
+
+// Splits the iterations of a request across SimOptions.Concurrency worker
+// Simulations, each on its own goroutine, and merges their metrics.
+//
+// Worker seeds are derived from the request seed, and metrics are always merged
+// in worker order, so results are deterministic for a fixed seed and number of
+// workers.
+//
+// With a stderr target, each worker stops on its own once its share of the
+// target is reached. Since the stderr of the merged mean shrinks with the
+// square root of the number of workers, each worker's target is scaled up by
+// that amount.
+func runConcurrentSim(ctx context.Context, rsr proto.RaidSimRequest, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	numWorkers := rsr.SimOptions.Concurrency
+	minIterations, maxIterations := iterationCaps(rsr.SimOptions)
+	splitIterations := func(iterations int32, workerIdx int32) int32 {
+		workerIterations := iterations / numWorkers
+		if workerIdx < iterations%numWorkers {
+			workerIterations++
+		}
+		return workerIterations
+	}
+
+	baseSeed := rsr.SimOptions.RandomSeed
+	if baseSeed == 0 {
+		baseSeed = time.Now().UnixNano()
+	}
+	seedRand := NewSplitMix(uint64(baseSeed))
+
+	workers := make([]*Simulation, numWorkers)
+	for i := int32(0); i < numWorkers; i++ {
+		workerRequest := googleProto.Clone(&rsr).(*proto.RaidSimRequest)
+		workerRequest.SimOptions.Concurrency = 0
+		workerRequest.SimOptions.Iterations = splitIterations(maxIterations, i)
+		if rsr.SimOptions.TargetDpsStderr > 0 {
+			workerRequest.SimOptions.TargetDpsStderr = rsr.SimOptions.TargetDpsStderr * math.Sqrt(float64(numWorkers))
+			workerRequest.SimOptions.MinIterations = splitIterations(minIterations, i)
+			workerRequest.SimOptions.MaxIterations = workerRequest.SimOptions.Iterations
+		}
+
+		// Only the first worker produces logs and combat log events, which are for
+		// its first iteration.
+		// The first worker also keeps the original seed, so its iterations match
+		// the first iterations of a serial sim.
+		workerSeed := seedRand.Next()
+		if i == 0 {
+			workerRequest.SimOptions.RandomSeed = baseSeed
+		} else {
+			workerRequest.SimOptions.RandomSeed = int64(workerSeed)
+			workerRequest.SimOptions.DebugFirstIteration = false
+			workerRequest.SimOptions.CombatLog = false
+		}
+
+		worker := NewSim(*workerRequest)
+		if err := worker.validate(); err != nil {
+			return errorSimResult(err.Error(), progress)
+		}
+		if i != 0 {
+			worker.testRandSalt = workerSeed
+		}
+		worker.runPresims(*workerRequest)
+		workers[i] = worker
+	}
+
+	if progress != nil {
+		reporter := newConcurrentProgressReporter(numWorkers, maxIterations, progress)
+		for i, worker := range workers {
+			worker.ProgressReport = reporter.workerReport(i)
+		}
+	}
+
+	logs := ""
+	var firstIterationDuration time.Duration
+	workerIterations := make([]int32, numWorkers)
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(workers))
+	for i, worker := range workers {
+		go func(i int, worker *Simulation) {
+			defer waitGroup.Done()
+			workerLogs, workerFirstIterationDuration, numIterations := worker.runIterations(ctx)
+			workerIterations[i] = numIterations
+			if i == 0 {
+				logs = workerLogs
+				firstIterationDuration = workerFirstIterationDuration
+			}
+		}(i, worker)
+	}
+	waitGroup.Wait()
+
+	for _, worker := range workers[1:] {
+		workers[0].mergeMetrics(worker)
+	}
+	totalIterations := int32(0)
+	for _, numIterations := range workerIterations {
+		totalIterations += numIterations
+	}
+	if ctx.Err() != nil {
+		result := cancelledSimResult()
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{TotalIterations: totalIterations, CompletedIterations: totalIterations, FinalRaidResult: result}
+		}
+		return result
+	}
+	result := workers[0].getResult(totalIterations, logs, firstIterationDuration)
+
+	// Final progress report
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{TotalIterations: totalIterations, CompletedIterations: totalIterations, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result}
+	}
+
+	return result
+}
+
+// Combines the progress reports of all workers into a single stream.
+type concurrentProgressReporter struct {
+	mu sync.Mutex
+
+	totalIterations     int32
+	completedIterations []int32
+	dps                 []float64
+
+	progress chan *proto.ProgressMetrics
+}
+
+func newConcurrentProgressReporter(numWorkers int32, totalIterations int32, progress chan *proto.ProgressMetrics) *concurrentProgressReporter {
+	return &concurrentProgressReporter{
+		totalIterations:     totalIterations,
+		completedIterations: make([]int32, numWorkers),
+		dps:                 make([]float64, numWorkers),
+		progress:            progress,
+	}
+}
+
+func (reporter *concurrentProgressReporter) workerReport(workerIdx int) func(*proto.ProgressMetrics) {
+	return func(workerProgress *proto.ProgressMetrics) {
+		reporter.mu.Lock()
+		defer reporter.mu.Unlock()
+
+		reporter.completedIterations[workerIdx] = workerProgress.CompletedIterations
+		reporter.dps[workerIdx] = workerProgress.Dps
+
+		completedIterations := int32(0)
+		dpsSum := 0.0
+		for i, workerIterations := range reporter.completedIterations {
+			completedIterations += workerIterations
+			dpsSum += reporter.dps[i] * float64(workerIterations)
+		}
+
+		combinedProgress := &proto.ProgressMetrics{
+			TotalIterations:     reporter.totalIterations,
+			CompletedIterations: completedIterations,
+		}
+		if completedIterations > 0 {
+			combinedProgress.Dps = dpsSum / float64(completedIterations)
+		}
+		reporter.progress <- combinedProgress
+	}
+}
+
+// Adds the aggregate metrics from another Simulation, which must have been
+// created from the same raid and encounter, into this one.
+func (sim *Simulation) mergeMetrics(other *Simulation) {
+	sim.Raid.mergeMetrics(other.Raid)
+	sim.Encounter.mergeMetrics(&other.Encounter)
+}
+
+func (raid *Raid) mergeMetrics(other *Raid) {
+	raid.dpsMetrics.merge(&other.dpsMetrics)
+	for partyIdx, party := range raid.Parties {
+		otherParty := other.Parties[partyIdx]
+		party.dpsMetrics.merge(&otherParty.dpsMetrics)
+
+		for playerIdx, player := range party.Players {
+			character := player.GetCharacter()
+			otherCharacter := otherParty.Players[playerIdx].GetCharacter()
+			character.Unit.mergeMetrics(&otherCharacter.Unit)
+
+			for petIdx, petAgent := range character.Pets {
+				petAgent.GetPet().Unit.mergeMetrics(&otherCharacter.Pets[petIdx].GetPet().Unit)
+			}
+		}
+	}
+}
+
+func (encounter *Encounter) mergeMetrics(other *Encounter) {
+	encounter.killTimeMetrics.merge(&other.killTimeMetrics)
+	encounter.numKillTimeSamples += other.numKillTimeSamples
+	for i, target := range encounter.Targets {
+		target.Unit.mergeMetrics(&other.Targets[i].Unit)
+	}
+}
+
+func (unit *Unit) mergeMetrics(other *Unit) {
+	unit.Metrics.merge(&other.Metrics)
+	unit.auraTracker.mergeMetrics(&other.auraTracker)
+}
+
+func (at *auraTracker) mergeMetrics(other *auraTracker) {
+	for _, otherAura := range other.auras {
+		aura := at.GetAura(otherAura.Label)
+		if aura == nil {
+			// Some auras are registered lazily, so they might only exist in some
+			// workers. This only happens after all iterations are done, so it's
+			// safe to keep the other worker's aura just for its metrics.
+			at.auras = append(at.auras, otherAura)
+			continue
+		}
+		aura.metrics.merge(&otherAura.metrics)
+	}
+}
+
+func (auraMetrics *AuraMetrics) merge(other *AuraMetrics) {
+	auraMetrics.uptimeSum += other.uptimeSum
+	auraMetrics.uptimeSumSquared += other.uptimeSumSquared
+	auraMetrics.chargesConsumedSum += other.chargesConsumedSum
+	auraMetrics.activeTimeSums = mergeTimeline(auraMetrics.activeTimeSums, other.activeTimeSums)
+}
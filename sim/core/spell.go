@@ -38,6 +38,8 @@ type SpellMetrics struct {
 	PartialResists_3_4 int32   // 3/4 of the spell was resisted
 	TotalDamage        float64 // Damage done by all casts of this spell.
 	TotalThreat        float64 // Threat generated by all casts of this spell.
+	TotalHealing       float64 // Healing done by all casts of this spell, not including overhealing.
+	TotalOverhealing   float64 // Healing done by all casts of this spell, beyond the target's max health.
 }
 
 type Spell struct {
@@ -145,7 +147,11 @@ func (spell *Spell) CurDamagePerCast() float64 {
 }
 
 func (spell *Spell) reset(sim *Simulation) {
-	spell.SpellMetrics = make([]SpellMetrics, len(spell.Unit.AttackTables))
+	if spell.SpellExtras.Matches(SpellExtrasHealing) {
+		spell.SpellMetrics = make([]SpellMetrics, spell.Unit.Env.Raid.numUnitSlots())
+	} else {
+		spell.SpellMetrics = make([]SpellMetrics, len(spell.Unit.AttackTables))
+	}
 }
 
 func (spell *Spell) doneIteration() {
@@ -177,6 +183,10 @@ func (spell *Spell) SkipCastAndApplyEffects(sim *Simulation, target *Unit) {
 			spell.ActionID, spell.DefaultCast.Cost, time.Duration(0))
 		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 	}
+	if sim.logCombatEvents && !spell.SpellExtras.Matches(SpellExtrasNoLogs) {
+		spell.logCastStart(sim, spell.DefaultCast.Cost, 0)
+		spell.logCastComplete(sim)
+	}
 	spell.applyEffects(sim, target)
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/wowsims/tbc/sim/core/proto"
 	"github.com/wowsims/tbc/sim/core/stats"
 )
 
@@ -26,6 +27,7 @@ type SpellEffect struct {
 	BonusSpellHitRating  float64
 	BonusSpellPower      float64
 	BonusSpellCritRating float64
+	BonusHealingPower    float64
 
 	BonusAttackPower float64
 	BonusCritRating  float64
@@ -190,8 +192,31 @@ func (spellEffect *SpellEffect) calcDamageTargetOnly(sim *Simulation, spell *Spe
 }
 
 func (spellEffect *SpellEffect) finalize(sim *Simulation, spell *Spell) {
+	if spellEffect.Damage > 0 && len(spellEffect.Target.absorbShields) > 0 {
+		spellEffect.Damage -= spellEffect.Target.absorbDamage(sim, spell, spellEffect.Damage)
+	}
+
 	spell.SpellMetrics[spellEffect.Target.Index].TotalDamage += spellEffect.Damage
 	spell.SpellMetrics[spellEffect.Target.Index].TotalThreat += spellEffect.calcThreat(spell)
+	if spellEffect.Damage > 0 {
+		spell.Unit.Metrics.timeline.addDamage(sim.CurrentTime, spellEffect.Damage)
+	}
+
+	if spellEffect.Damage > 0 {
+		spellEffect.Target.RemoveHealth(sim, spellEffect.Damage)
+	}
+
+	if sim.logCombatEvents {
+		sim.addCombatLogEvent(&proto.CombatLogEvent{
+			Type:     proto.CombatLogEvent_Damage,
+			Unit:     spell.Unit.unitReference(),
+			Target:   spellEffect.Target.unitReference(),
+			ActionId: spell.ActionID.ToProto(),
+			Amount:   spellEffect.Damage,
+			Outcome:  spellEffect.Outcome.ToProto(),
+			Periodic: spellEffect.IsPeriodic,
+		})
+	}
 
 	if sim.Log != nil {
 		if spellEffect.IsPeriodic {
@@ -279,4 +304,8 @@ func (spellEffect *SpellEffect) applyTargetModifiers(sim *Simulation, spell *Spe
 	} else if spell.SpellSchool.Matches(SpellSchoolShadow) {
 		spellEffect.Damage *= target.PseudoStats.ShadowDamageTakenMultiplier
 	}
+
+	if target.PseudoStats.Immune {
+		spellEffect.Damage = 0
+	}
 }
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (spellEffect *SpellEffect) HealingPower(unit *Unit) float64 {
+	return unit.GetStat(stats.HealingPower) + spellEffect.BonusHealingPower
+}
+
+// Creates a BaseDamageCalculator function for a heal, which scales with
+// healing power instead of spell power.
+func BaseHealingFuncHealing(minFlatHealing float64, maxFlatHealing float64, spellCoefficient float64) BaseDamageCalculator {
+	if spellCoefficient == 0 {
+		return BaseDamageFuncRoll(minFlatHealing, maxFlatHealing)
+	}
+
+	if minFlatHealing == maxFlatHealing {
+		return func(sim *Simulation, hitEffect *SpellEffect, spell *Spell) float64 {
+			return hitEffect.HealingPower(spell.Unit)*spellCoefficient + minFlatHealing
+		}
+	} else {
+		deltaHealing := maxFlatHealing - minFlatHealing
+		return func(sim *Simulation, hitEffect *SpellEffect, spell *Spell) float64 {
+			healing := hitEffect.HealingPower(spell.Unit) * spellCoefficient
+			healing += damageRollOptimized(sim, minFlatHealing, deltaHealing)
+			return healing
+		}
+	}
+}
+func BaseHealingConfigHealing(minFlatHealing float64, maxFlatHealing float64, spellCoefficient float64) BaseDamageConfig {
+	return BuildBaseDamageConfig(BaseHealingFuncHealing(minFlatHealing, maxFlatHealing, spellCoefficient), spellCoefficient)
+}
+
+// Heals use the same SpellEffect as damage, with Damage holding the amount healed.
+// Heals always land, so OutcomeApplier should be one of the crit or tick funcs.
+func ApplyEffectFuncDirectHealing(baseEffect SpellEffect) ApplySpellEffects {
+	baseEffect.Validate()
+	return func(sim *Simulation, target *Unit, spell *Spell) {
+		effect := &baseEffect
+		effect.Target = target
+		effect.init(sim, spell)
+
+		effect.Damage = effect.calculateBaseDamage(sim, spell) * effect.DamageMultiplier
+		effect.calcHealingSingle(sim, spell)
+		effect.finalizeHealing(sim, spell)
+	}
+}
+
+// Like ApplyEffectFuncDirectHealing, but each effect heals its own Target.
+func ApplyEffectFuncHealingMultiple(baseEffects []SpellEffect) ApplySpellEffects {
+	for _, effect := range baseEffects {
+		effect.Validate()
+	}
+
+	return func(sim *Simulation, _ *Unit, spell *Spell) {
+		for i := range baseEffects {
+			effect := &baseEffects[i]
+			effect.init(sim, spell)
+			effect.Damage = effect.calculateBaseDamage(sim, spell) * effect.DamageMultiplier
+			effect.calcHealingSingle(sim, spell)
+		}
+		for i := range baseEffects {
+			effect := &baseEffects[i]
+			effect.finalizeHealing(sim, spell)
+		}
+	}
+}
+
+// Healing equivalent of TickFuncSnapshot, for HoTs.
+func TickFuncHealingSnapshot(target *Unit, baseEffect SpellEffect) TickEffects {
+	snapshotEffect := &SpellEffect{}
+	return func(sim *Simulation, spell *Spell) func() {
+		*snapshotEffect = baseEffect
+		snapshotEffect.Target = target
+		baseHealing := snapshotEffect.calculateBaseDamage(sim, spell) * snapshotEffect.DamageMultiplier
+		snapshotEffect.DamageMultiplier = 1
+		snapshotEffect.BaseDamage = BaseDamageConfigFlat(baseHealing)
+
+		effectsFunc := ApplyEffectFuncDirectHealing(*snapshotEffect)
+		return func() {
+			effectsFunc(sim, target, spell)
+		}
+	}
+}
+
+func (spellEffect *SpellEffect) calcHealingSingle(sim *Simulation, spell *Spell) {
+	spellEffect.Damage *= spell.Unit.PseudoStats.HealingDealtMultiplier
+	spellEffect.Damage += spellEffect.Target.PseudoStats.BonusHealingTaken * spellEffect.BaseDamage.TargetSpellCoefficient
+	spellEffect.Damage *= spellEffect.Target.PseudoStats.HealingTakenMultiplier
+	spellEffect.PreoutcomeDamage = spellEffect.Damage
+	spellEffect.OutcomeApplier(sim, spell, spellEffect, nil)
+}
+
+func (spellEffect *SpellEffect) finalizeHealing(sim *Simulation, spell *Spell) {
+	healing := spellEffect.Damage
+	effectiveHealing := spellEffect.Target.GainHealth(sim, healing)
+	overhealing := healing - effectiveHealing
+
+	// Healing threat is 0.5 per point of effective healing.
+	threat := effectiveHealing * 0.5 * spellEffect.ThreatMultiplier * spell.TotalThreatMultiplier()
+
+	spellMetrics := &spell.SpellMetrics[spellEffect.Target.Index]
+	spellMetrics.TotalHealing += effectiveHealing
+	spellMetrics.TotalOverhealing += overhealing
+	spellMetrics.TotalThreat += threat
+
+	if sim.logCombatEvents {
+		sim.addCombatLogEvent(&proto.CombatLogEvent{
+			Type:        proto.CombatLogEvent_Healing,
+			Unit:        spell.Unit.unitReference(),
+			Target:      spellEffect.Target.unitReference(),
+			ActionId:    spell.ActionID.ToProto(),
+			Amount:      effectiveHealing,
+			Outcome:     spellEffect.Outcome.ToProto(),
+			Periodic:    spellEffect.IsPeriodic,
+			Overhealing: overhealing,
+		})
+	}
+
+	if sim.Log != nil {
+		if spellEffect.IsPeriodic {
+			spell.Unit.Log(sim, "%s tick on %s %s. (Overheal: %0.3f, Threat: %0.3f)", spell.ActionID, spellEffect.Target.Label, spellEffect.healingString(), overhealing, threat)
+		} else {
+			spell.Unit.Log(sim, "%s on %s %s. (Overheal: %0.3f, Threat: %0.3f)", spell.ActionID, spellEffect.Target.Label, spellEffect.healingString(), overhealing, threat)
+		}
+	}
+
+	if !spellEffect.IsPeriodic && spellEffect.OnSpellHitDealt != nil {
+		spellEffect.OnSpellHitDealt(sim, spell, spellEffect)
+	}
+	spell.Unit.OnHealDealt(sim, spell, spellEffect)
+	spellEffect.Target.OnHealTaken(sim, spell, spellEffect)
+}
+
+func (spellEffect *SpellEffect) healingString() string {
+	return fmt.Sprintf("%s for %0.3f healing", spellEffect.Outcome.String(), spellEffect.Damage)
+}
@@ -296,6 +296,22 @@ func (unit *Unit) OutcomeFuncEnemyMeleeWhite() OutcomeApplier {
 	}
 }
 
+// Enemy special attacks can be avoided like white hits, but can't crit or crush.
+func (unit *Unit) OutcomeFuncEnemyMeleeSpecial() OutcomeApplier {
+	return func(sim *Simulation, spell *Spell, spellEffect *SpellEffect, attackTable *AttackTable) {
+		unit := spell.Unit
+		roll := sim.RandomFloat("Enemy Special Hit Table")
+		chance := 0.0
+
+		if !spellEffect.applyEnemyAttackTableMiss(spell, unit, attackTable, roll, &chance) &&
+			!spellEffect.applyEnemyAttackTableDodge(spell, unit, attackTable, roll, &chance) &&
+			!spellEffect.applyEnemyAttackTableParry(spell, unit, attackTable, roll, &chance) &&
+			!spellEffect.applyEnemyAttackTableBlock(spell, unit, attackTable, roll, &chance) {
+			spellEffect.applyAttackTableHit(spell)
+		}
+	}
+}
+
 // Calculates a hit check using the stats from this spell.
 func (spellEffect *SpellEffect) magicHitCheck(sim *Simulation, spell *Spell, attackTable *AttackTable) bool {
 	missChance := attackTable.BaseSpellMissChance - (spell.Unit.GetStat(stats.SpellHit)+spellEffect.BonusSpellHitRating)/(SpellHitRatingPerHitChance*100)
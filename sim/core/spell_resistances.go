@@ -98,7 +98,9 @@ func (at *AttackTable) GetBinaryHitChance(ss SpellSchool) float64 {
 // https://royalgiraffe.github.io/resist-guide
 
 func (unit *Unit) resistCoeff(school SpellSchool, attacker *Unit, binary bool) float64 {
-	resistanceCap := float64(unit.Level * 5)
+	// The cap is based on the higher of the two levels, so that level 73 bosses
+	// casting at level 70 players use the same cap as the other way around.
+	resistanceCap := float64(MaxInt32(unit.Level, attacker.Level) * 5)
 
 	resistance := MaxFloat(0, unit.GetStat(school.ResistanceStat())-attacker.stats[stats.SpellPenetration])
 	if school == SpellSchoolHoly {
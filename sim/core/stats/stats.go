@@ -458,6 +458,8 @@ type PseudoStats struct {
 	BonusCritRatingAgentReserved1       float64
 	AgentReserved1DamageDealtMultiplier float64
 
+	HealingDealtMultiplier float64 // All healing
+
 	///////////////////////////////////////////////////
 	// Effects that apply when this unit is the target.
 	///////////////////////////////////////////////////
@@ -491,6 +493,11 @@ type PseudoStats struct {
 	ShadowDamageTakenMultiplier   float64
 
 	PeriodicPhysicalDamageTakenMultiplier float64
+
+	Immune bool // Takes no damage, e.g. bosses during immune phases or despawned adds.
+
+	BonusHealingTaken      float64 // Blessing of Light
+	HealingTakenMultiplier float64 // All healing
 }
 
 func NewPseudoStats() PseudoStats {
@@ -518,6 +525,8 @@ func NewPseudoStats() PseudoStats {
 
 		AgentReserved1DamageDealtMultiplier: 1,
 
+		HealingDealtMultiplier: 1,
+
 		// Target effects.
 		DamageTakenMultiplier: 1,
 
@@ -530,5 +539,7 @@ func NewPseudoStats() PseudoStats {
 		ShadowDamageTakenMultiplier:   1,
 
 		PeriodicPhysicalDamageTakenMultiplier: 1,
+
+		HealingTakenMultiplier: 1,
 	}
 }
@@ -1,10 +1,10 @@
 package core
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"sync"
-	"sync/atomic"
 
 	"github.com/wowsims/tbc/sim/core/proto"
 	"github.com/wowsims/tbc/sim/core/stats"
@@ -33,17 +33,23 @@ type StatWeightsResult struct {
 	Dps  StatWeightValues
 	Tps  StatWeightValues
 	Dtps StatWeightValues
+	Hps  StatWeightValues
+
+	ErrorMsg string
 }
 
 func (swr StatWeightsResult) ToProto() *proto.StatWeightsResult {
 	return &proto.StatWeightsResult{
-		Dps:  swr.Dps.ToProto(),
-		Tps:  swr.Tps.ToProto(),
-		Dtps: swr.Dtps.ToProto(),
+		Dps:      swr.Dps.ToProto(),
+		Tps:      swr.Tps.ToProto(),
+		Dtps:     swr.Dtps.ToProto(),
+		Hps:      swr.Hps.ToProto(),
+		ErrorMsg: swr.ErrorMsg,
 	}
 }
 
-func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, referenceStat stats.Stat, progress chan *proto.ProgressMetrics) StatWeightsResult {
+// Stops early with an error message if ctx is cancelled.
+func CalcStatWeight(ctx context.Context, swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, referenceStat stats.Stat, progress chan *proto.ProgressMetrics) StatWeightsResult {
 	if swr.Player.BonusStats == nil {
 		swr.Player.BonusStats = make([]float64, stats.Len)
 	}
@@ -53,9 +59,31 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 
 	simOptions := swr.SimOptions
 
+	const defaultStatMod = 50.0
+	getStatMod := func(stat stats.Stat) float64 {
+		if stat == stats.SpellHit || stat == stats.MeleeHit || stat == stats.Expertise {
+			return 15
+		}
+		return defaultStatMod
+	}
+
+	if swr.TargetWeightStderr > 0 {
+		// Give the baseline and the stat sims an equal share of the weight stderr,
+		// for the stat with the smallest mod.
+		minStatMod := getStatMod(referenceStat)
+		for _, stat := range statsToWeigh {
+			minStatMod = math.Min(minStatMod, getStatMod(stat))
+		}
+		simOptions = googleProto.Clone(simOptions).(*proto.SimOptions)
+		simOptions.TargetDpsStderr = swr.TargetWeightStderr * minStatMod / math.Sqrt2
+	}
+
 	baseStatsResult := ComputeStats(&proto.ComputeStatsRequest{
 		Raid: raidProto,
 	})
+	if baseStatsResult.ErrorMsg != "" {
+		return StatWeightsResult{ErrorMsg: baseStatsResult.ErrorMsg}
+	}
 	baseStats := baseStatsResult.RaidStats.Parties[0].Players[0].FinalStats
 
 	baseSimRequest := &proto.RaidSimRequest{
@@ -63,10 +91,14 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 		Encounter:  swr.Encounter,
 		SimOptions: simOptions,
 	}
-	baselineResult := RunRaidSim(baseSimRequest)
+	baselineResult := RunSimWithContext(ctx, *baseSimRequest, nil)
+	if baselineResult.ErrorMsg != "" {
+		return StatWeightsResult{ErrorMsg: baselineResult.ErrorMsg}
+	}
 	baselineDpsMetrics := baselineResult.RaidMetrics.Parties[0].Players[0].Dps
 	baselineTpsMetrics := baselineResult.RaidMetrics.Parties[0].Players[0].Threat
 	baselineDtpsMetrics := baselineResult.RaidMetrics.Parties[0].Players[0].Dtps
+	baselineHpsMetrics := baselineResult.RaidMetrics.Parties[0].Players[0].Hps
 
 	var waitGroup sync.WaitGroup
 
@@ -80,10 +112,7 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 	dtpsHistsLow := [stats.Len]map[int32]int32{}
 	dtpsHistsHigh := [stats.Len]map[int32]int32{}
 
-	var iterationsTotal int32
-	var iterationsDone int32
-	var simsTotal int32
-	var simsCompleted int32
+	batch := newSimBatch(progress)
 
 	doStat := func(stat stats.Stat, value float64, isLow bool) {
 		defer waitGroup.Done()
@@ -91,52 +120,35 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 		simRequest := googleProto.Clone(baseSimRequest).(*proto.RaidSimRequest)
 		simRequest.Raid.Parties[0].Players[0].BonusStats[stat] += value
 		simRequest.SimOptions.Iterations /= 2 // Cut in half since we're doing above and below separately.
+		simRequest.SimOptions.MinIterations /= 2
+		simRequest.SimOptions.MaxIterations /= 2
+		plannedIterations := simRequest.SimOptions.Iterations
+		if swr.TargetWeightStderr > 0 {
+			setStatSimStderrTarget(simRequest.SimOptions, swr.TargetWeightStderr, value, baselineDpsMetrics.Stderr)
+		}
 
-		reporter := make(chan *proto.ProgressMetrics, 10)
-		go RunSim(*simRequest, reporter) // RunRaidSim(simRequest)
-
-		var localIterations int32
-		var simResult *proto.RaidSimResult
-	statsim:
-		for {
-			select {
-			case metrics, ok := <-reporter:
-				if !ok {
-					break statsim
-				}
-				atomic.AddInt32(&iterationsDone, (metrics.CompletedIterations - localIterations))
-				localIterations = metrics.CompletedIterations
-				if metrics.FinalRaidResult != nil {
-					atomic.AddInt32(&simsCompleted, 1)
-					simResult = metrics.FinalRaidResult
-				}
-				if progress != nil {
-					progress <- &proto.ProgressMetrics{
-						TotalIterations:     atomic.LoadInt32(&iterationsTotal),
-						CompletedIterations: atomic.LoadInt32(&iterationsDone),
-						CompletedSims:       atomic.LoadInt32(&simsCompleted),
-						TotalSims:           atomic.LoadInt32(&simsTotal),
-					}
-				}
-				if metrics.FinalRaidResult != nil {
-					break statsim
-				}
-			}
+		simResult := batch.runSim(ctx, simRequest, plannedIterations)
+		if simResult.ErrorMsg != "" {
+			return
 		}
 		dpsMetrics := simResult.RaidMetrics.Parties[0].Players[0].Dps
 		tpsMetrics := simResult.RaidMetrics.Parties[0].Players[0].Dps
 		dtpsMetrics := simResult.RaidMetrics.Parties[0].Players[0].Dtps
+		hpsMetrics := simResult.RaidMetrics.Parties[0].Players[0].Hps
 		dpsDiff := (dpsMetrics.Avg - baselineDpsMetrics.Avg) / value
 		tpsDiff := (tpsMetrics.Avg - baselineTpsMetrics.Avg) / value
 		dtpsDiff := (dtpsMetrics.Avg - baselineDtpsMetrics.Avg) / value
+		hpsDiff := (hpsMetrics.Avg - baselineHpsMetrics.Avg) / value
 
 		if isLow {
 			resultLow.Dps.Weights[stat] = dpsDiff
 			resultLow.Tps.Weights[stat] = tpsDiff
 			resultLow.Dtps.Weights[stat] = dtpsDiff
+			resultLow.Hps.Weights[stat] = hpsDiff
 			resultLow.Dps.WeightsStdev[stat] = dpsMetrics.Stdev / math.Abs(value)
 			resultLow.Tps.WeightsStdev[stat] = tpsMetrics.Stdev / math.Abs(value)
 			resultLow.Dtps.WeightsStdev[stat] = dtpsMetrics.Stdev / math.Abs(value)
+			resultLow.Hps.WeightsStdev[stat] = hpsMetrics.Stdev / math.Abs(value)
 			dpsHistsLow[stat] = dpsMetrics.Hist
 			tpsHistsLow[stat] = tpsMetrics.Hist
 			dtpsHistsLow[stat] = dtpsMetrics.Hist
@@ -144,16 +156,17 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 			resultHigh.Dps.Weights[stat] = dpsDiff
 			resultHigh.Tps.Weights[stat] = tpsDiff
 			resultHigh.Dtps.Weights[stat] = dtpsDiff
+			resultHigh.Hps.Weights[stat] = hpsDiff
 			resultHigh.Dps.WeightsStdev[stat] = dpsMetrics.Stdev / math.Abs(value)
 			resultHigh.Tps.WeightsStdev[stat] = tpsMetrics.Stdev / math.Abs(value)
 			resultHigh.Dtps.WeightsStdev[stat] = dtpsMetrics.Stdev / math.Abs(value)
+			resultHigh.Hps.WeightsStdev[stat] = hpsMetrics.Stdev / math.Abs(value)
 			dpsHistsHigh[stat] = dpsMetrics.Hist
 			tpsHistsHigh[stat] = tpsMetrics.Hist
 			dtpsHistsHigh[stat] = dtpsMetrics.Hist
 		}
 	}
 
-	const defaultStatMod = 50.0
 	statModsLow := stats.Stats{}
 	statModsHigh := stats.Stats{}
 
@@ -162,10 +175,7 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 	statModsHigh[referenceStat] = defaultStatMod
 
 	for _, stat := range statsToWeigh {
-		statMod := defaultStatMod
-		if stat == stats.SpellHit || stat == stats.MeleeHit || stat == stats.Expertise {
-			statMod = 15
-		}
+		statMod := getStatMod(stat)
 		statModsHigh[stat] = statMod
 		statModsLow[stat] = -statMod
 	}
@@ -175,14 +185,16 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 			continue
 		}
 		waitGroup.Add(2)
-		atomic.AddInt32(&iterationsTotal, swr.SimOptions.Iterations)
-		atomic.AddInt32(&simsTotal, 2)
+		batch.addSims(2, swr.SimOptions.Iterations/2)
 
 		go doStat(stats.Stat(stat), statModsLow[stat], true)
 		go doStat(stats.Stat(stat), statModsHigh[stat], false)
 	}
 
 	waitGroup.Wait()
+	if ctx.Err() != nil {
+		return StatWeightsResult{ErrorMsg: cancelledSimResult().ErrorMsg}
+	}
 
 	melee2HHitCap := 9 * MeleeHitRatingPerHitChance
 	if swr.Debuffs != nil && swr.Debuffs.FaerieFire == proto.TristateEffect_TristateEffectImproved {
@@ -205,6 +217,7 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 				resultHigh.Dps.Weights[stat] = resultLow.Dps.Weights[stat]
 				resultHigh.Tps.Weights[stat] = resultLow.Tps.Weights[stat]
 				resultHigh.Dtps.Weights[stat] = resultLow.Dtps.Weights[stat]
+				resultHigh.Hps.Weights[stat] = resultLow.Hps.Weights[stat]
 			}
 		} else if stat == stats.MeleeHit {
 			if baseStats[stat] > 30 {
@@ -240,10 +253,12 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 		result.Dps.Weights[stat] = (resultLow.Dps.Weights[stat] + resultHigh.Dps.Weights[stat]) / 2
 		result.Tps.Weights[stat] = (resultLow.Tps.Weights[stat] + resultHigh.Tps.Weights[stat]) / 2
 		result.Dtps.Weights[stat] = (resultLow.Dtps.Weights[stat] + resultHigh.Dtps.Weights[stat]) / 2
+		result.Hps.Weights[stat] = (resultLow.Hps.Weights[stat] + resultHigh.Hps.Weights[stat]) / 2
 
 		result.Dps.WeightsStdev[stat] = (resultLow.Dps.WeightsStdev[stat] + resultHigh.Dps.WeightsStdev[stat]) / 2
 		result.Tps.WeightsStdev[stat] = (resultLow.Tps.WeightsStdev[stat] + resultHigh.Tps.WeightsStdev[stat]) / 2
 		result.Dtps.WeightsStdev[stat] = (resultLow.Dtps.WeightsStdev[stat] + resultHigh.Dtps.WeightsStdev[stat]) / 2
+		result.Hps.WeightsStdev[stat] = (resultLow.Hps.WeightsStdev[stat] + resultHigh.Hps.WeightsStdev[stat]) / 2
 	}
 
 	for statIdx, _ := range statModsLow {
@@ -256,6 +271,10 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 		result.Tps.EpValues[stat] = result.Tps.Weights[stat] / result.Tps.Weights[referenceStat]
 		result.Dps.EpValuesStdev[stat] = result.Dps.WeightsStdev[stat] / math.Abs(result.Dps.Weights[referenceStat])
 		result.Tps.EpValuesStdev[stat] = result.Tps.WeightsStdev[stat] / math.Abs(result.Tps.Weights[referenceStat])
+		if result.Hps.Weights[referenceStat] != 0 {
+			result.Hps.EpValues[stat] = result.Hps.Weights[stat] / result.Hps.Weights[referenceStat]
+			result.Hps.EpValuesStdev[stat] = result.Hps.WeightsStdev[stat] / math.Abs(result.Hps.Weights[referenceStat])
+		}
 		if result.Dtps.Weights[DTPSReferenceStat] != 0 {
 			result.Dtps.EpValues[stat] = result.Dtps.Weights[stat] / result.Dtps.Weights[DTPSReferenceStat]
 			result.Dtps.EpValuesStdev[stat] = result.Dtps.WeightsStdev[stat] / math.Abs(result.Dps.Weights[DTPSReferenceStat])
@@ -291,6 +310,21 @@ func CalcStatWeight(swr proto.StatWeightsRequest, statsToWeigh []stats.Stat, ref
 	return result
 }
 
+// Sets the DPS stderr target of a stat sim so that the stderr of the resulting
+// weight is targetWeightStderr, given the stderr of the baseline DPS. If the
+// baseline alone is already too noisy, the stat sim runs its maximum number of
+// iterations instead.
+func setStatSimStderrTarget(options *proto.SimOptions, targetWeightStderr float64, statMod float64, baselineStderr float64) {
+	weightStderr := targetWeightStderr * math.Abs(statMod)
+	remainingVariance := weightStderr*weightStderr - baselineStderr*baselineStderr
+	if remainingVariance <= 0 {
+		_, options.Iterations = iterationCaps(options)
+		options.TargetDpsStderr = 0
+		return
+	}
+	options.TargetDpsStderr = math.Sqrt(remainingVariance)
+}
+
 func computeStDevFromHists(iters int32, modValue float64, moddedStatDpsHist map[int32]int32, baselineDpsHist map[int32]int32, referenceDpsHist map[int32]int32, referenceModValue float64) float64 {
 	if referenceDpsHist != nil && len(referenceDpsHist) == 1 {
 		return 0
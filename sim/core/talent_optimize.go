@@ -0,0 +1,198 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const defaultTalentOptimizeMaxBuilds = 200
+
+type TalentOptimizeBuild struct {
+	Points []int32
+	Dps    *proto.DistributionMetrics
+	Tps    *proto.DistributionMetrics
+}
+
+func (build TalentOptimizeBuild) ToProto() *proto.TalentOptimizeBuild {
+	return &proto.TalentOptimizeBuild{
+		Points: build.Points,
+		Dps:    build.Dps,
+		Tps:    build.Tps,
+	}
+}
+
+type TalentOptimizeResult struct {
+	Builds   []TalentOptimizeBuild
+	ErrorMsg string
+}
+
+func (result TalentOptimizeResult) ToProto() *proto.TalentOptimizeResult {
+	resultProto := &proto.TalentOptimizeResult{
+		ErrorMsg: result.ErrorMsg,
+	}
+	for _, build := range result.Builds {
+		resultProto.Builds = append(resultProto.Builds, build.ToProto())
+	}
+	return resultProto
+}
+
+// Returns every combination of points for the given talents, each from 0 up
+// to the talent's max points, or nil if there are more than maxCombinations.
+func talentCombinations(talents []*talentConfig, maxCombinations int) [][]int32 {
+	numCombinations := 1
+	for _, talent := range talents {
+		numCombinations *= int(talent.maxPoints) + 1
+		if numCombinations > maxCombinations {
+			return nil
+		}
+	}
+
+	combinations := make([][]int32, 0, numCombinations)
+	points := make([]int32, len(talents))
+	for {
+		combinations = append(combinations, append([]int32(nil), points...))
+
+		// Advance to the next combination, like an odometer.
+		i := 0
+		for ; i < len(talents); i++ {
+			if points[i] < talents[i].maxPoints {
+				points[i]++
+				break
+			}
+			points[i] = 0
+		}
+		if i == len(talents) {
+			return combinations
+		}
+	}
+}
+
+// CalcTalentOptimize enumerates the legal talent builds which vary a set of a
+// player's talents, and ranks them by DPS (or TPS) from full sims. The sims stop
+// early if ctx is cancelled, and the result then only has an error message.
+func CalcTalentOptimize(ctx context.Context, request proto.TalentOptimizeRequest, progress chan *proto.ProgressMetrics) TalentOptimizeResult {
+	if request.Player == nil {
+		return TalentOptimizeResult{ErrorMsg: "Missing player"}
+	}
+	if request.SimOptions == nil {
+		return TalentOptimizeResult{ErrorMsg: "Missing sim options"}
+	}
+	if request.Encounter == nil {
+		return TalentOptimizeResult{ErrorMsg: "Missing encounter"}
+	}
+	if len(request.Talents) == 0 {
+		return TalentOptimizeResult{ErrorMsg: "No talents to optimize"}
+	}
+
+	classTrees, ok := talentTreesByClass[request.Player.Class]
+	if !ok {
+		return TalentOptimizeResult{ErrorMsg: fmt.Sprintf("No talent trees for class %s", request.Player.Class)}
+	}
+	basePlayer := googleProto.Clone(request.Player).(*proto.Player)
+	baseTalents := playerTalentsMessage(basePlayer, true)
+	if baseTalents == nil {
+		return TalentOptimizeResult{ErrorMsg: "Player has no talents"}
+	}
+	if err := ValidateTalents(basePlayer); err != nil {
+		return TalentOptimizeResult{ErrorMsg: err.Error()}
+	}
+	basePoints := classTrees.modeledPoints(baseTalents)
+
+	var varyingTalents []*talentConfig
+	for _, fieldName := range request.Talents {
+		var talent *talentConfig
+		for treeIdx := range classTrees.trees {
+			for i := range classTrees.trees[treeIdx].talents {
+				if classTrees.trees[treeIdx].talents[i].fieldName == fieldName {
+					talent = &classTrees.trees[treeIdx].talents[i]
+				}
+			}
+		}
+		if talent == nil {
+			return TalentOptimizeResult{ErrorMsg: fmt.Sprintf("No %s talent: %s", request.Player.Class, fieldName)}
+		}
+		for _, other := range varyingTalents {
+			if other == talent {
+				return TalentOptimizeResult{ErrorMsg: fmt.Sprintf("Duplicate talent: %s", fieldName)}
+			}
+		}
+		varyingTalents = append(varyingTalents, talent)
+	}
+
+	maxBuilds := int(request.MaxBuilds)
+	if maxBuilds <= 0 {
+		maxBuilds = defaultTalentOptimizeMaxBuilds
+	}
+	// Most combinations are filtered out below, so allow more of them than builds.
+	combinations := talentCombinations(varyingTalents, maxBuilds*100)
+	if combinations == nil {
+		return TalentOptimizeResult{ErrorMsg: "Too many talent combinations, try varying fewer talents"}
+	}
+
+	var builds []TalentOptimizeBuild
+	var buildPlayers []*proto.Player
+	for _, points := range combinations {
+		player := googleProto.Clone(basePlayer).(*proto.Player)
+		talents := playerTalentsMessage(player, true)
+		for i, talent := range varyingTalents {
+			setTalentPoints(talents, talent.fieldName, points[i])
+		}
+		if classTrees.modeledPoints(talents) != basePoints || ValidateTalents(player) != nil {
+			continue
+		}
+		builds = append(builds, TalentOptimizeBuild{Points: points})
+		buildPlayers = append(buildPlayers, player)
+	}
+	if len(builds) > maxBuilds {
+		return TalentOptimizeResult{ErrorMsg: fmt.Sprintf("Found %d legal builds, but max_builds is %d", len(builds), maxBuilds)}
+	}
+
+	batch := newSimBatch(progress)
+	batch.addSims(int32(len(builds)), request.SimOptions.Iterations)
+	simErrors := make([]string, len(builds))
+
+	runInWorkerPool(len(builds), func(buildIdx int) {
+		if ctx.Err() != nil {
+			return
+		}
+		raidProto := SinglePlayerRaidProto(buildPlayers[buildIdx], request.PartyBuffs, request.RaidBuffs, request.Debuffs)
+		raidProto.Tanks = request.Tanks
+		simRequest := &proto.RaidSimRequest{
+			Raid:       raidProto,
+			Encounter:  googleProto.Clone(request.Encounter).(*proto.Encounter),
+			SimOptions: googleProto.Clone(request.SimOptions).(*proto.SimOptions),
+		}
+
+		simResult := batch.runSim(ctx, simRequest, request.SimOptions.Iterations)
+		if simResult.ErrorMsg != "" {
+			simErrors[buildIdx] = simResult.ErrorMsg
+			return
+		}
+		playerMetrics := simResult.RaidMetrics.Parties[0].Players[0]
+		builds[buildIdx].Dps = playerMetrics.Dps
+		builds[buildIdx].Tps = playerMetrics.Threat
+	})
+	if ctx.Err() != nil {
+		return TalentOptimizeResult{ErrorMsg: cancelledSimResult().ErrorMsg}
+	}
+	for _, errorMsg := range simErrors {
+		if errorMsg != "" {
+			return TalentOptimizeResult{ErrorMsg: errorMsg}
+		}
+	}
+
+	sort.SliceStable(builds, func(i, j int) bool {
+		if request.MaximizeTps {
+			return builds[i].Tps.Avg > builds[j].Tps.Avg
+		}
+		return builds[i].Dps.Avg > builds[j].Dps.Avg
+	})
+
+	return TalentOptimizeResult{
+		Builds: builds,
+	}
+}
@@ -0,0 +1,769 @@
+package core
+
+import (
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+// Talent trees for each class, matching the talent pickers in the UI. Talents
+// are listed in the same order as in talent strings.
+var talentTreesByClass = map[proto.Class]classTalentTrees{
+	proto.Class_ClassDruid: {
+		talentsProto: &proto.DruidTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Balance",
+				talents: []talentConfig{
+					{fieldName: "starlight_wrath", row: 0, col: 0, maxPoints: 5},
+					{row: 0, col: 1, maxPoints: 1},                                          // naturesGrasp
+					{row: 0, col: 2, maxPoints: 4, prereq: &talentLocation{row: 0, col: 1}}, // improvedNaturesGrasp
+					{row: 1, col: 0, maxPoints: 3},                                          // controlOfNature
+					{fieldName: "focused_starlight", row: 1, col: 1, maxPoints: 2},
+					{fieldName: "improved_moonfire", row: 1, col: 2, maxPoints: 2},
+					{fieldName: "brambles", row: 2, col: 0, maxPoints: 3},
+					{fieldName: "insect_swarm", row: 2, col: 2, maxPoints: 1},
+					{row: 2, col: 3, maxPoints: 2}, // naturesReach
+					{fieldName: "vengeance", row: 3, col: 1, maxPoints: 5, prereq: &talentLocation{row: 1, col: 1}},
+					{row: 3, col: 2, maxPoints: 3}, // celestialFocus
+					{fieldName: "lunar_guidance", row: 4, col: 0, maxPoints: 3},
+					{fieldName: "natures_grace", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "moonglow", row: 4, col: 2, maxPoints: 3},
+					{fieldName: "moonfury", row: 5, col: 1, maxPoints: 5, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "balance_of_power", row: 5, col: 2, maxPoints: 2},
+					{fieldName: "dreamstate", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "moonkin_form", row: 6, col: 1, maxPoints: 1},
+					{fieldName: "improved_faerie_fire", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "wrath_of_cenarius", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "force_of_nature", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Feral Combat",
+				talents: []talentConfig{
+					{fieldName: "ferocity", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "feral_aggression", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "feral_instinct", row: 1, col: 0, maxPoints: 3},
+					{row: 1, col: 1, maxPoints: 2}, // brutalImpact
+					{fieldName: "thick_hide", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "feral_swiftness", row: 2, col: 0, maxPoints: 2},
+					{row: 2, col: 1, maxPoints: 1}, // feralCharge
+					{fieldName: "sharpened_claws", row: 2, col: 2, maxPoints: 3},
+					{fieldName: "shredding_attacks", row: 3, col: 0, maxPoints: 2},
+					{fieldName: "predatory_strikes", row: 3, col: 1, maxPoints: 3},
+					{fieldName: "primal_fury", row: 3, col: 2, maxPoints: 2},
+					{fieldName: "savage_fury", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "faerie_fire", row: 4, col: 2, maxPoints: 1},
+					{row: 4, col: 3, maxPoints: 2}, // nurturingInstinct
+					{fieldName: "heart_of_the_wild", row: 5, col: 1, maxPoints: 5, prereq: &talentLocation{row: 3, col: 1}},
+					{fieldName: "survival_of_the_fittest", row: 5, col: 2, maxPoints: 3},
+					{row: 6, col: 0, maxPoints: 3}, // primalTenacity
+					{fieldName: "leader_of_the_pack", row: 6, col: 1, maxPoints: 1},
+					{fieldName: "improved_leader_of_the_pack", row: 6, col: 2, maxPoints: 2, prereq: &talentLocation{row: 6, col: 1}},
+					{fieldName: "predatory_instincts", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "mangle", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Restoration",
+				talents: []talentConfig{
+					{fieldName: "improved_mark_of_the_wild", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "furor", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "naturalist", row: 1, col: 0, maxPoints: 5},
+					{row: 1, col: 1, maxPoints: 5}, // naturesFocus
+					{fieldName: "natural_shapeshifter", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "intensity", row: 2, col: 0, maxPoints: 3},
+					{fieldName: "subtlety", row: 2, col: 1, maxPoints: 5},
+					{fieldName: "omen_of_clarity", row: 2, col: 2, maxPoints: 1},
+					{row: 3, col: 1, maxPoints: 5}, // tranquilSpirit
+					{row: 3, col: 2, maxPoints: 3}, // improvedRejuvenation
+					{fieldName: "natures_swiftness", row: 4, col: 0, maxPoints: 1, prereq: &talentLocation{row: 2, col: 0}},
+					{fieldName: "gift_of_nature", row: 4, col: 1, maxPoints: 5},
+					{row: 4, col: 3, maxPoints: 2}, // improvedTranquility
+					{row: 5, col: 0, maxPoints: 2}, // empoweredTouch
+					{fieldName: "improved_regrowth", row: 5, col: 2, maxPoints: 5, prereq: &talentLocation{row: 3, col: 2}},
+					{fieldName: "living_spirit", row: 6, col: 0, maxPoints: 3},
+					{row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}}, // swiftmend
+					{fieldName: "natural_perfection", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "empowered_rejuvenation", row: 7, col: 1, maxPoints: 5},
+					{row: 8, col: 1, maxPoints: 1}, // treeOfLife
+				},
+			},
+		},
+	},
+	proto.Class_ClassHunter: {
+		talentsProto: &proto.HunterTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Beast Mastery",
+				talents: []talentConfig{
+					{fieldName: "improved_aspect_of_the_hawk", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "endurance_training", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "focused_fire", row: 1, col: 0, maxPoints: 2},
+					{row: 1, col: 1, maxPoints: 3}, // improvedAspectOfTheMonkey
+					{row: 1, col: 2, maxPoints: 3}, // thickHide
+					{row: 1, col: 3, maxPoints: 2}, // improvedRevivePet
+					{row: 2, col: 0, maxPoints: 2}, // pathfinding
+					{row: 2, col: 1, maxPoints: 1},
+					{fieldName: "unleashed_fury", row: 2, col: 2, maxPoints: 5},
+					{row: 3, col: 1, maxPoints: 2}, // improvedMendPet
+					{fieldName: "ferocity", row: 3, col: 2, maxPoints: 5},
+					{row: 4, col: 0, maxPoints: 2},
+					{row: 4, col: 1, maxPoints: 1}, // Intimidation
+					{fieldName: "bestial_discipline", row: 4, col: 3, maxPoints: 2},
+					{fieldName: "animal_handler", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "frenzy", row: 5, col: 2, maxPoints: 5, prereq: &talentLocation{row: 3, col: 2}},
+					{fieldName: "ferocious_inspiration", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "bestial_wrath", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{row: 6, col: 2, maxPoints: 3}, // catlikeReflexes
+					{fieldName: "serpents_swiftness", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "the_beast_within", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Marksmanship",
+				talents: []talentConfig{
+					{row: 0, col: 1, maxPoints: 5}, // improvedConsussiveShot
+					{fieldName: "lethal_shots", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "improved_hunters_mark", row: 1, col: 1, maxPoints: 5},
+					{fieldName: "efficiency", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "go_for_the_throat", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "improved_arcane_shot", row: 2, col: 1, maxPoints: 5},
+					{fieldName: "aimed_shot", row: 2, col: 2, maxPoints: 1},
+					{fieldName: "rapid_killing", row: 2, col: 3, maxPoints: 2},
+					{fieldName: "improved_stings", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "mortal_shots", row: 3, col: 2, maxPoints: 5, prereq: &talentLocation{row: 2, col: 2}},
+					{row: 4, col: 0, maxPoints: 3}, // concussiveBarrage
+					{fieldName: "scatter_shot", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "barrage", row: 4, col: 2, maxPoints: 3},
+					{fieldName: "combat_experience", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "ranged_weapon_specialization", row: 5, col: 3, maxPoints: 5},
+					{fieldName: "careful_aim", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "trueshot_aura", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "improved_barrage", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "master_marksman", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "silencing_shot", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 7, col: 1}},
+				},
+			},
+			{
+				name: "Survival",
+				talents: []talentConfig{
+					{fieldName: "monster_slaying", row: 0, col: 0, maxPoints: 3},
+					{fieldName: "humanoid_slaying", row: 0, col: 1, maxPoints: 3},
+					{row: 0, col: 2, maxPoints: 3}, // hawkEye
+					{fieldName: "savage_strikes", row: 0, col: 3, maxPoints: 2},
+					{row: 1, col: 0, maxPoints: 3}, // entrapment
+					{fieldName: "deflection", row: 1, col: 1, maxPoints: 5},
+					{row: 1, col: 2, maxPoints: 3}, // improvedWingClip
+					{fieldName: "clever_traps", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "survivalist", row: 2, col: 1, maxPoints: 5},
+					{row: 2, col: 2, maxPoints: 1}, // deterrance
+					{fieldName: "trap_mastery", row: 3, col: 0, maxPoints: 2},
+					{fieldName: "surefooted", row: 3, col: 1, maxPoints: 3},
+					{row: 3, col: 3, maxPoints: 2}, // improvedFeignDeath
+					{fieldName: "survival_instincts", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "killer_instinct", row: 4, col: 1, maxPoints: 3},
+					{row: 4, col: 2, maxPoints: 1, prereq: &talentLocation{row: 2, col: 2}}, // counterattack
+					{fieldName: "resourcefulness", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "lightning_reflexes", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "thrill_of_the_hunt", row: 6, col: 0, maxPoints: 3},
+					{row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}}, // wyvernSting
+					{fieldName: "expose_weakness", row: 6, col: 2, maxPoints: 3, prereq: &talentLocation{row: 5, col: 2}},
+					{fieldName: "master_tactician", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "readiness", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 7, col: 1}},
+				},
+			},
+		},
+	},
+	proto.Class_ClassMage: {
+		talentsProto: &proto.MageTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Arcane",
+				talents: []talentConfig{
+					{fieldName: "arcane_subtlety", row: 0, col: 0, maxPoints: 2},
+					{fieldName: "arcane_focus", row: 0, col: 1, maxPoints: 5},
+					{row: 0, col: 2, maxPoints: 5}, // improvedArcaneMissiles
+					{fieldName: "wand_specialization", row: 1, col: 0, maxPoints: 2},
+					{fieldName: "magic_absorption", row: 1, col: 1, maxPoints: 5},
+					{fieldName: "arcane_concentration", row: 1, col: 2, maxPoints: 5},
+					{row: 2, col: 0, maxPoints: 2}, // magicAttunement
+					{fieldName: "arcane_impact", row: 2, col: 1, maxPoints: 3},
+					{row: 2, col: 3, maxPoints: 1}, // arcaneFortitude
+					{row: 3, col: 0, maxPoints: 2}, // improvedManaShield
+					{row: 3, col: 1, maxPoints: 2}, // improvedCounterspell
+					{fieldName: "arcane_meditation", row: 3, col: 3, maxPoints: 3},
+					{row: 4, col: 0, maxPoints: 2}, // improvedBlink
+					{fieldName: "presence_of_mind", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "arcane_mind", row: 4, col: 3, maxPoints: 5},
+					{row: 5, col: 0, maxPoints: 2}, // prismaticCloak
+					{fieldName: "arcane_instability", row: 5, col: 1, maxPoints: 3, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "arcane_potency", row: 5, col: 2, maxPoints: 3, prereq: &talentLocation{row: 1, col: 2}},
+					{fieldName: "empowered_arcane_missiles", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "arcane_power", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 5, col: 1}},
+					{fieldName: "spell_power", row: 6, col: 2, maxPoints: 2},
+					{fieldName: "mind_mastery", row: 7, col: 1, maxPoints: 5},
+					{row: 8, col: 1, maxPoints: 1}, // slow
+				},
+			},
+			{
+				name: "Fire",
+				talents: []talentConfig{
+					{fieldName: "improved_fireball", row: 0, col: 1, maxPoints: 5},
+					{row: 0, col: 2, maxPoints: 5}, // impact
+					{fieldName: "ignite", row: 1, col: 0, maxPoints: 5},
+					{row: 1, col: 1, maxPoints: 2}, // flameThrowing
+					{fieldName: "improved_fire_blast", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "incineration", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "improved_flamestrike", row: 2, col: 1, maxPoints: 3},
+					{fieldName: "pyroblast", row: 2, col: 2, maxPoints: 1},
+					{fieldName: "burning_soul", row: 2, col: 3, maxPoints: 2},
+					{fieldName: "improved_scorch", row: 3, col: 0, maxPoints: 3},
+					{row: 3, col: 1, maxPoints: 2}, // moltenShields
+					{fieldName: "master_of_elements", row: 3, col: 3, maxPoints: 3},
+					{fieldName: "playing_with_fire", row: 4, col: 0, maxPoints: 3},
+					{fieldName: "critical_mass", row: 4, col: 1, maxPoints: 3},
+					{fieldName: "blast_wave", row: 4, col: 2, maxPoints: 1, prereq: &talentLocation{row: 2, col: 2}},
+					{row: 5, col: 0, maxPoints: 2}, // blazingSpeed
+					{fieldName: "fire_power", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "pyromaniac", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "combustion", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "molten_fury", row: 6, col: 2, maxPoints: 2},
+					{fieldName: "empowered_fireball", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "dragons_breath", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Frost",
+				talents: []talentConfig{
+					{row: 0, col: 0, maxPoints: 2}, // frostWarding
+					{fieldName: "improved_frostbolt", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "elemental_precision", row: 0, col: 2, maxPoints: 3},
+					{fieldName: "ice_shards", row: 1, col: 0, maxPoints: 5},
+					{row: 1, col: 1, maxPoints: 3}, // frostbite
+					{fieldName: "improved_frost_nova", row: 1, col: 2, maxPoints: 2},
+					{row: 1, col: 3, maxPoints: 3}, // permafrost
+					{fieldName: "piercing_ice", row: 2, col: 0, maxPoints: 3},
+					{fieldName: "icy_veins", row: 2, col: 1, maxPoints: 1},
+					{row: 2, col: 3, maxPoints: 3}, // improvedBlizzard
+					{row: 3, col: 0, maxPoints: 2}, // arcticReach
+					{fieldName: "frost_channeling", row: 3, col: 1, maxPoints: 3},
+					{fieldName: "shatter", row: 3, col: 2, maxPoints: 5, prereq: &talentLocation{row: 1, col: 2}},
+					{row: 4, col: 0, maxPoints: 3}, // frozenCore
+					{fieldName: "cold_snap", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "improved_cone_of_cold", row: 4, col: 2, maxPoints: 3},
+					{fieldName: "ice_floes", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "winters_chill", row: 5, col: 2, maxPoints: 5},
+					{row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}}, // iceBarrier
+					{fieldName: "arctic_winds", row: 6, col: 2, maxPoints: 5},
+					{fieldName: "empowered_frostbolt", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "summon_water_elemental", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+		},
+	},
+	proto.Class_ClassPaladin: {
+		talentsProto: &proto.PaladinTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Holy",
+				talents: []talentConfig{
+					{fieldName: "divine_strength", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "divine_intellect", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 1, maxPoints: 5}, // spiritualFocus
+					{fieldName: "improved_seal_of_righteousness", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "healing_light", row: 2, col: 0, maxPoints: 3},
+					{row: 2, col: 1, maxPoints: 1}, // auraMastery
+					{row: 2, col: 2, maxPoints: 2}, // improvedLayOnHands
+					{row: 2, col: 3, maxPoints: 2}, // unyieldingFaith
+					{fieldName: "illumination", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "improved_blessing_of_wisdom", row: 3, col: 2, maxPoints: 2},
+					{row: 4, col: 0, maxPoints: 3}, // pureOfHeart
+					{fieldName: "divine_favor", row: 4, col: 1, maxPoints: 1, prereq: &talentLocation{row: 3, col: 1}},
+					{fieldName: "sanctified_light", row: 4, col: 2, maxPoints: 3},
+					{fieldName: "purifying_power", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "holy_power", row: 5, col: 2, maxPoints: 5},
+					{row: 6, col: 0, maxPoints: 3}, // lightsGrace
+					{fieldName: "holy_shock", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "blessed_life", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "holy_guidance", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "divine_illumination", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Protection",
+				talents: []talentConfig{
+					{fieldName: "improved_devotion_aura", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "redoubt", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "precision", row: 1, col: 0, maxPoints: 3},
+					{row: 1, col: 1, maxPoints: 2}, // guardiansFavor
+					{fieldName: "toughness", row: 1, col: 3, maxPoints: 5},
+					{fieldName: "blessing_of_kings", row: 2, col: 0, maxPoints: 1},
+					{fieldName: "improved_righteous_fury", row: 2, col: 1, maxPoints: 3},
+					{fieldName: "shield_specialization", row: 2, col: 2, maxPoints: 3, prereq: &talentLocation{row: 0, col: 2}},
+					{fieldName: "anticipation", row: 2, col: 3, maxPoints: 5},
+					{row: 3, col: 0, maxPoints: 2}, // stoicism
+					{row: 3, col: 1, maxPoints: 3}, // improvedHammerOfJustice
+					{row: 3, col: 2, maxPoints: 3}, // improvedConcentrationAura
+					{fieldName: "spell_warding", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "blessing_of_sanctuary", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "reckoning", row: 4, col: 2, maxPoints: 5},
+					{fieldName: "sacred_duty", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "one_handed_weapon_specialization", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "improved_holy_shield", row: 6, col: 0, maxPoints: 2, prereq: &talentLocation{row: 6, col: 1}},
+					{fieldName: "holy_shield", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "ardent_defender", row: 6, col: 2, maxPoints: 5},
+					{fieldName: "combat_expertise", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "avengers_shield", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Retribution",
+				talents: []talentConfig{
+					{fieldName: "improved_blessing_of_might", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "benediction", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "improved_judgement", row: 1, col: 0, maxPoints: 2},
+					{fieldName: "improved_seal_of_the_crusader", row: 1, col: 1, maxPoints: 3},
+					{fieldName: "deflection", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "vindication", row: 2, col: 0, maxPoints: 3},
+					{fieldName: "conviction", row: 2, col: 1, maxPoints: 5},
+					{fieldName: "seal_of_command", row: 2, col: 2, maxPoints: 1},
+					{fieldName: "pursuit_of_justice", row: 2, col: 3, maxPoints: 3},
+					{fieldName: "eye_for_an_eye", row: 3, col: 0, maxPoints: 2},
+					{fieldName: "improved_retribution_aura", row: 3, col: 2, maxPoints: 2},
+					{fieldName: "crusade", row: 3, col: 3, maxPoints: 3},
+					{fieldName: "two_handed_weapon_specialization", row: 4, col: 0, maxPoints: 3},
+					{fieldName: "sanctity_aura", row: 4, col: 2, maxPoints: 1},
+					{fieldName: "improved_sanctity_aura", row: 4, col: 3, maxPoints: 2, prereq: &talentLocation{row: 4, col: 2}},
+					{fieldName: "vengeance", row: 5, col: 1, maxPoints: 5},
+					{fieldName: "sanctified_judgement", row: 5, col: 2, maxPoints: 3},
+					{fieldName: "sanctified_seals", row: 6, col: 0, maxPoints: 3},
+					{row: 6, col: 1, maxPoints: 1}, // repentance
+					{fieldName: "divine_purpose", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "fanaticism", row: 7, col: 1, maxPoints: 5, prereq: &talentLocation{row: 6, col: 1}},
+					{fieldName: "crusader_strike", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+		},
+	},
+	proto.Class_ClassPriest: {
+		talentsProto: &proto.PriestTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Discipline",
+				talents: []talentConfig{
+					{row: 0, col: 1, maxPoints: 5}, // unbreakableWill
+					{fieldName: "wand_specialization", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "silent_resolve", row: 1, col: 0, maxPoints: 5},
+					{fieldName: "improved_power_word_fortitude", row: 1, col: 1, maxPoints: 2},
+					{fieldName: "improved_power_word_shield", row: 1, col: 2, maxPoints: 3},
+					{row: 1, col: 3, maxPoints: 2}, // martyrdom
+					{row: 2, col: 0, maxPoints: 3}, // absolution
+					{fieldName: "inner_focus", row: 2, col: 1, maxPoints: 1},
+					{fieldName: "meditation", row: 2, col: 2, maxPoints: 3},
+					{row: 3, col: 0, maxPoints: 3}, // improvedInnerFire
+					{fieldName: "mental_agility", row: 3, col: 1, maxPoints: 5},
+					{row: 3, col: 3, maxPoints: 2}, // improvedManaBurn
+					{fieldName: "mental_strength", row: 4, col: 1, maxPoints: 5},
+					{fieldName: "divine_spirit", row: 4, col: 2, maxPoints: 1, prereq: &talentLocation{row: 2, col: 2}},
+					{fieldName: "improved_divine_spirit", row: 4, col: 3, maxPoints: 2, prereq: &talentLocation{row: 4, col: 2}},
+					{fieldName: "focused_power", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "force_of_will", row: 5, col: 2, maxPoints: 5},
+					{row: 6, col: 0, maxPoints: 3}, // focusedWill
+					{fieldName: "power_infusion", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{row: 6, col: 2, maxPoints: 5}, // reflectiveShield
+					{fieldName: "enlightenment", row: 7, col: 1, maxPoints: 5},
+					{row: 8, col: 1, maxPoints: 1}, // painSuppresion
+				},
+			},
+			{
+				name: "Holy",
+				talents: []talentConfig{
+					{fieldName: "healing_focus", row: 0, col: 0, maxPoints: 2},
+					{fieldName: "improved_renew", row: 0, col: 1, maxPoints: 3},
+					{fieldName: "holy_specialization", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 1, maxPoints: 5}, // spellWarding
+					{fieldName: "divine_fury", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "holy_nova", row: 2, col: 0, maxPoints: 1},
+					{row: 2, col: 1, maxPoints: 3}, // blessedRecovery
+					{row: 2, col: 3, maxPoints: 3}, // inspiration
+					{row: 3, col: 0, maxPoints: 2}, // holyReach
+					{fieldName: "improved_healing", row: 3, col: 1, maxPoints: 3},
+					{fieldName: "searing_light", row: 3, col: 2, maxPoints: 2, prereq: &talentLocation{row: 1, col: 2}},
+					{fieldName: "healing_prayers", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "spirit_of_redemption", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "spiritual_guidance", row: 4, col: 2, maxPoints: 5},
+					{fieldName: "surge_of_light", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "spiritual_healing", row: 5, col: 2, maxPoints: 5},
+					{row: 6, col: 0, maxPoints: 3},                                          // holyConcentration
+					{row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}}, // lightwell
+					{row: 6, col: 2, maxPoints: 3},                                          // blessedResilience
+					{fieldName: "empowered_healing", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "circle_of_healing", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Shadow",
+				talents: []talentConfig{
+					{row: 0, col: 1, maxPoints: 5}, // spiritTap
+					{row: 0, col: 2, maxPoints: 5}, // blackout
+					{fieldName: "shadow_affinity", row: 1, col: 0, maxPoints: 3},
+					{fieldName: "improved_shadow_word_pain", row: 1, col: 1, maxPoints: 2},
+					{fieldName: "shadow_focus", row: 1, col: 2, maxPoints: 5},
+					{row: 2, col: 0, maxPoints: 2}, // improvedPsychicScream
+					{fieldName: "improved_mind_blast", row: 2, col: 1, maxPoints: 5},
+					{fieldName: "mind_flay", row: 2, col: 2, maxPoints: 1},
+					{row: 3, col: 1, maxPoints: 2}, // improvedFade
+					{row: 3, col: 2, maxPoints: 2}, // shadowReach
+					{fieldName: "shadow_weaving", row: 3, col: 3, maxPoints: 5},
+					{row: 4, col: 0, maxPoints: 1, prereq: &talentLocation{row: 2, col: 0}}, // silence
+					{fieldName: "vampiric_embrace", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "improved_vampiric_embrace", row: 4, col: 2, maxPoints: 2},
+					{fieldName: "focused_mind", row: 4, col: 3, maxPoints: 3},
+					{row: 5, col: 0, maxPoints: 2}, // shadowResilience
+					{fieldName: "darkness", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "shadowform", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "shadow_power", row: 6, col: 2, maxPoints: 5},
+					{fieldName: "misery", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "vampiric_touch", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+		},
+	},
+	proto.Class_ClassRogue: {
+		talentsProto: &proto.RogueTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Assassination",
+				talents: []talentConfig{
+					{fieldName: "improved_eviscerate", row: 0, col: 0, maxPoints: 3},
+					{row: 0, col: 1, maxPoints: 2}, // remorselessAttacks
+					{fieldName: "malice", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "ruthlessness", row: 1, col: 0, maxPoints: 3},
+					{fieldName: "murder", row: 1, col: 1, maxPoints: 2},
+					{fieldName: "puncturing_wounds", row: 1, col: 3, maxPoints: 3},
+					{fieldName: "relentless_strikes", row: 2, col: 0, maxPoints: 1},
+					{fieldName: "improved_expose_armor", row: 2, col: 1, maxPoints: 2},
+					{fieldName: "lethality", row: 2, col: 2, maxPoints: 5, prereq: &talentLocation{row: 0, col: 2}},
+					{fieldName: "vile_poisons", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "improved_poisons", row: 3, col: 2, maxPoints: 5},
+					{row: 4, col: 0, maxPoints: 2}, // fleetFooted
+					{fieldName: "cold_blood", row: 4, col: 1, maxPoints: 1},
+					{row: 4, col: 2, maxPoints: 3}, // improvedKidneyShot
+					{fieldName: "quick_recovery", row: 4, col: 3, maxPoints: 2},
+					{fieldName: "seal_fate", row: 5, col: 1, maxPoints: 5, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "master_poisoner", row: 5, col: 2, maxPoints: 2},
+					{fieldName: "vigor", row: 6, col: 1, maxPoints: 1},
+					{row: 6, col: 2, maxPoints: 5}, // deadenedNerves
+					{fieldName: "find_weakness", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "mutilate", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Combat",
+				talents: []talentConfig{
+					{row: 0, col: 0, maxPoints: 3}, // improvedGouge
+					{fieldName: "improved_sinister_strike", row: 0, col: 1, maxPoints: 2},
+					{fieldName: "lightning_reflexes", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "improved_slice_and_dice", row: 1, col: 0, maxPoints: 3},
+					{fieldName: "deflection", row: 1, col: 1, maxPoints: 5},
+					{fieldName: "precision", row: 1, col: 2, maxPoints: 5},
+					{row: 2, col: 0, maxPoints: 2},                                          // endurance
+					{row: 2, col: 1, maxPoints: 1, prereq: &talentLocation{row: 1, col: 1}}, // riposte
+					{row: 2, col: 3, maxPoints: 2},                                          // improvedSprint
+					{row: 3, col: 0, maxPoints: 2},                                          // improvedKick
+					{fieldName: "dagger_specialization", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "dual_wield_specialization", row: 3, col: 2, maxPoints: 5, prereq: &talentLocation{row: 1, col: 2}},
+					{fieldName: "mace_specialization", row: 4, col: 0, maxPoints: 5},
+					{fieldName: "blade_flurry", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "sword_specialization", row: 4, col: 2, maxPoints: 5},
+					{fieldName: "fist_weapon_specialization", row: 4, col: 3, maxPoints: 5},
+					{row: 5, col: 0, maxPoints: 2}, // bladeTwisting
+					{fieldName: "weapon_expertise", row: 5, col: 1, maxPoints: 2, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "aggression", row: 5, col: 2, maxPoints: 3},
+					{fieldName: "vitality", row: 6, col: 0, maxPoints: 2},
+					{fieldName: "adrenaline_rush", row: 6, col: 1, maxPoints: 1},
+					{row: 6, col: 2, maxPoints: 2}, // nervesOfSteel
+					{fieldName: "combat_potency", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "surprise_attacks", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Subtlety",
+				talents: []talentConfig{
+					{row: 0, col: 1, maxPoints: 5}, // masterOfDeception
+					{fieldName: "opportunity", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "sleight_of_hand", row: 1, col: 0, maxPoints: 2},
+					{row: 1, col: 1, maxPoints: 2}, // dirtyTricks
+					{row: 1, col: 2, maxPoints: 5}, // camoflauge
+					{fieldName: "initiative", row: 2, col: 0, maxPoints: 3},
+					{fieldName: "ghostly_strike", row: 2, col: 1, maxPoints: 1},
+					{fieldName: "improved_ambush", row: 2, col: 2, maxPoints: 3},
+					{row: 3, col: 0, maxPoints: 3}, // setup
+					{fieldName: "elusiveness", row: 3, col: 1, maxPoints: 2},
+					{fieldName: "serrated_blades", row: 3, col: 2, maxPoints: 3},
+					{row: 4, col: 0, maxPoints: 2}, // heightenedSenses
+					{fieldName: "preparation", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "dirty_deeds", row: 4, col: 2, maxPoints: 2},
+					{fieldName: "hemorrhage", row: 4, col: 3, maxPoints: 1, prereq: &talentLocation{row: 3, col: 2}},
+					{fieldName: "master_of_subtlety", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "deadliness", row: 5, col: 2, maxPoints: 5},
+					{row: 6, col: 0, maxPoints: 3}, // envelopingShadows
+					{fieldName: "premeditation", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{row: 6, col: 2, maxPoints: 3}, // cheatDeath
+					{fieldName: "sinister_calling", row: 7, col: 1, maxPoints: 5, prereq: &talentLocation{row: 6, col: 1}},
+					{fieldName: "shadowstep", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+		},
+	},
+	proto.Class_ClassShaman: {
+		talentsProto: &proto.ShamanTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Elemental",
+				talents: []talentConfig{
+					{fieldName: "convection", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "concussion", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 0, maxPoints: 2}, // earthsGrasp
+					{row: 1, col: 1, maxPoints: 3}, // elementalWarding
+					{fieldName: "call_of_flame", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "elemental_focus", row: 2, col: 0, maxPoints: 1},
+					{fieldName: "reverberation", row: 2, col: 1, maxPoints: 5},
+					{fieldName: "call_of_thunder", row: 2, col: 2, maxPoints: 5},
+					{fieldName: "improved_fire_totems", row: 3, col: 0, maxPoints: 2},
+					{row: 3, col: 1, maxPoints: 3}, // eyeOfTheStorm
+					{fieldName: "elemental_devastation", row: 3, col: 3, maxPoints: 3},
+					{row: 4, col: 0, maxPoints: 2}, // stormReach
+					{fieldName: "elemental_fury", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "unrelenting_storm", row: 4, col: 3, maxPoints: 5},
+					{fieldName: "elemental_precision", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "lightning_mastery", row: 5, col: 2, maxPoints: 5, prereq: &talentLocation{row: 2, col: 2}},
+					{fieldName: "elemental_mastery", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{row: 6, col: 2, maxPoints: 3}, // elementalShields
+					{fieldName: "lightning_overload", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "totemOfWrath", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 7, col: 1}},
+				},
+			},
+			{
+				name: "Enhancement",
+				talents: []talentConfig{
+					{fieldName: "ancestral_knowledge", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "shield_specialization", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 0, maxPoints: 2}, // guardianTotems
+					{fieldName: "thundering_strikes", row: 1, col: 1, maxPoints: 5},
+					{row: 1, col: 2, maxPoints: 2}, // improvedGhostWolf
+					{row: 1, col: 3, maxPoints: 3}, // improvedLightningShield
+					{fieldName: "enhancing_totems", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "shamanistic_focus", row: 2, col: 2, maxPoints: 1},
+					{fieldName: "anticipation", row: 2, col: 3, maxPoints: 5},
+					{fieldName: "flurry", row: 3, col: 1, maxPoints: 5, prereq: &talentLocation{row: 1, col: 1}},
+					{fieldName: "toughness", row: 3, col: 2, maxPoints: 5},
+					{fieldName: "improved_weapon_totems", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "spirit_weapons", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "elemental_weapons", row: 4, col: 2, maxPoints: 3},
+					{fieldName: "mental_quickness", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "weapon_mastery", row: 5, col: 3, maxPoints: 5},
+					{fieldName: "dual_wield_specialization", row: 6, col: 0, maxPoints: 3, prereq: &talentLocation{row: 6, col: 1}},
+					{row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}}, // dualWield
+					{fieldName: "stormstrike", row: 6, col: 2, maxPoints: 1, prereq: &talentLocation{row: 4, col: 2}},
+					{fieldName: "unleashed_rage", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "shamanistic_rage", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Restoration",
+				talents: []talentConfig{
+					{fieldName: "improved_healing_wave", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "tidal_focus", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 0, maxPoints: 2}, // improvedReincarnation
+					{row: 1, col: 1, maxPoints: 3}, // ancestralUealing
+					{fieldName: "totemic_focus", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "natures_guidance", row: 2, col: 0, maxPoints: 3},
+					{row: 2, col: 1, maxPoints: 5}, // healingFocus
+					{row: 2, col: 2, maxPoints: 1}, // totemicMastery
+					{row: 2, col: 3, maxPoints: 3}, // healingGrace
+					{fieldName: "restorative_totems", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "tidal_mastery", row: 3, col: 2, maxPoints: 5},
+					{row: 4, col: 0, maxPoints: 3}, // healingWay
+					{fieldName: "natures_swiftness", row: 4, col: 2, maxPoints: 1},
+					{row: 4, col: 3, maxPoints: 3}, // focusedMind
+					{fieldName: "purification", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "mana_tide_totem", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 3, col: 1}},
+					{row: 6, col: 2, maxPoints: 5}, // naturesGuardian
+					{fieldName: "natures_blessing", row: 7, col: 1, maxPoints: 3},
+					{fieldName: "improved_chain_heal", row: 7, col: 2, maxPoints: 2},
+					{row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 7, col: 1}}, // earthShield
+				},
+			},
+		},
+	},
+	proto.Class_ClassWarlock: {
+		talentsProto: &proto.WarlockTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Affliction",
+				talents: []talentConfig{
+					{fieldName: "suppression", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "improved_corruption", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 0, maxPoints: 2}, // improvedCurseOfWeakness
+					{fieldName: "improved_drain_soul", row: 1, col: 1, maxPoints: 2},
+					{fieldName: "improved_life_tap", row: 1, col: 2, maxPoints: 2},
+					{fieldName: "soul_siphon", row: 1, col: 3, maxPoints: 2},
+					{fieldName: "improved_curse_of_agony", row: 2, col: 0, maxPoints: 2},
+					{row: 2, col: 1, maxPoints: 5}, // felConcentration
+					{fieldName: "amplify_curse", row: 2, col: 2, maxPoints: 1},
+					{row: 3, col: 0, maxPoints: 2}, // grimReach
+					{fieldName: "nightfall", row: 3, col: 1, maxPoints: 2},
+					{fieldName: "empowered_corruption", row: 3, col: 3, maxPoints: 3},
+					{fieldName: "shadow_embrace", row: 4, col: 0, maxPoints: 5},
+					{fieldName: "siphon_life", row: 4, col: 1, maxPoints: 1},
+					{row: 4, col: 2, maxPoints: 1, prereq: &talentLocation{row: 2, col: 2}}, // curseOfExhaustion
+					{fieldName: "shadow_mastery", row: 5, col: 1, maxPoints: 5, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "contagion", row: 6, col: 1, maxPoints: 5},
+					{fieldName: "dark_pact", row: 6, col: 2, maxPoints: 1},
+					{row: 7, col: 0, maxPoints: 2}, // improvedHowlOfTerror
+					{fieldName: "malediction", row: 7, col: 2, maxPoints: 3},
+					{fieldName: "unstable_affliction", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Demonology",
+				talents: []talentConfig{
+					{row: 0, col: 0, maxPoints: 2}, // improvedHealthstone
+					{fieldName: "improved_imp", row: 0, col: 1, maxPoints: 3},
+					{fieldName: "demonic_embrace", row: 0, col: 2, maxPoints: 5},
+					{row: 1, col: 0, maxPoints: 2}, // improvedHealthFunnel
+					{fieldName: "improved_voidwalker", row: 1, col: 1, maxPoints: 3},
+					{fieldName: "fel_intellect", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "improved_sayaad", row: 2, col: 0, maxPoints: 3},
+					{row: 2, col: 1, maxPoints: 1}, // felDomination
+					{fieldName: "fel_stamina", row: 2, col: 2, maxPoints: 3},
+					{fieldName: "demonic_aegis", row: 2, col: 3, maxPoints: 3},
+					{row: 3, col: 1, maxPoints: 2, prereq: &talentLocation{row: 2, col: 1}}, // masterSummoner
+					{fieldName: "unholy_power", row: 3, col: 2, maxPoints: 5},
+					{fieldName: "improved_enslave_demon", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "demonic_sacrifice", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "master_conjuror", row: 4, col: 3, maxPoints: 2},
+					{fieldName: "mana_feed", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "master_demonologist", row: 5, col: 2, maxPoints: 5, prereq: &talentLocation{row: 3, col: 2}},
+					{row: 6, col: 0, maxPoints: 3}, // demonicResilience
+					{fieldName: "soul_link", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "demonic_knowledge", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "demonic_tactics", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "summon_felguard", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Destruction",
+				talents: []talentConfig{
+					{fieldName: "improved_shadow_bolt", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "cataclysm", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "bane", row: 1, col: 1, maxPoints: 5},
+					{row: 1, col: 2, maxPoints: 5}, // aftermath
+					{fieldName: "improved_firebolt", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "improved_lash_of_pain", row: 2, col: 1, maxPoints: 2},
+					{fieldName: "devastation", row: 2, col: 2, maxPoints: 5},
+					{fieldName: "shadowburn", row: 2, col: 3, maxPoints: 1},
+					{row: 3, col: 0, maxPoints: 2}, // intensity
+					{fieldName: "destructive_reach", row: 3, col: 1, maxPoints: 2},
+					{fieldName: "improved_searing_pain", row: 3, col: 3, maxPoints: 3},
+					{row: 4, col: 0, maxPoints: 2, prereq: &talentLocation{row: 3, col: 0}}, // pyroclasm
+					{fieldName: "improved_immolate", row: 4, col: 1, maxPoints: 5},
+					{fieldName: "ruin", row: 4, col: 2, maxPoints: 1, prereq: &talentLocation{row: 2, col: 2}},
+					{row: 5, col: 0, maxPoints: 3}, // netherProtection
+					{fieldName: "emberstorm", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "backlash", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "conflagrate", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "soul_leech", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "shadow_and_flame", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "shadowfury", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 7, col: 1}},
+				},
+			},
+		},
+	},
+	proto.Class_ClassWarrior: {
+		talentsProto: &proto.WarriorTalents{},
+		trees: [3]talentTreeConfig{
+			{
+				name: "Arms",
+				talents: []talentConfig{
+					{fieldName: "improved_heroic_strike", row: 0, col: 0, maxPoints: 3},
+					{fieldName: "deflection", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "improved_rend", row: 0, col: 2, maxPoints: 3},
+					{fieldName: "improved_charge", row: 1, col: 0, maxPoints: 2},
+					{row: 1, col: 1, maxPoints: 5}, // ironWill
+					{fieldName: "improved_thunder_clap", row: 1, col: 2, maxPoints: 3},
+					{fieldName: "improved_overpower", row: 2, col: 0, maxPoints: 2},
+					{fieldName: "anger_management", row: 2, col: 1, maxPoints: 1},
+					{fieldName: "deep_wounds", row: 2, col: 2, maxPoints: 3},
+					{fieldName: "two_handed_weapon_specialization", row: 3, col: 1, maxPoints: 5},
+					{fieldName: "impale", row: 3, col: 2, maxPoints: 2, prereq: &talentLocation{row: 2, col: 2}},
+					{fieldName: "poleaxe_specialization", row: 4, col: 0, maxPoints: 5},
+					{fieldName: "death_wish", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "mace_specialization", row: 4, col: 2, maxPoints: 5},
+					{fieldName: "sword_specialization", row: 4, col: 3, maxPoints: 5},
+					{row: 5, col: 0, maxPoints: 2}, // improvedIntercept
+					{row: 5, col: 2, maxPoints: 3}, // improvedHamstring
+					{fieldName: "improved_disciplines", row: 5, col: 3, maxPoints: 3},
+					{fieldName: "blood_frenzy", row: 6, col: 0, maxPoints: 2},
+					{fieldName: "mortal_strike", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{row: 6, col: 2, maxPoints: 2}, // secondWind
+					{fieldName: "improved_mortal_strike", row: 7, col: 1, maxPoints: 5, prereq: &talentLocation{row: 6, col: 1}},
+					{fieldName: "endless_rage", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+			{
+				name: "Fury",
+				talents: []talentConfig{
+					{fieldName: "booming_voice", row: 0, col: 1, maxPoints: 5},
+					{fieldName: "cruelty", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "improved_demoralizing_shout", row: 1, col: 1, maxPoints: 5},
+					{fieldName: "unbridled_wrath", row: 1, col: 2, maxPoints: 5},
+					{fieldName: "improved_cleave", row: 2, col: 0, maxPoints: 3},
+					{row: 2, col: 1, maxPoints: 1}, // piercingHowl
+					{row: 2, col: 2, maxPoints: 3}, // bloodCraze
+					{fieldName: "commanding_presence", row: 2, col: 3, maxPoints: 5},
+					{fieldName: "dual_wield_specialization", row: 3, col: 0, maxPoints: 5},
+					{fieldName: "improved_execute", row: 3, col: 1, maxPoints: 2},
+					{row: 3, col: 2, maxPoints: 5}, // enrage
+					{fieldName: "improved_slam", row: 4, col: 0, maxPoints: 2},
+					{fieldName: "sweeping_strikes", row: 4, col: 1, maxPoints: 1},
+					{fieldName: "weapon_mastery", row: 4, col: 3, maxPoints: 2},
+					{fieldName: "improved_berserker_rage", row: 5, col: 0, maxPoints: 2},
+					{fieldName: "flurry", row: 5, col: 2, maxPoints: 5, prereq: &talentLocation{row: 3, col: 2}},
+					{fieldName: "precision", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "bloodthirst", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "improved_whirlwind", row: 6, col: 2, maxPoints: 2},
+					{fieldName: "improved_berserker_stance", row: 7, col: 2, maxPoints: 5},
+					{fieldName: "rampage", row: 8, col: 1, maxPoints: 1, prereq: &talentLocation{row: 6, col: 1}},
+				},
+			},
+			{
+				name: "Protection",
+				talents: []talentConfig{
+					{fieldName: "improved_bloodrage", row: 0, col: 0, maxPoints: 2},
+					{fieldName: "tactical_mastery", row: 0, col: 1, maxPoints: 3},
+					{fieldName: "anticipation", row: 0, col: 2, maxPoints: 5},
+					{fieldName: "shield_specialization", row: 1, col: 1, maxPoints: 5},
+					{fieldName: "toughness", row: 1, col: 2, maxPoints: 5},
+					{row: 2, col: 0, maxPoints: 1}, // lastStand
+					{fieldName: "improved_shield_block", row: 2, col: 1, maxPoints: 1, prereq: &talentLocation{row: 1, col: 1}},
+					{row: 2, col: 2, maxPoints: 3}, // improvedRevenge
+					{fieldName: "defiance", row: 2, col: 3, maxPoints: 3},
+					{fieldName: "improved_sunder_armor", row: 3, col: 0, maxPoints: 3},
+					{row: 3, col: 1, maxPoints: 3}, // improvedDisarm
+					{row: 3, col: 2, maxPoints: 2}, // improvedTaunt
+					{row: 4, col: 0, maxPoints: 2}, // improvedShieldWall
+					{row: 4, col: 1, maxPoints: 1}, // concussionBlow
+					{row: 4, col: 2, maxPoints: 2}, // improvedShieldBash
+					{fieldName: "shield_mastery", row: 5, col: 0, maxPoints: 3},
+					{fieldName: "one_handed_weapon_specialization", row: 5, col: 2, maxPoints: 5},
+					{fieldName: "improved_defensive_stance", row: 6, col: 0, maxPoints: 3},
+					{fieldName: "shield_slam", row: 6, col: 1, maxPoints: 1, prereq: &talentLocation{row: 4, col: 1}},
+					{fieldName: "focused_rage", row: 6, col: 2, maxPoints: 3},
+					{fieldName: "vitality", row: 7, col: 1, maxPoints: 5},
+					{fieldName: "devastate", row: 8, col: 1, maxPoints: 1},
+				},
+			},
+		},
+	},
+}
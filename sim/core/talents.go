@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const MaxTalentPoints = 61
+
+// Number of points which must be spent in a tree to unlock each row.
+const talentPointsPerRow = 5
+
+const numTalentRows = 9
+
+type talentLocation struct {
+	row int
+	col int
+}
+
+type talentConfig struct {
+	// Name of the field in the class's talents proto. Empty for talents which
+	// the sim doesn't model.
+	fieldName string
+
+	row       int
+	col       int
+	maxPoints int32
+
+	// Location of a talent which must be full before this one can be taken.
+	prereq *talentLocation
+}
+
+func (talent *talentConfig) label() string {
+	if talent.fieldName != "" {
+		return talent.fieldName
+	}
+	return fmt.Sprintf("row %d col %d", talent.row+1, talent.col+1)
+}
+
+type talentTreeConfig struct {
+	name    string
+	talents []talentConfig
+}
+
+func (tree *talentTreeConfig) getTalent(location talentLocation) *talentConfig {
+	for i := range tree.talents {
+		if tree.talents[i].row == location.row && tree.talents[i].col == location.col {
+			return &tree.talents[i]
+		}
+	}
+	return nil
+}
+
+type classTalentTrees struct {
+	// Empty talents proto for the class, e.g. &proto.DruidTalents{}.
+	talentsProto googleProto.Message
+
+	trees [3]talentTreeConfig
+}
+
+// Returns the talents proto within the player's spec options, e.g.
+// *proto.DruidTalents, or nil if the spec has no talents. If mutable is set,
+// missing talents are created so they can be modified.
+func playerTalentsMessage(player *proto.Player, mutable bool) protoreflect.Message {
+	playerMsg := player.ProtoReflect()
+	specField := playerMsg.WhichOneof(playerMsg.Descriptor().Oneofs().ByName("spec"))
+	if specField == nil {
+		return nil
+	}
+	specMsg := playerMsg.Get(specField).Message()
+	talentsField := specMsg.Descriptor().Fields().ByName("talents")
+	if talentsField == nil {
+		return nil
+	}
+	if mutable {
+		return specMsg.Mutable(talentsField).Message()
+	}
+	return specMsg.Get(talentsField).Message()
+}
+
+// Returns the points in a talent proto field, which is either an int32 or a bool.
+func getTalentPoints(talents protoreflect.Message, fieldName string) int32 {
+	field := talents.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
+	value := talents.Get(field)
+	if field.Kind() == protoreflect.BoolKind {
+		if value.Bool() {
+			return 1
+		}
+		return 0
+	}
+	return int32(value.Int())
+}
+
+func setTalentPoints(talents protoreflect.Message, fieldName string, points int32) {
+	field := talents.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
+	if field.Kind() == protoreflect.BoolKind {
+		talents.Set(field, protoreflect.ValueOfBool(points > 0))
+	} else {
+		talents.Set(field, protoreflect.ValueOfInt32(points))
+	}
+}
+
+// Returns the total number of points in the talents modeled by the sim.
+func (classTrees *classTalentTrees) modeledPoints(talents protoreflect.Message) int32 {
+	total := int32(0)
+	for _, tree := range classTrees.trees {
+		for _, talent := range tree.talents {
+			if talent.fieldName != "" {
+				total += getTalentPoints(talents, talent.fieldName)
+			}
+		}
+	}
+	return total
+}
+
+// Checks that the player's talents can be part of a legal talent build: no
+// talent above its max rank, prerequisites full, enough points in each tree to
+// reach every talent, and at most MaxTalentPoints in total.
+//
+// Talents protos often leave out points which don't affect the sim, and
+// talents which the sim doesn't model aren't in the proto at all. So spare
+// ranks of any talent are assumed to hold whatever points are needed to reach
+// deeper rows, and unmodeled prerequisites are assumed to be full.
+func ValidateTalents(player *proto.Player) error {
+	classTrees, ok := talentTreesByClass[player.Class]
+	if !ok {
+		return nil
+	}
+	talents := playerTalentsMessage(player, false)
+	if talents == nil {
+		return nil
+	}
+
+	totalPoints := int32(0)
+	for _, tree := range classTrees.trees {
+		treePoints, err := validateTalentTree(&tree, talents)
+		if err != nil {
+			return err
+		}
+		totalPoints += treePoints
+	}
+
+	if totalPoints > MaxTalentPoints {
+		return fmt.Errorf("Talents need at least %d points, but only %d are available", totalPoints, MaxTalentPoints)
+	}
+	return nil
+}
+
+// Returns an error for the first player in the raid with an illegal talent build.
+func validateRaidTalents(raid *proto.Raid) error {
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			if player == nil {
+				continue
+			}
+			if err := ValidateTalents(player); err != nil {
+				return fmt.Errorf("Invalid talents for %s: %s", player.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validates a single tree, returning the least number of points it needs.
+func validateTalentTree(tree *talentTreeConfig, talents protoreflect.Message) (int32, error) {
+	var rowPoints [numTalentRows]int32
+	// Spare ranks in each row, which can hold filler points.
+	var fillerCapacity [numTalentRows]int32
+
+	for _, talent := range tree.talents {
+		if talent.fieldName == "" {
+			fillerCapacity[talent.row] += talent.maxPoints
+			continue
+		}
+		points := getTalentPoints(talents, talent.fieldName)
+		if points < 0 || points > talent.maxPoints {
+			return 0, fmt.Errorf("%s talent %s has %d points, max is %d", tree.name, talent.fieldName, points, talent.maxPoints)
+		}
+		rowPoints[talent.row] += points
+		fillerCapacity[talent.row] += talent.maxPoints - points
+	}
+
+	// Prerequisites must be full. Unmodeled prerequisites are filled with filler points.
+	filledPrereqs := make(map[talentLocation]bool)
+	for _, talent := range tree.talents {
+		if talent.fieldName == "" || talent.prereq == nil || getTalentPoints(talents, talent.fieldName) == 0 {
+			continue
+		}
+		prereq := tree.getTalent(*talent.prereq)
+		if prereq.fieldName != "" {
+			if getTalentPoints(talents, prereq.fieldName) != prereq.maxPoints {
+				return 0, fmt.Errorf("%s talent %s requires %s to be full", tree.name, talent.fieldName, prereq.label())
+			}
+		} else if !filledPrereqs[*talent.prereq] {
+			filledPrereqs[*talent.prereq] = true
+			rowPoints[prereq.row] += prereq.maxPoints
+			fillerCapacity[prereq.row] -= prereq.maxPoints
+		}
+	}
+
+	deepestRow := -1
+	for row, points := range rowPoints {
+		if points > 0 {
+			deepestRow = row
+		}
+	}
+
+	// Add filler points as high up the tree as possible, until every row with
+	// points is reachable.
+	pointsAbove := int32(0)
+	for row := 0; row <= deepestRow; row++ {
+		required := int32(row * talentPointsPerRow)
+		for fillerRow := 0; fillerRow < row && pointsAbove < required; fillerRow++ {
+			filler := MinInt32(fillerCapacity[fillerRow], required-pointsAbove)
+			fillerCapacity[fillerRow] -= filler
+			rowPoints[fillerRow] += filler
+			pointsAbove += filler
+		}
+		if rowPoints[row] > 0 && pointsAbove < required {
+			return 0, fmt.Errorf("%s talents in row %d need %d points in the rows above, but at most %d can be spent there", tree.name, row+1, required, pointsAbove)
+		}
+		pointsAbove += rowPoints[row]
+	}
+
+	return pointsAbove, nil
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestTalentTreesMatchProtos(t *testing.T) {
+	for class, classTrees := range talentTreesByClass {
+		fields := classTrees.talentsProto.ProtoReflect().Descriptor().Fields()
+		seen := make(map[string]bool)
+		for _, tree := range classTrees.trees {
+			for _, talent := range tree.talents {
+				if talent.prereq != nil && tree.getTalent(*talent.prereq) == nil {
+					t.Fatalf("%s %s talent at row %d col %d has a missing prereq", class, tree.name, talent.row, talent.col)
+				}
+				if talent.fieldName == "" {
+					continue
+				}
+				field := fields.ByName(protoreflect.Name(talent.fieldName))
+				if field == nil {
+					t.Fatalf("%s talents proto has no field %s", class, talent.fieldName)
+				}
+				if field.Kind() == protoreflect.BoolKind && talent.maxPoints != 1 {
+					t.Fatalf("%s talent %s is a bool but has %d max points", class, talent.fieldName, talent.maxPoints)
+				}
+				seen[talent.fieldName] = true
+			}
+		}
+		for i := 0; i < fields.Len(); i++ {
+			if name := string(fields.Get(i).Name()); !seen[name] {
+				t.Fatalf("%s talent %s is missing from the talent trees", class, name)
+			}
+		}
+	}
+}
+
+func TestValidateTalents(t *testing.T) {
+	newPlayer := func(talents *proto.ShamanTalents) *proto.Player {
+		return &proto.Player{
+			Class: proto.Class_ClassShaman,
+			Spec: &proto.Player_ElementalShaman{
+				ElementalShaman: &proto.ElementalShaman{
+					Talents: talents,
+				},
+			},
+		}
+	}
+
+	legal := []*proto.ShamanTalents{
+		// Points outside the proto are assumed to fill the rows above.
+		{ElementalFury: true, ElementalMastery: true, LightningOverload: 5},
+		// 41 in one tree and 20 in another.
+		{LightningOverload: 5, TotemOfWrath: true, ShamanisticFocus: true},
+	}
+	for _, talents := range legal {
+		if err := ValidateTalents(newPlayer(talents)); err != nil {
+			t.Fatalf("Expected %v to be legal, got: %s", talents, err)
+		}
+	}
+
+	illegal := []*proto.ShamanTalents{
+		{Convection: 6},
+		// Stormstrike requires Elemental Weapons.
+		{Stormstrike: true},
+		// Only 61 points available.
+		{LightningOverload: 5, TotemOfWrath: true, ShamanisticRage: true},
+	}
+	for _, talents := range illegal {
+		if err := ValidateTalents(newPlayer(talents)); err == nil {
+			t.Fatalf("Expected %v to be illegal", talents)
+		}
+	}
+
+	// ComputeStats rejects illegal builds.
+	result := ComputeStats(&proto.ComputeStatsRequest{
+		Raid: SinglePlayerRaidProto(newPlayer(&proto.ShamanTalents{Convection: 6}), &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+	})
+	if result.ErrorMsg == "" {
+		t.Fatalf("Expected ComputeStats to reject an illegal build")
+	}
+
+	// So do the sims and optimizers, without panicking.
+	illegalPlayer := newPlayer(&proto.ShamanTalents{Convection: 6})
+	raid := SinglePlayerRaidProto(illegalPlayer, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	encounter := MakeSingleTargetEncounter(0)
+	simOptions := &proto.SimOptions{Iterations: 1, IsTest: true}
+
+	rsr := &proto.RaidSimRequest{Raid: raid, Encounter: encounter, SimOptions: simOptions}
+	if result := RunRaidSim(rsr); result.ErrorMsg == "" {
+		t.Fatalf("Expected RunRaidSim to reject an illegal build")
+	}
+	progress := make(chan *proto.ProgressMetrics, 10)
+	RunRaidSimAsync(context.Background(), rsr, progress)
+	if final := waitForFinalProgress(progress); final.FinalRaidResult.ErrorMsg == "" {
+		t.Fatalf("Expected RunRaidSimAsync to reject an illegal build")
+	}
+
+	swr := &proto.StatWeightsRequest{Player: illegalPlayer, Encounter: encounter, SimOptions: simOptions}
+	if result := StatWeights(swr); result.ErrorMsg == "" {
+		t.Fatalf("Expected StatWeights to reject an illegal build")
+	}
+	progress = make(chan *proto.ProgressMetrics, 10)
+	StatWeightsAsync(context.Background(), swr, progress)
+	if final := waitForFinalProgress(progress); final.FinalWeightResult.ErrorMsg == "" {
+		t.Fatalf("Expected StatWeightsAsync to reject an illegal build")
+	}
+
+	copt := &proto.CooldownOptimizeRequest{Player: illegalPlayer, Encounter: encounter, SimOptions: simOptions}
+	if result := CooldownOptimize(copt); result.ErrorMsg == "" {
+		t.Fatalf("Expected CooldownOptimize to reject an illegal build")
+	}
+}
+
+// Returns the final progress report, which has the result.
+func waitForFinalProgress(progress chan *proto.ProgressMetrics) *proto.ProgressMetrics {
+	for metrics := range progress {
+		if metrics.FinalRaidResult != nil || metrics.FinalWeightResult != nil {
+			return metrics
+		}
+	}
+	return nil
+}
@@ -13,6 +13,20 @@ type Encounter struct {
 	DurationVariation  time.Duration
 	executePhaseBegins time.Duration
 	Targets            []*Target
+
+	phases    []*encounterPhase
+	movements []*movementEvent
+
+	// Whether players die when their health reaches 0.
+	PlayerDeaths bool
+
+	// Whether iterations end when all targets have died, rather than after a
+	// fixed duration. See proto.Encounter.UseHealth.
+	UseHealth bool
+
+	// Fight length for each iteration, only used when UseHealth is set.
+	killTimeMetrics    DistributionMetrics
+	numKillTimeSamples int32
 }
 
 func NewEncounter(options proto.Encounter) Encounter {
@@ -21,6 +35,8 @@ func NewEncounter(options proto.Encounter) Encounter {
 		DurationVariation:  DurationFromSeconds(options.DurationVariation),
 		executePhaseBegins: DurationFromSeconds(options.Duration * (1 - options.ExecuteProportion)),
 		Targets:            []*Target{},
+		PlayerDeaths:       options.PlayerDeaths,
+		killTimeMetrics:    NewDistributionMetrics(),
 	}
 
 	for targetIndex, targetOptions := range options.Targets {
@@ -33,6 +49,25 @@ func NewEncounter(options proto.Encounter) Encounter {
 		encounter.Targets = append(encounter.Targets, NewTarget(proto.Target{}, 0))
 	}
 
+	if options.UseHealth {
+		for _, target := range encounter.Targets {
+			if target.MaxHealth() > 0 {
+				encounter.UseHealth = true
+			}
+		}
+	}
+	if encounter.UseHealth {
+		// Execute phase is triggered by target health instead.
+		encounter.executePhaseBegins = NeverExpires
+	}
+
+	for phaseIndex, phaseOptions := range options.Phases {
+		encounter.phases = append(encounter.phases, encounter.newPhase(phaseIndex, phaseOptions))
+	}
+	for _, movementOptions := range options.Movements {
+		encounter.movements = append(encounter.movements, newMovementEvent(movementOptions))
+	}
+
 	return encounter
 }
 
@@ -60,6 +95,20 @@ func (encounter *Encounter) GetMetricsProto(numIterations int32) *proto.Encounte
 // Target is an enemy/boss that can be the target of player attacks/spells.
 type Target struct {
 	Unit
+
+	abilities []*targetAbility
+
+	// Whether this target is in the fight, and for how long it has been in
+	// the fight during the current iteration.
+	active      bool
+	activeSince time.Duration
+	activeTime  time.Duration
+
+	// Set for targets which are spawned by an encounter phase, so they start
+	// each iteration despawned.
+	spawnedByPhase bool
+
+	immuneAura *Aura
 }
 
 func NewTarget(options proto.Target, targetIndex int32) *Target {
@@ -142,7 +191,21 @@ func (target *Target) init(sim *Simulation) {
 
 func (target *Target) Reset(sim *Simulation) {
 	target.Unit.reset(sim, nil)
-	//target.SetGCDTimer(sim, 0)
+
+	if len(target.abilities) > 0 {
+		for _, ability := range target.abilities {
+			ability.Spell.CD.Set(ability.initialCooldown)
+		}
+		target.SetGCDTimer(sim, 0)
+	}
+
+	target.active = !target.spawnedByPhase
+	target.activeSince = 0
+	target.activeTime = 0
+	if !target.active {
+		target.stopActions(sim)
+	}
+	target.updateImmunity()
 }
 
 func (target *Target) Advance(sim *Simulation, elapsedTime time.Duration) {
@@ -165,6 +228,7 @@ func (target *Target) GetMetricsProto(numIterations int32) *proto.UnitMetrics {
 	metrics := target.Metrics.ToProto(numIterations)
 	metrics.Name = target.Label
 	metrics.Auras = target.auraTracker.GetMetricsProto(numIterations)
+	metrics.Timeline = target.Metrics.timeline.ToProto(target.auraTracker.auras)
 	return metrics
 }
 
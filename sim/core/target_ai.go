@@ -2,6 +2,7 @@ package core
 
 import (
 	"log"
+	"math"
 	"time"
 
 	"github.com/wowsims/tbc/sim/core/proto"
@@ -12,11 +13,11 @@ type TargetAI struct {
 }
 
 func (target *Target) initialize(config *proto.Target) {
-	if config == nil || target.CurrentTarget == nil {
+	if config == nil {
 		return
 	}
 
-	if config.SwingSpeed > 0 {
+	if config.SwingSpeed > 0 && target.CurrentTarget != nil {
 		aaOptions := AutoAttackOptions{
 			MainHand: Weapon{
 				BaseDamageMin:  config.MinBaseDamage,
@@ -36,11 +37,231 @@ func (target *Target) initialize(config *proto.Target) {
 		target.EnableAutoAttacks(target, aaOptions)
 	}
 
-	//target.gcdAction = &PendingAction{
-	//	Priority: ActionPriorityGCD,
-	//	OnAction: func(sim *Simulation) {
-	//	},
-	//}
+	for _, abilityConfig := range config.Abilities {
+		target.abilities = append(target.abilities, target.newAbility(abilityConfig))
+	}
+
+	if len(target.abilities) > 0 {
+		target.gcdAction = &PendingAction{
+			Priority: ActionPriorityGCD,
+			OnAction: target.useAbilities,
+		}
+	}
+}
+
+// A scripted ability, as configured by a proto.TargetAbility.
+type targetAbility struct {
+	Spell *Spell
+
+	targeting       proto.TargetAbility_Targeting
+	initialCooldown time.Duration
+
+	// Players who can be hit by this ability.
+	players []*Unit
+}
+
+func (target *Target) newAbility(config *proto.TargetAbility) *targetAbility {
+	var players []*Unit
+	if config.Targeting == proto.TargetAbility_TargetingTankGroupSplit {
+		players = target.tankGroup()
+	} else {
+		for _, unit := range target.Env.Raid.AllUnits {
+			if unit.Type == PlayerUnit {
+				players = append(players, unit)
+			}
+		}
+	}
+
+	ability := &targetAbility{
+		targeting:       config.Targeting,
+		initialCooldown: DurationFromSeconds(config.InitialCooldown),
+		players:         players,
+	}
+
+	spellSchool := SpellSchoolFromProto(config.School)
+	baseEffect := SpellEffect{
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		BaseDamage:       BaseDamageConfigRoll(config.MinDamage, config.MaxDamage),
+	}
+	if spellSchool == SpellSchoolPhysical {
+		baseEffect.ProcMask = ProcMaskMeleeMHSpecial
+		baseEffect.OutcomeApplier = target.OutcomeFuncEnemyMeleeSpecial()
+	} else {
+		baseEffect.ProcMask = ProcMaskSpellDamage
+		baseEffect.OutcomeApplier = target.OutcomeFuncMagicHit()
+	}
+
+	if config.Debuff != nil && len(players) > 0 {
+		debuffs := make([]*Aura, players[len(players)-1].Index+1)
+		for _, player := range players {
+			debuffs[player.Index] = target.abilityDebuffAura(player, config)
+		}
+		baseEffect.OnSpellHitDealt = func(sim *Simulation, spell *Spell, spellEffect *SpellEffect) {
+			if !spellEffect.Landed() {
+				return
+			}
+			debuff := debuffs[spellEffect.Target.Index]
+			debuff.Activate(sim)
+			debuff.AddStack(sim)
+		}
+	}
+
+	var applyEffects ApplySpellEffects
+	if config.Targeting == proto.TargetAbility_TargetingAllRaidMembers && len(players) > 0 {
+		effects := make([]SpellEffect, len(players))
+		for i, player := range players {
+			effects[i] = baseEffect
+			effects[i].Target = player
+		}
+		applyEffects = ApplyEffectFuncDamageMultiple(effects)
+	} else if config.Targeting == proto.TargetAbility_TargetingTankGroupSplit && len(players) > 0 {
+		effects := make([]SpellEffect, len(players))
+		for i, player := range players {
+			effects[i] = baseEffect
+			effects[i].Target = player
+		}
+		applyEffects = applyEffectFuncSplitDamage(effects)
+	} else {
+		applyEffects = ApplyEffectFuncDirectDamage(baseEffect)
+	}
+
+	ability.Spell = target.RegisterSpell(SpellConfig{
+		ActionID:    ActionID{SpellID: config.SpellId},
+		SpellSchool: spellSchool,
+
+		Cast: CastConfig{
+			DefaultCast: Cast{
+				GCD:      GCDDefault,
+				CastTime: DurationFromSeconds(config.CastTime),
+			},
+			CD: Cooldown{
+				Timer:    target.NewTimer(),
+				Duration: DurationFromSeconds(config.Cooldown),
+			},
+		},
+
+		ApplyEffects: applyEffects,
+	})
+
+	return ability
+}
+
+// Returns the players in the party of the player tanking the target.
+func (target *Target) tankGroup() []*Unit {
+	if target.CurrentTarget == nil {
+		return nil
+	}
+	for _, party := range target.Env.Raid.Parties {
+		var players []*Unit
+		inParty := false
+		for _, player := range party.Players {
+			unit := &player.GetCharacter().Unit
+			players = append(players, unit)
+			if unit == target.CurrentTarget {
+				inParty = true
+			}
+		}
+		if inParty {
+			return players
+		}
+	}
+	return nil
+}
+
+// Like ApplyEffectFuncDamageMultiple, but the base damage is rolled once and
+// split evenly between the targets.
+func applyEffectFuncSplitDamage(baseEffects []SpellEffect) ApplySpellEffects {
+	for _, effect := range baseEffects {
+		effect.Validate()
+	}
+
+	return func(sim *Simulation, _ *Unit, spell *Spell) {
+		baseDamage := baseEffects[0].calculateBaseDamage(sim, spell) / float64(len(baseEffects))
+		for i := range baseEffects {
+			effect := &baseEffects[i]
+			effect.init(sim, spell)
+			effect.Damage = baseDamage * effect.DamageMultiplier
+			attackTable := spell.Unit.AttackTables[effect.Target.Index]
+			effect.calcDamageSingle(sim, spell, attackTable)
+		}
+		for i := range baseEffects {
+			effect := &baseEffects[i]
+			effect.finalize(sim, spell)
+		}
+	}
+}
+
+// Debuff placed on a player by a target ability, which increases damage taken.
+func (target *Target) abilityDebuffAura(player *Unit, config *proto.TargetAbility) *Aura {
+	debuffConfig := config.Debuff
+
+	actionID := ActionID{SpellID: debuffConfig.SpellId}
+	if debuffConfig.SpellId == 0 {
+		actionID = ActionID{SpellID: config.SpellId}
+	}
+
+	maxStacks := MaxInt32(1, debuffConfig.MaxStacks)
+	multiplier := debuffConfig.DamageTakenMultiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	return player.RegisterAura(Aura{
+		Label:     config.Name + "-" + target.Label,
+		ActionID:  actionID,
+		Duration:  DurationFromSeconds(debuffConfig.Duration),
+		MaxStacks: maxStacks,
+		OnStacksChange: func(aura *Aura, sim *Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.DamageTakenMultiplier *= math.Pow(multiplier, float64(newStacks-oldStacks))
+		},
+	})
+}
+
+// Chooses the player hit by an ability. Returns nil if there is none.
+func (ability *targetAbility) chooseTarget(sim *Simulation, target *Target) *Unit {
+	switch ability.targeting {
+	case proto.TargetAbility_TargetingRandomRaidMember:
+		if len(ability.players) == 0 {
+			return nil
+		}
+		idx := int(sim.RandomFloat("Target Ability Target") * float64(len(ability.players)))
+		return ability.players[idx]
+	case proto.TargetAbility_TargetingAllRaidMembers, proto.TargetAbility_TargetingTankGroupSplit:
+		// The effects already hold every player hit, this is only used for metrics.
+		if len(ability.players) == 0 {
+			return nil
+		}
+		return ability.players[0]
+	default:
+		return target.CurrentTarget
+	}
+}
+
+// Invoked whenever the target's GCD is ready. Uses the first ready ability, or
+// waits until one comes off cooldown.
+func (target *Target) useAbilities(sim *Simulation) {
+	nextReadyAt := NeverExpires
+	for _, ability := range target.abilities {
+		if !ability.Spell.IsReady(sim) {
+			nextReadyAt = MinDuration(nextReadyAt, ability.Spell.ReadyAt())
+			continue
+		}
+
+		victim := ability.chooseTarget(sim, target)
+		if victim == nil {
+			// Try again after a GCD, the target may have a victim by then.
+			nextReadyAt = MinDuration(nextReadyAt, sim.CurrentTime+GCDDefault)
+			continue
+		}
+
+		ability.Spell.Cast(sim, victim)
+		return
+	}
+
+	if nextReadyAt != NeverExpires {
+		target.WaitUntil(sim, nextReadyAt)
+	}
 }
 
 // Empty Agent interface functions.
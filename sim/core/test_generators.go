@@ -73,6 +73,7 @@ type SettingsCombos struct {
 	Buffs       []BuffsCombo
 	Encounters  []EncounterCombo
 	SimOptions  *proto.SimOptions
+	Tanks       []*proto.RaidTarget
 }
 
 func (combos *SettingsCombos) NumTests() int {
@@ -137,6 +138,7 @@ func (combos *SettingsCombos) GetTest(testIdx int) (string, *proto.ComputeStatsR
 		Encounter:  encounterCombo.Encounter,
 		SimOptions: combos.SimOptions,
 	}
+	rsr.Raid.Tanks = combos.Tanks
 
 	return strings.Join(testNameParts, "-"), nil, nil, rsr
 }
@@ -284,6 +286,7 @@ type ItemsTestGenerator struct {
 	Debuffs    *proto.Debuffs
 	Encounter  *proto.Encounter
 	SimOptions *proto.SimOptions
+	Tanks      []*proto.RaidTarget
 
 	// Some fields are populated automatically.
 	ItemFilter ItemFilter
@@ -371,6 +374,7 @@ func (generator *ItemsTestGenerator) GetTest(testIdx int) (string, *proto.Comput
 		Encounter:  generator.Encounter,
 		SimOptions: generator.SimOptions,
 	}
+	rsr.Raid.Tanks = generator.Tanks
 
 	return label, nil, nil, rsr
 }
@@ -419,6 +423,8 @@ type CharacterSuiteConfig struct {
 	Consumes    *proto.Consumes
 	Debuffs     *proto.Debuffs
 
+	// Whether the player tanks the target in every test, which also gives
+	// healers damage to heal.
 	IsTank          bool
 	InFrontOfTarget bool
 
@@ -487,6 +493,7 @@ func FullCharacterTestSuiteGenerator(config CharacterSuiteConfig) TestGenerator
 					},
 					Encounters: MakeDefaultEncounterCombos(config.Debuffs),
 					SimOptions: DefaultSimTestOptions,
+					Tanks:      defaultRaid.Tanks,
 				},
 			},
 			SubGenerator{
@@ -498,6 +505,7 @@ func FullCharacterTestSuiteGenerator(config CharacterSuiteConfig) TestGenerator
 					Debuffs:    config.Debuffs,
 					Encounter:  MakeSingleTargetEncounter(0),
 					SimOptions: DefaultSimTestOptions,
+					Tanks:      defaultRaid.Tanks,
 					ItemFilter: config.ItemFilter,
 				},
 			},
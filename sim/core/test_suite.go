@@ -32,6 +32,9 @@ func (testSuite *IndividualTestSuite) TestCharacterStats(testName string, csr *p
 	testSuite.testNames = append(testSuite.testNames, testName)
 
 	result := ComputeStats(csr)
+	if result.ErrorMsg != "" {
+		panic(result.ErrorMsg)
+	}
 	finalStats := stats.FromFloatArray(result.RaidStats.Parties[0].Players[0].FinalStats)
 
 	testSuite.testResults.CharacterStatsResults[testName] = &proto.CharacterStatsTestResult{
@@ -59,6 +62,7 @@ func (testSuite *IndividualTestSuite) TestDPS(testName string, rsr *proto.RaidSi
 		Dps:  result.RaidMetrics.Dps.Avg,
 		Tps:  result.RaidMetrics.Parties[0].Players[0].Threat.Avg,
 		Dtps: result.RaidMetrics.Parties[0].Players[0].Dtps.Avg,
+		Hps:  result.RaidMetrics.Parties[0].Players[0].Hps.Avg,
 	}
 }
 
@@ -188,6 +192,10 @@ func RunTestSuite(t *testing.T, suiteName string, generator TestGenerator) {
 							t.Logf("DTPS expected %0.03f but was %0.03f!.", expectedDpsResult.Dtps, actualDpsResult.Dtps)
 							t.Fail()
 						}
+						if actualDpsResult.Hps < expectedDpsResult.Hps-tolerance || actualDpsResult.Hps > expectedDpsResult.Hps+tolerance {
+							t.Logf("HPS expected %0.03f but was %0.03f!.", expectedDpsResult.Hps, actualDpsResult.Hps)
+							t.Fail()
+						}
 					} else {
 						t.Logf("Unexpected test %s with %0.03f DPS!", fullTestName, actualDpsResult.Dps)
 						t.Fail()
@@ -109,6 +109,9 @@ func CharacterStatsTest(label string, t *testing.T, raid *proto.Raid, expectedSt
 	}
 
 	result := ComputeStats(csr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("%s failed: %s", label, result.ErrorMsg)
+	}
 	finalStats := stats.FromFloatArray(result.RaidStats.Parties[0].Players[0].FinalStats)
 
 	const tolerance = 0.5
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// A trinket which is simmed unequipped, see SimOptions.TrinketContributions.
+type trinketContributionSim struct {
+	partyIdx  int
+	playerIdx int
+	slot      proto.ItemSlot
+	itemID    int32
+}
+
+// Returns a sim for each trinket equipped by a player in the raid.
+func findTrinketContributionSims(raid *proto.Raid) []trinketContributionSim {
+	var trinketSims []trinketContributionSim
+	for partyIdx, party := range raid.Parties {
+		for playerIdx, player := range party.Players {
+			if player == nil || player.Equipment == nil {
+				continue
+			}
+			for _, slot := range []proto.ItemSlot{proto.ItemSlot_ItemSlotTrinket1, proto.ItemSlot_ItemSlotTrinket2} {
+				if int(slot) >= len(player.Equipment.Items) {
+					continue
+				}
+				itemSpec := player.Equipment.Items[slot]
+				if itemSpec == nil || itemSpec.Id == 0 {
+					continue
+				}
+				trinketSims = append(trinketSims, trinketContributionSim{
+					partyIdx:  partyIdx,
+					playerIdx: playerIdx,
+					slot:      slot,
+					itemID:    itemSpec.Id,
+				})
+			}
+		}
+	}
+	return trinketSims
+}
+
+// Runs the sim as usual, then once more for each equipped trinket with that
+// trinket unequipped, and adds the DPS each trinket contributes to its
+// player's metrics.
+//
+// All the sims use the same seed, so that most of their random differences
+// cancel out.
+func runSimWithTrinketContributions(ctx context.Context, rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	simOptions := googleProto.Clone(rsr.SimOptions).(*proto.SimOptions)
+	simOptions.TrinketContributions = false
+	if simOptions.RandomSeed == 0 {
+		simOptions.RandomSeed = time.Now().UnixNano()
+	}
+
+	trinketSims := findTrinketContributionSims(rsr.Raid)
+	totalSims := int32(len(trinketSims) + 1)
+	completedSims := int32(0)
+
+	runSim := func(raid *proto.Raid) *proto.RaidSimResult {
+		result := RunSimWithContext(ctx, proto.RaidSimRequest{
+			Raid:       raid,
+			Encounter:  rsr.Encounter,
+			SimOptions: simOptions,
+		}, nil)
+		completedSims++
+		if progress != nil && completedSims < totalSims {
+			progress <- &proto.ProgressMetrics{
+				CompletedSims: completedSims,
+				TotalSims:     totalSims,
+			}
+		}
+		return result
+	}
+
+	result := runSim(rsr.Raid)
+	for _, trinketSim := range trinketSims {
+		if result.ErrorMsg != "" {
+			break
+		}
+
+		raid := googleProto.Clone(rsr.Raid).(*proto.Raid)
+		raid.Parties[trinketSim.partyIdx].Players[trinketSim.playerIdx].Equipment.Items[trinketSim.slot] = &proto.ItemSpec{}
+		trinketResult := runSim(raid)
+		if trinketResult.ErrorMsg != "" {
+			result = trinketResult
+			break
+		}
+
+		playerMetrics := result.RaidMetrics.Parties[trinketSim.partyIdx].Players[trinketSim.playerIdx]
+		withoutTrinketMetrics := trinketResult.RaidMetrics.Parties[trinketSim.partyIdx].Players[trinketSim.playerIdx]
+		playerMetrics.TrinketContributions = append(playerMetrics.TrinketContributions, &proto.TrinketContribution{
+			Slot:   trinketSim.slot,
+			ItemId: trinketSim.itemID,
+			Dps:    playerMetrics.Dps.Avg - withoutTrinketMetrics.Dps.Avg,
+		})
+	}
+
+	if progress != nil {
+		finalProgress := &proto.ProgressMetrics{
+			CompletedSims:   totalSims,
+			TotalSims:       totalSims,
+			FinalRaidResult: result,
+		}
+		if result.RaidMetrics != nil {
+			finalProgress.Dps = result.RaidMetrics.Dps.Avg
+		}
+		progress <- finalProgress
+	}
+
+	return result
+}
@@ -61,6 +61,7 @@ type Unit struct {
 
 	PseudoStats stats.PseudoStats
 
+	healthBar
 	rageBar
 	energyBar
 
@@ -89,6 +90,20 @@ type Unit struct {
 	gcdAction      *PendingAction
 	hardcastAction *PendingAction
 
+	// The end of the current channel, and how to stop its effects if it is
+	// interrupted.
+	channelEndsAt    time.Duration
+	channelInterrupt func(*Simulation)
+
+	// Periodic actions performed by this unit in the current iteration, which
+	// are cancelled if it dies.
+	periodicActions []*PendingAction
+
+	// Encounter movement events which apply to this unit, and the time at which
+	// the current movement ends.
+	movements   []*movementEvent
+	movingUntil time.Duration
+
 	// Fields related to waiting for certain events to happen.
 	waitingForMana float64
 	waitStartTime  time.Duration
@@ -316,6 +331,12 @@ func (unit *Unit) finalize() {
 	}
 
 	unit.updateCastSpeed()
+	unit.healthBar = healthBar{
+		unit:         unit,
+		canDie:       unit.Type == PlayerUnit && unit.Env.Encounter.PlayerDeaths || unit.Type == EnemyUnit && unit.Env.Encounter.UseHealth && unit.MaxHealth() > 0,
+		onHealthLost: unit.onHealthLost,
+		onDeath:      unit.onDeath,
+	}
 
 	// All stats added up to this point are part of the 'initial' stats.
 	unit.initialStats = unit.stats
@@ -329,6 +350,9 @@ func (unit *Unit) finalize() {
 
 func (unit *Unit) init(sim *Simulation) {
 	unit.auraTracker.init(sim)
+	if sim.Options.TimelineBucketSize > 0 {
+		unit.Metrics.timeline.bucketSize = DurationFromSeconds(sim.Options.TimelineBucketSize)
+	}
 }
 
 func (unit *Unit) reset(sim *Simulation, agent Agent) {
@@ -342,10 +366,15 @@ func (unit *Unit) reset(sim *Simulation, agent Agent) {
 
 	unit.UpdateManaRegenRates()
 
+	unit.healthBar.reset(sim)
 	unit.energyBar.reset(sim)
 	unit.rageBar.reset(sim)
 
 	unit.AutoAttacks.reset(sim)
+	unit.movingUntil = 0
+	unit.channelEndsAt = 0
+	unit.channelInterrupt = nil
+	unit.periodicActions = nil
 }
 
 // Advance moves time forward counting down auras, CDs, mana regen, etc
@@ -14,7 +14,7 @@ func (moonkin *BalanceDruid) OnGCDReady(sim *core.Simulation) {
 }
 
 func (moonkin *BalanceDruid) OnManaTick(sim *core.Simulation) {
-	if moonkin.FinishedWaitingForManaAndGCDReady(sim) {
+	if !moonkin.HasAPLRotation() && moonkin.FinishedWaitingForManaAndGCDReady(sim) {
 		moonkin.tryUseGCD(sim)
 	}
 }
@@ -23,11 +23,13 @@ type Druid struct {
 	Hurricane        *core.Spell
 	InsectSwarm      *core.Spell
 	Lacerate         *core.Spell
+	Lifebloom        *core.Spell
 	Mangle           *core.Spell
 	Maul             *core.Spell
 	Moonfire         *core.Spell
 	Powershift       *core.Spell
 	Rebirth          *core.Spell
+	Regrowth         *core.Spell
 	Rip              *core.Spell
 	Shred            *core.Spell
 	Starfire6        *core.Spell
@@ -37,7 +39,9 @@ type Druid struct {
 
 	InsectSwarmDot *core.Dot
 	LacerateDot    *core.Dot
+	LifebloomDot   *core.Dot
 	MoonfireDot    *core.Dot
+	RegrowthDot    *core.Dot
 	RipDot         *core.Dot
 
 	DemoralizingRoarAura *core.Aura
@@ -48,6 +48,9 @@ func NewFeralDruid(character core.Character, options proto.Player) *FeralDruid {
 	cat.HasMHWeaponImbue = true
 
 	cat.EnableEnergyBar(100.0, func(sim *core.Simulation) {
+		if cat.HasAPLRotation() {
+			return
+		}
 		cat.TryUseCooldowns(sim)
 		if cat.GCD.IsReady(sim) {
 			cat.doRotation(sim)
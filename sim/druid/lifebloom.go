@@ -0,0 +1,103 @@
+package druid
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+// Lifebloom stacks up to 3 times on a single heal target. Each stack heals
+// over time, and blooms for a direct heal when the HoT expires naturally.
+func (druid *Druid) RegisterLifebloomSpell(healTarget *core.Unit) {
+	actionID := core.ActionID{SpellID: 33763}
+	baseCost := 220.0
+
+	druid.Lifebloom = druid.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolNature,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost: baseCost,
+				GCD:  core.GCDDefault,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			if druid.LifebloomDot.IsActive() {
+				druid.LifebloomDot.Refresh(sim)
+				druid.LifebloomDot.AddStack(sim)
+				druid.LifebloomDot.TakeSnapshot(sim)
+			} else {
+				druid.LifebloomDot.Apply(sim)
+				druid.LifebloomDot.SetStacks(sim, 1)
+			}
+		},
+	})
+
+	bloomSpell := druid.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID.WithTag(1),
+		SpellSchool: core.SpellSchoolNature,
+		SpellExtras: core.SpellExtrasHealing,
+	})
+	bloomEffect := druid.newHealingSpellEffect(600, 600, 0.342)
+
+	// Deactivating the aura clears its stacks before OnExpire, so keep track of
+	// them for the bloom.
+	bloomStacks := int32(0)
+	dotAura := healTarget.RegisterAura(core.Aura{
+		Label:     "Lifebloom-" + strconv.Itoa(int(druid.Index)),
+		ActionID:  actionID,
+		MaxStacks: 3,
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			if newStacks > 0 {
+				bloomStacks = newStacks
+			}
+		},
+	})
+
+	hotEffect := druid.newHealingSpellEffect(39, 39, 0.518/7*(1+0.04*float64(druid.Talents.EmpoweredRejuvenation)))
+	hotEffect.ProcMask = core.ProcMaskPeriodicHealing
+	hotEffect.IsPeriodic = true
+	hotEffect.BaseDamage = core.MultiplyByStacks(hotEffect.BaseDamage, dotAura)
+	hotEffect.OutcomeApplier = druid.OutcomeFuncTick()
+
+	druid.LifebloomDot = core.NewDot(core.Dot{
+		Spell:         druid.Lifebloom,
+		Aura:          dotAura,
+		NumberOfTicks: 7,
+		TickLength:    time.Second,
+		TickEffects:   core.TickFuncHealingSnapshot(healTarget, hotEffect),
+	})
+
+	onExpire := dotAura.OnExpire
+	dotAura.OnExpire = func(aura *core.Aura, sim *core.Simulation) {
+		onExpire(aura, sim)
+
+		// Only bloom on natural expiration, not when the sim iteration ends.
+		if aura.ExpiresAt() != sim.CurrentTime {
+			return
+		}
+		effect := bloomEffect
+		effect.DamageMultiplier *= float64(bloomStacks)
+		core.ApplyEffectFuncDirectHealing(effect)(sim, healTarget, bloomSpell)
+	}
+}
+
+func (druid *Druid) newHealingSpellEffect(minHealing float64, maxHealing float64, spellCoefficient float64) core.SpellEffect {
+	return core.SpellEffect{
+		ProcMask: core.ProcMaskSpellHealing,
+
+		DamageMultiplier: 1 + 0.02*float64(druid.Talents.GiftOfNature),
+		ThreatMultiplier: 1 - 0.04*float64(druid.Talents.Subtlety),
+
+		BaseDamage:     core.BaseHealingConfigHealing(minHealing, maxHealing, spellCoefficient),
+		OutcomeApplier: druid.OutcomeFuncMagicCrit(1.5),
+	}
+}
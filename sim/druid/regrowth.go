@@ -0,0 +1,55 @@
+package druid
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (druid *Druid) RegisterRegrowthSpell(healTarget *core.Unit) {
+	actionID := core.ActionID{SpellID: 26980}
+	baseCost := 675.0
+
+	directEffect := druid.newHealingSpellEffect(1253, 1394, 0.286)
+	directEffect.BonusSpellCritRating = float64(druid.Talents.ImprovedRegrowth) * 10 * core.SpellCritRatingPerCritChance
+	directEffect.OnSpellHitDealt = func(sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
+		druid.RegrowthDot.Apply(sim)
+	}
+
+	druid.Regrowth = druid.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolNature,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Second * 2,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(directEffect),
+	})
+
+	hotEffect := druid.newHealingSpellEffect(182, 182, 0.7/7*(1+0.04*float64(druid.Talents.EmpoweredRejuvenation)))
+	hotEffect.ProcMask = core.ProcMaskPeriodicHealing
+	hotEffect.IsPeriodic = true
+	hotEffect.OutcomeApplier = druid.OutcomeFuncTick()
+
+	druid.RegrowthDot = core.NewDot(core.Dot{
+		Spell: druid.Regrowth,
+		Aura: healTarget.RegisterAura(core.Aura{
+			Label:    "Regrowth-" + strconv.Itoa(int(druid.Index)),
+			ActionID: actionID,
+		}),
+		NumberOfTicks: 7,
+		TickLength:    time.Second * 3,
+		TickEffects:   core.TickFuncHealingSnapshot(healTarget, hotEffect),
+	})
+}
@@ -0,0 +1,125 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+var StandardTalents = &proto.DruidTalents{
+	StarlightWrath: 5,
+
+	ImprovedMarkOfTheWild: 5,
+	Furor:                 5,
+	Naturalist:            5,
+	GiftOfNature:          5,
+	NaturalShapeshifter:   3,
+	Intensity:             3,
+	Subtlety:              5,
+	OmenOfClarity:         true,
+	NaturesSwiftness:      true,
+	ImprovedRegrowth:      5,
+	LivingSpirit:          3,
+	EmpoweredRejuvenation: 5,
+	NaturalPerfection:     3,
+}
+
+var FullRaidBuffs = &proto.RaidBuffs{
+	ArcaneBrilliance: true,
+	GiftOfTheWild:    proto.TristateEffect_TristateEffectImproved,
+	DivineSpirit:     proto.TristateEffect_TristateEffectImproved,
+}
+var FullPartyBuffs = &proto.PartyBuffs{
+	WrathOfAirTotem: proto.TristateEffect_TristateEffectImproved,
+	ManaSpringTotem: proto.TristateEffect_TristateEffectRegular,
+}
+var FullIndividualBuffs = &proto.IndividualBuffs{
+	BlessingOfKings:  true,
+	BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+}
+
+var FullConsumes = &proto.Consumes{
+	Flask:              proto.Flask_FlaskOfMightyRestoration,
+	Food:               proto.Food_FoodBlackenedBasilisk,
+	DefaultPotion:      proto.Potions_SuperManaPotion,
+	NumStartingPotions: 1,
+	DefaultConjured:    proto.Conjured_ConjuredDarkRune,
+}
+
+var FullDebuffs = &proto.Debuffs{
+	JudgementOfWisdom: true,
+}
+
+var PlayerOptionsLifebloom = &proto.Player_RestorationDruid{
+	RestorationDruid: &proto.RestorationDruid{
+		Talents: StandardTalents,
+		Options: &proto.RestorationDruid_Options{},
+		Rotation: &proto.RestorationDruid_Rotation{
+			LifebloomStacks: 3,
+			UseRegrowth:     true,
+		},
+	},
+}
+
+var PlayerOptionsRegrowth = &proto.Player_RestorationDruid{
+	RestorationDruid: &proto.RestorationDruid{
+		Talents: StandardTalents,
+		Options: &proto.RestorationDruid_Options{},
+		Rotation: &proto.RestorationDruid_Rotation{
+			UseRegrowth: true,
+		},
+	},
+}
+
+var P1Gear = items.EquipmentSpecFromJsonString(`{"items": [
+	{
+		"id": 28803
+	},
+	{
+		"id": 30726
+	},
+	{
+		"id": 28647
+	},
+	{
+		"id": 31329
+	},
+	{
+		"id": 28600
+	},
+	{
+		"id": 29523
+	},
+	{
+		"id": 29506
+	},
+	{
+		"id": 28655
+	},
+	{
+		"id": 28591
+	},
+	{
+		"id": 28752
+	},
+	{
+		"id": 30736
+	},
+	{
+		"id": 28763
+	},
+	{
+		"id": 29376
+	},
+	{
+		"id": 28590
+	},
+	{
+		"id": 28771
+	},
+	{
+		"id": 29274
+	},
+	{
+		"id": 29390
+	}
+]}`)
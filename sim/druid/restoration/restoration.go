@@ -0,0 +1,74 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/common"
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/druid"
+)
+
+func RegisterRestorationDruid() {
+	core.RegisterAgentFactory(
+		proto.Player_RestorationDruid{},
+		proto.Spec_SpecRestorationDruid,
+		func(character core.Character, options proto.Player) core.Agent {
+			return NewRestorationDruid(character, options)
+		},
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_RestorationDruid)
+			if !ok {
+				panic("Invalid spec value for Restoration Druid!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+func NewRestorationDruid(character core.Character, options proto.Player) *RestorationDruid {
+	restoOptions := options.GetRestorationDruid()
+
+	selfBuffs := druid.SelfBuffs{}
+	if restoOptions.Options.InnervateTarget != nil {
+		selfBuffs.InnervateTarget = *restoOptions.Options.InnervateTarget
+	} else {
+		selfBuffs.InnervateTarget.TargetIndex = -1
+	}
+
+	resto := &RestorationDruid{
+		Druid:            druid.New(character, druid.Humanoid, selfBuffs, *restoOptions.Talents),
+		rotation:         *restoOptions.Rotation,
+		healTargetOption: restoOptions.Options.HealTarget,
+		manaTracker:      common.NewManaSpendingRateTracker(),
+	}
+	resto.rotation.LifebloomStacks = core.MinInt32(resto.rotation.LifebloomStacks, 3)
+
+	return resto
+}
+
+type RestorationDruid struct {
+	*druid.Druid
+
+	rotation proto.RestorationDruid_Rotation
+
+	healTargetOption *proto.RaidTarget
+	healTarget       *core.Character
+
+	manaTracker common.ManaSpendingRateTracker
+}
+
+func (resto *RestorationDruid) GetDruid() *druid.Druid {
+	return resto.Druid
+}
+
+func (resto *RestorationDruid) Initialize() {
+	resto.Druid.Initialize()
+
+	resto.healTarget = resto.GetHealTarget(resto.healTargetOption)
+	resto.RegisterLifebloomSpell(&resto.healTarget.Unit)
+	resto.RegisterRegrowthSpell(&resto.healTarget.Unit)
+}
+
+func (resto *RestorationDruid) Reset(sim *core.Simulation) {
+	resto.Druid.Reset(sim)
+	resto.manaTracker.Reset()
+}
@@ -0,0 +1,41 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+)
+
+func (resto *RestorationDruid) OnGCDReady(sim *core.Simulation) {
+	resto.tryUseGCD(sim)
+}
+
+func (resto *RestorationDruid) OnManaTick(sim *core.Simulation) {
+	if !resto.HasAPLRotation() && resto.FinishedWaitingForManaAndGCDReady(sim) {
+		resto.tryUseGCD(sim)
+	}
+}
+
+func (resto *RestorationDruid) tryUseGCD(sim *core.Simulation) {
+	resto.manaTracker.Update(sim, resto.GetCharacter())
+
+	healTarget := &resto.healTarget.Unit
+	lifebloomDot := resto.LifebloomDot
+
+	// Refresh Lifebloom just before it would bloom, so the stacks keep rolling.
+	lifebloomRefreshAt := lifebloomDot.ExpiresAt() - resto.SpellGCD()
+
+	var spell *core.Spell
+	if resto.rotation.LifebloomStacks > 0 && (!lifebloomDot.IsActive() || lifebloomDot.GetStacks() < resto.rotation.LifebloomStacks || sim.CurrentTime >= lifebloomRefreshAt) {
+		spell = resto.Lifebloom
+	} else if resto.rotation.UseRegrowth && !resto.RegrowthDot.IsActive() && resto.manaTracker.ProjectedManaSurplus(sim, resto.GetCharacter()) {
+		spell = resto.Regrowth
+	} else if resto.rotation.LifebloomStacks > 0 {
+		resto.WaitUntil(sim, lifebloomRefreshAt)
+		return
+	} else {
+		spell = resto.Regrowth
+	}
+
+	if success := spell.Cast(sim, healTarget); !success {
+		resto.WaitForMana(sim, spell.CurCast.Cost)
+	}
+}
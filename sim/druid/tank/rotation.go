@@ -13,6 +13,9 @@ func (bear *FeralTankDruid) OnGCDReady(sim *core.Simulation) {
 }
 
 func (bear *FeralTankDruid) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
+	if bear.HasAPLRotation() {
+		return
+	}
 	bear.tryQueueMaul(sim)
 }
 
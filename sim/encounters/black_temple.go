@@ -32,6 +32,29 @@ func registerBlackTemple() {
 			ParryHaste:       true,
 			DualWield:        false,
 			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         39837,
+					Name:            "Impaling Spine",
+					Cooldown:        21,
+					InitialCooldown: 21,
+					School:          proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:       4625,
+					MaxDamage:       5375,
+					Targeting:       proto.TargetAbility_TargetingRandomRaidMember,
+				},
+				{
+					SpellId:         39835,
+					Name:            "Needle Spine",
+					Cooldown:        3,
+					InitialCooldown: 3,
+					School:          proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:       2188,
+					MaxDamage:       2812,
+					Targeting:       proto.TargetAbility_TargetingRandomRaidMember,
+				},
+			},
 		},
 	})
 
@@ -58,6 +81,29 @@ func registerBlackTemple() {
 			ParryHaste:       true,
 			DualWield:        false,
 			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         40126,
+					Name:            "Molten Punch",
+					Cooldown:        20,
+					InitialCooldown: 10,
+					School:          proto.SpellSchool_SpellSchoolFire,
+					MinDamage:       5250,
+					MaxDamage:       6750,
+					Targeting:       proto.TargetAbility_TargetingTank,
+				},
+				{
+					SpellId:         41926,
+					Name:            "Hateful Strike",
+					Cooldown:        5,
+					InitialCooldown: 5,
+					School:          proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:       6175,
+					MaxDamage:       7825,
+					Targeting:       proto.TargetAbility_TargetingTank,
+				},
+			},
 		},
 	})
 
@@ -245,6 +291,29 @@ func registerBlackTemple() {
 			ParryHaste:       false,
 			DualWield:        false,
 			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         40827,
+					Name:            "Sinful Beam",
+					Cooldown:        9,
+					InitialCooldown: 9,
+					School:          proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:       2775,
+					MaxDamage:       3225,
+					Targeting:       proto.TargetAbility_TargetingRandomRaidMember,
+				},
+				{
+					SpellId:         41001,
+					Name:            "Fatal Attraction",
+					Cooldown:        30,
+					InitialCooldown: 20,
+					School:          proto.SpellSchool_SpellSchoolFire,
+					MinDamage:       2500,
+					MaxDamage:       2500,
+					Targeting:       proto.TargetAbility_TargetingRandomRaidMember,
+				},
+			},
 		},
 	})
 
@@ -383,6 +452,29 @@ func registerBlackTemple() {
 			ParryHaste:       true,
 			DualWield:        true,
 			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         40832,
+					Name:            "Flame Crash",
+					Cooldown:        26,
+					InitialCooldown: 26,
+					School:          proto.SpellSchool_SpellSchoolFire,
+					MinDamage:       3238,
+					MaxDamage:       3762,
+					Targeting:       proto.TargetAbility_TargetingTank,
+				},
+				{
+					SpellId:         40904,
+					Name:            "Draw Soul",
+					Cooldown:        32,
+					InitialCooldown: 32,
+					School:          proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:       4163,
+					MaxDamage:       4837,
+					Targeting:       proto.TargetAbility_TargetingTank,
+				},
+			},
 		},
 	})
 
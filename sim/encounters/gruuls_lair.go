@@ -0,0 +1,170 @@
+package encounters
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func registerGruulsLair() {
+	const bossPrefix = "Gruul's Lair"
+
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        18831,
+			Name:      "High King Maulgar",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_260_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       1.4,
+			MinBaseDamage:    8460,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        18832,
+			Name:      "Krosh Firehand",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      720_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    4000,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        18834,
+			Name:      "Olm the Summoner",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 2,
+
+			Stats: stats.Stats{
+				stats.Health:      720_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6600,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        18835,
+			Name:      "Kiggler the Crazed",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 3,
+
+			Stats: stats.Stats{
+				stats.Health:      720_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    4000,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        18836,
+			Name:      "Blindeye the Seer",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 4,
+
+			Stats: stats.Stats{
+				stats.Health:      720_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    4000,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetEncounter("High King Maulgar", []string{
+		bossPrefix + "/High King Maulgar",
+		bossPrefix + "/Krosh Firehand",
+		bossPrefix + "/Olm the Summoner",
+		bossPrefix + "/Kiggler the Crazed",
+		bossPrefix + "/Blindeye the Seer",
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        19044,
+			Name:      "Gruul the Dragonkiller",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeGiant,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      3_250_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    8750,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+}
@@ -0,0 +1,141 @@
+package encounters
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func registerHyjal() {
+	const bossPrefix = "Hyjal Summit"
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17767,
+			Name:      "Rage Winterchill",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeUndead,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_280_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11250,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17808,
+			Name:      "Anetheron",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_280_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11250,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17888,
+			Name:      "Kaz'rogal",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_280_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11250,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17842,
+			Name:      "Azgalor",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_280_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11250,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17968,
+			Name:      "Archimonde",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      5_060_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    12750,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+}
@@ -0,0 +1,326 @@
+package encounters
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func registerKarazhan() {
+	const bossPrefix = "Karazhan"
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        16152,
+			Name:      "Attumen the Huntsman",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      458_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    5813,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        15687,
+			Name:      "Moroes",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_210_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    5513,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        16457,
+			Name:      "Maiden of Virtue",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_210_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6235,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17533,
+			Name:      "Romulo",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      380_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       1.4,
+			MinBaseDamage:    3700,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        true,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17534,
+			Name:      "Julianne",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      380_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    2400,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetEncounter("Romulo and Julianne", []string{
+		bossPrefix + "/Romulo",
+		bossPrefix + "/Julianne",
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17521,
+			Name:      "The Big Bad Wolf",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeBeast,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      850_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6300,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        15691,
+			Name:      "The Curator",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeMechanical,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_200_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    5933,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        15688,
+			Name:      "Terestian Illhoof",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_000_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    4988,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        16524,
+			Name:      "Shade of Aran",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_000_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    3600,
+			CanCrush:         false,
+			ParryHaste:       false,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        15689,
+			Name:      "Netherspite",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_550_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6413,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        15690,
+			Name:      "Prince Malchezaar",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_150_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6803,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17225,
+			Name:      "Nightbane",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDragonkin,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_450_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6125,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+}
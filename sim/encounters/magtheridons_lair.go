@@ -0,0 +1,37 @@
+package encounters
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func registerMagtheridonsLair() {
+	const bossPrefix = "Magtheridon's Lair"
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        17257,
+			Name:      "Magtheridon",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_300_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11250,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+}
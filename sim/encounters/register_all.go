@@ -5,7 +5,14 @@ import (
 )
 
 func init() {
+	registerKarazhan()
+	registerGruulsLair()
+	registerMagtheridonsLair()
+	registerSerpentshrineCavern()
+	registerTempestKeep()
+	registerHyjal()
 	registerBlackTemple()
+	registerZulAman()
 	registerSunwellPlateau()
 }
 
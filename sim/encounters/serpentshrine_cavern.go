@@ -0,0 +1,262 @@
+package encounters
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func registerSerpentshrineCavern() {
+	const bossPrefix = "Serpentshrine Cavern"
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21216,
+			Name:      "Hydross the Unstable",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      4_300_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			// Uses the frost phase, in which melee swings deal frost damage.
+			SpellSchool:      proto.SpellSchool_SpellSchoolFrost,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    8450,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         38235,
+					Name:            "Water Tomb",
+					Cooldown:        7,
+					InitialCooldown: 7,
+					School:          proto.SpellSchool_SpellSchoolFrost,
+					MinDamage:       5550,
+					MaxDamage:       6450,
+					Targeting:       proto.TargetAbility_TargetingRandomRaidMember,
+				},
+			},
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21217,
+			Name:      "The Lurker Below",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeBeast,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      5_060_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    9200,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21215,
+			Name:      "Leotheras the Blind",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeDemon,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      3_790_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    9600,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        true,
+			DualWieldPenalty: false,
+		},
+	})
+
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21214,
+			Name:      "Fathom-Lord Karathress",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      1_011_750,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    11000,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21966,
+			Name:      "Fathom-Guard Sharkkis",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      910_575,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6600,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21965,
+			Name:      "Fathom-Guard Tidalvess",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 2,
+
+			Stats: stats.Stats{
+				stats.Health:      910_575,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    6600,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetTarget(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21964,
+			Name:      "Fathom-Guard Caribdis",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 3,
+
+			Stats: stats.Stats{
+				stats.Health:      910_575,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    4500,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+	core.AddPresetEncounter("Fathom-Lord Karathress", []string{
+		bossPrefix + "/Fathom-Lord Karathress",
+		bossPrefix + "/Fathom-Guard Sharkkis",
+		bossPrefix + "/Fathom-Guard Tidalvess",
+		bossPrefix + "/Fathom-Guard Caribdis",
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21213,
+			Name:      "Morogrim Tidewalker",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      4_047_000,
+				stats.Armor:       7684,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    10400,
+			CanCrush:         true,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+
+	AddSingleTargetBossEncounter(core.PresetTarget{
+		PathPrefix: bossPrefix,
+		Config: proto.Target{
+			Id:        21212,
+			Name:      "Lady Vashj",
+			Level:     73,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      3_800_000,
+				stats.Armor:       6193,
+				stats.AttackPower: 320,
+				stats.BlockValue:  54,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2.0,
+			MinBaseDamage:    7200,
+			CanCrush:         false,
+			ParryHaste:       true,
+			DualWield:        false,
+			DualWieldPenalty: false,
+		},
+	})
+}
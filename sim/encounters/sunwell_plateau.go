@@ -90,6 +90,19 @@ func registerSunwellPlateau() {
 			ParryHaste:       false,
 			DualWield:        true,
 			DualWieldPenalty: false,
+
+			Abilities: []*proto.TargetAbility{
+				{
+					SpellId:         45150,
+					Name:            "Meteor Slash",
+					Cooldown:        12,
+					InitialCooldown: 12,
+					School:          proto.SpellSchool_SpellSchoolFire,
+					MinDamage:       3470,
+					MaxDamage:       4530,
+					Targeting:       proto.TargetAbility_TargetingTankGroupSplit,
+				},
+			},
 		},
 	})
 
@@ -31,7 +31,7 @@ func (hunter *Hunter) OnManaTick(sim *core.Simulation) {
 		hunter.AddMana(sim, manaGain, hunter.AspectOfTheViper.ActionID, false)
 	}
 
-	if hunter.IsWaitingForMana() && hunter.DoneWaitingForMana(sim) {
+	if !hunter.HasAPLRotation() && hunter.IsWaitingForMana() && hunter.DoneWaitingForMana(sim) {
 		hunter.TryKillCommand(sim, hunter.CurrentTarget)
 		if hunter.nextAction == OptionNone && hunter.Hardcast.Expires <= sim.CurrentTime {
 			hunter.rotation(sim, false)
@@ -40,6 +40,9 @@ func (hunter *Hunter) OnManaTick(sim *core.Simulation) {
 }
 
 func (hunter *Hunter) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
+	if hunter.HasAPLRotation() {
+		return
+	}
 	hunter.TryKillCommand(sim, hunter.CurrentTarget)
 	if spell == hunter.AutoAttacks.RangedAuto {
 		hunter.TryUseCooldowns(sim)
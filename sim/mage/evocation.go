@@ -43,13 +43,15 @@ func (mage *Mage) registerEvocationCD() {
 
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			period := spell.CurCast.ChannelTime / time.Duration(numTicks)
-			core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			pa := core.NewPeriodicAction(sim, core.PeriodicActionOptions{
 				Period:   period,
 				NumTicks: int(numTicks),
 				OnAction: func(sim *core.Simulation) {
 					mage.AddMana(sim, manaPerTick, actionID, true)
 				},
 			})
+			sim.AddPendingAction(pa)
+			mage.StartChannel(sim, sim.CurrentTime+spell.CurCast.ChannelTime, pa.Cancel)
 
 			// All MCDs that use the GCD and have a non-zero cast time must call this.
 			mage.UpdateMajorCooldowns()
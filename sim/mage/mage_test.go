@@ -6,6 +6,7 @@ import (
 	_ "github.com/wowsims/tbc/sim/common"
 	"github.com/wowsims/tbc/sim/core"
 	"github.com/wowsims/tbc/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -113,3 +114,50 @@ func TestFrost(t *testing.T) {
 		},
 	}))
 }
+
+func TestFireBlastWhileMoving(t *testing.T) {
+	options := googleProto.Clone(PlayerOptionsFire.Mage).(*proto.Mage)
+	options.Rotation.Fire.WeaveFireBlast = false
+	player := core.WithSpec(&proto.Player{
+		Race:      proto.Race_RaceTroll10,
+		Class:     proto.Class_ClassMage,
+		Equipment: P1FireGear,
+		Consumes:  FullFireConsumes,
+		Buffs:     FullIndividualBuffs,
+	}, &proto.Player_Mage{Mage: options})
+
+	fireBlastCasts := func(movements []*proto.MovementEvent) int32 {
+		rsr := &proto.RaidSimRequest{
+			Raid:      core.SinglePlayerRaidProto(player, FullFirePartyBuffs, FullRaidBuffs, FullDebuffs),
+			Encounter: core.MakeSingleTargetEncounter(0),
+			SimOptions: &proto.SimOptions{
+				Iterations: 1,
+				IsTest:     true,
+			},
+		}
+		rsr.Encounter.Movements = movements
+		result := core.RunRaidSim(rsr)
+		if result.ErrorMsg != "" {
+			t.Fatalf("Sim failed: %s", result.ErrorMsg)
+		}
+
+		casts := int32(0)
+		for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+			if action.Id.GetSpellId() == 27079 {
+				for _, target := range action.Targets {
+					casts += target.Casts
+				}
+			}
+		}
+		return casts
+	}
+
+	if casts := fireBlastCasts(nil); casts != 0 {
+		t.Fatalf("Expected no Fire Blast casts without weaving or movement, got %d", casts)
+	}
+	// Ranged players move for 4s every 20s.
+	movements := []*proto.MovementEvent{{StartTime: 10, Duration: 4, RepeatInterval: 20, Role: proto.MovementEvent_Ranged}}
+	if casts := fireBlastCasts(movements); casts == 0 {
+		t.Fatalf("Expected Fire Blast casts while moving")
+	}
+}
@@ -39,7 +39,7 @@ var FrostTalents = &proto.MageTalents{
 	ElementalPrecision:   3,
 	IceShards:            5,
 	IcyVeins:             true,
-	PiercingIce:          5,
+	PiercingIce:          3,
 	FrostChanneling:      3,
 	ColdSnap:             true,
 	ImprovedConeOfCold:   2,
@@ -67,7 +67,7 @@ var ArcaneTalents = &proto.MageTalents{
 	ElementalPrecision: 3,
 	IceShards:          5,
 	IcyVeins:           true,
-	PiercingIce:        5,
+	PiercingIce:        3,
 	FrostChanneling:    3,
 	ColdSnap:           true,
 }
@@ -12,7 +12,7 @@ func (mage *Mage) OnGCDReady(sim *core.Simulation) {
 }
 
 func (mage *Mage) OnManaTick(sim *core.Simulation) {
-	if mage.FinishedWaitingForManaAndGCDReady(sim) {
+	if !mage.HasAPLRotation() && mage.FinishedWaitingForManaAndGCDReady(sim) {
 		mage.tryUseGCD(sim)
 	}
 }
@@ -160,15 +160,22 @@ func (mage *Mage) doFireRotation(sim *core.Simulation) *core.Spell {
 		return mage.doAoeRotation(sim)
 	}
 
-	if mage.FireRotation.WeaveFireBlast && mage.FireBlast.IsReady(sim) {
+	primarySpell := mage.Scorch
+	if mage.FireRotation.PrimarySpell == proto.Mage_Rotation_FireRotation_Fireball {
+		primarySpell = mage.Fireball
+	}
+
+	// Fire Blast is instant, so use it instead of a cast which movement would
+	// interrupt.
+	if mage.FireBlast.IsReady(sim) && mage.TimeUntilNextMovement(sim) < mage.ApplyCastSpeed(primarySpell.DefaultCast.CastTime) {
 		return mage.FireBlast
 	}
 
-	if mage.FireRotation.PrimarySpell == proto.Mage_Rotation_FireRotation_Fireball {
-		return mage.Fireball
-	} else {
-		return mage.Scorch
+	if mage.FireRotation.WeaveFireBlast && mage.FireBlast.IsReady(sim) {
+		return mage.FireBlast
 	}
+
+	return primarySpell
 }
 
 func (mage *Mage) doFrostRotation(sim *core.Simulation) *core.Spell {
@@ -0,0 +1,43 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (paladin *Paladin) RegisterFlashOfLightSpell() {
+	actionID := core.ActionID{SpellID: 27137}
+	baseCost := 180.0
+
+	paladin.FlashOfLight = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(core.SpellEffect{
+			ProcMask: core.ProcMaskSpellHealing,
+
+			BonusSpellCritRating: float64(paladin.Talents.HolyPower) * core.SpellCritRatingPerCritChance,
+
+			DamageMultiplier: 1 + 0.04*float64(paladin.Talents.HealingLight),
+			ThreatMultiplier: 1,
+
+			BaseDamage:      core.BaseHealingConfigHealing(458, 513, 0.429),
+			OutcomeApplier:  paladin.OutcomeFuncMagicCrit(1.5),
+			OnSpellHitDealt: paladin.illuminationOnHeal(baseCost),
+		}),
+	})
+}
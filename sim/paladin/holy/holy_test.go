@@ -0,0 +1,50 @@
+package holy
+
+import (
+	"testing"
+
+	_ "github.com/wowsims/tbc/sim/common" // imported to get item effects included.
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+func init() {
+	RegisterHolyPaladin()
+}
+
+func TestHoly(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
+		Class: proto.Class_ClassPaladin,
+
+		Race:       proto.Race_RaceBloodElf,
+		OtherRaces: []proto.Race{proto.Race_RaceHuman},
+
+		GearSet: core.GearSetCombo{Label: "P1", GearSet: Phase1Gear},
+
+		SpecOptions: core.SpecOptionsCombo{Label: "Adaptive", SpecOptions: DefaultOptions},
+		OtherSpecOptions: []core.SpecOptionsCombo{
+			core.SpecOptionsCombo{Label: "FlashOfLight", SpecOptions: FlashOfLightOptions},
+		},
+
+		RaidBuffs:   FullRaidBuffs,
+		PartyBuffs:  FullPartyBuffs,
+		PlayerBuffs: FullIndividualBuffs,
+		Consumes:    FullConsumes,
+		Debuffs:     FullDebuffs,
+
+		// Tank the boss so there is damage to heal.
+		IsTank: true,
+
+		ItemFilter: core.ItemFilter{
+			WeaponTypes: []proto.WeaponType{
+				proto.WeaponType_WeaponTypeMace,
+				proto.WeaponType_WeaponTypeShield,
+				proto.WeaponType_WeaponTypeOffHand,
+			},
+			ArmorType: proto.ArmorType_ArmorTypePlate,
+			RangedWeaponTypes: []proto.RangedWeaponType{
+				proto.RangedWeaponType_RangedWeaponTypeLibram,
+			},
+		},
+	}))
+}
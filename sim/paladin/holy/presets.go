@@ -0,0 +1,130 @@
+package holy
+
+import (
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+var defaultHolyTalents = &proto.PaladinTalents{
+	DivineStrength:           5,
+	DivineIntellect:          5,
+	HealingLight:             3,
+	Illumination:             5,
+	ImprovedBlessingOfWisdom: 2,
+	DivineFavor:              true,
+	SanctifiedLight:          3,
+	HolyPower:                5,
+	BlessedLife:              3,
+	HolyGuidance:             5,
+	DivineIllumination:       true,
+
+	Redoubt:         5,
+	Precision:       3,
+	Toughness:       5,
+	BlessingOfKings: true,
+}
+
+var defaultHolyRotation = &proto.HolyPaladin_Rotation{
+	PrimarySpell: proto.HolyPaladin_Rotation_Adaptive,
+}
+
+var defaultHolyOptions = &proto.HolyPaladin_Options{
+	Aura: proto.PaladinAura_DevotionAura,
+}
+
+var DefaultOptions = &proto.Player_HolyPaladin{
+	HolyPaladin: &proto.HolyPaladin{
+		Talents:  defaultHolyTalents,
+		Options:  defaultHolyOptions,
+		Rotation: defaultHolyRotation,
+	},
+}
+
+var FlashOfLightOptions = &proto.Player_HolyPaladin{
+	HolyPaladin: &proto.HolyPaladin{
+		Talents: defaultHolyTalents,
+		Options: defaultHolyOptions,
+		Rotation: &proto.HolyPaladin_Rotation{
+			PrimarySpell: proto.HolyPaladin_Rotation_FlashOfLight,
+		},
+	},
+}
+
+var FullRaidBuffs = &proto.RaidBuffs{
+	ArcaneBrilliance:   true,
+	GiftOfTheWild:      proto.TristateEffect_TristateEffectImproved,
+	PowerWordFortitude: proto.TristateEffect_TristateEffectRegular,
+	DivineSpirit:       proto.TristateEffect_TristateEffectImproved,
+}
+var FullPartyBuffs = &proto.PartyBuffs{
+	MoonkinAura:     proto.TristateEffect_TristateEffectRegular,
+	WrathOfAirTotem: proto.TristateEffect_TristateEffectImproved,
+	ManaSpringTotem: proto.TristateEffect_TristateEffectRegular,
+}
+var FullIndividualBuffs = &proto.IndividualBuffs{
+	BlessingOfKings:  true,
+	BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+}
+
+var FullConsumes = &proto.Consumes{
+	Flask:              proto.Flask_FlaskOfMightyRestoration,
+	Food:               proto.Food_FoodBlackenedBasilisk,
+	DefaultPotion:      proto.Potions_SuperManaPotion,
+	NumStartingPotions: 1,
+	DefaultConjured:    proto.Conjured_ConjuredDarkRune,
+}
+
+var FullDebuffs = &proto.Debuffs{
+	JudgementOfWisdom: true,
+}
+
+var Phase1Gear = items.EquipmentSpecFromJsonString(`{"items": [
+	{
+		"id": 32084
+	},
+	{
+		"id": 30726
+	},
+	{
+		"id": 28666
+	},
+	{
+		"id": 31329
+	},
+	{
+		"id": 28662
+	},
+	{
+		"id": 23539
+	},
+	{
+		"id": 28505
+	},
+	{
+		"id": 28733
+	},
+	{
+		"id": 30299
+	},
+	{
+		"id": 28569
+	},
+	{
+		"id": 30736
+	},
+	{
+		"id": 28763
+	},
+	{
+		"id": 29376
+	},
+	{
+		"id": 28590
+	},
+	{
+		"id": 28771
+	},
+	{
+		"id": 29274
+	}
+]}`)
@@ -0,0 +1,39 @@
+package holy
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+func (holy *HolyPaladin) OnGCDReady(sim *core.Simulation) {
+	holy.tryUseGCD(sim)
+}
+
+func (holy *HolyPaladin) OnManaTick(sim *core.Simulation) {
+	if !holy.HasAPLRotation() && holy.FinishedWaitingForManaAndGCDReady(sim) {
+		holy.tryUseGCD(sim)
+	}
+}
+
+func (holy *HolyPaladin) tryUseGCD(sim *core.Simulation) {
+	var spell *core.Spell
+	switch holy.Rotation.PrimarySpell {
+	case proto.HolyPaladin_Rotation_HolyLight:
+		spell = holy.HolyLight
+	case proto.HolyPaladin_Rotation_FlashOfLight:
+		spell = holy.FlashOfLight
+	default:
+		holy.manaTracker.Update(sim, holy.GetCharacter())
+
+		// Holy Light heals for more per second, but Flash of Light lasts longer.
+		if holy.manaTracker.ProjectedManaSurplus(sim, holy.GetCharacter()) {
+			spell = holy.HolyLight
+		} else {
+			spell = holy.FlashOfLight
+		}
+	}
+
+	if success := spell.Cast(sim, &holy.healTarget.Unit); !success {
+		holy.WaitForMana(sim, spell.CurCast.Cost)
+	}
+}
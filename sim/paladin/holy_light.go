@@ -0,0 +1,43 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (paladin *Paladin) RegisterHolyLightSpell() {
+	actionID := core.ActionID{SpellID: 27136}
+	baseCost := 840.0
+
+	paladin.HolyLight = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(core.SpellEffect{
+			ProcMask: core.ProcMaskSpellHealing,
+
+			BonusSpellCritRating: float64(paladin.Talents.HolyPower+2*paladin.Talents.SanctifiedLight) * core.SpellCritRatingPerCritChance,
+
+			DamageMultiplier: 1 + 0.04*float64(paladin.Talents.HealingLight),
+			ThreatMultiplier: 1,
+
+			BaseDamage:      core.BaseHealingConfigHealing(2196, 2446, 0.714),
+			OutcomeApplier:  paladin.OutcomeFuncMagicCrit(1.5),
+			OnSpellHitDealt: paladin.illuminationOnHeal(baseCost),
+		}),
+	})
+}
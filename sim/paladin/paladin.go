@@ -24,6 +24,8 @@ type Paladin struct {
 	Consecration             *core.Spell
 	CrusaderStrike           *core.Spell
 	Exorcism                 *core.Spell
+	FlashOfLight             *core.Spell
+	HolyLight                *core.Spell
 	HolyShield               *core.Spell
 	JudgementOfBlood         *core.Spell
 	JudgementOfTheCrusader   *core.Spell
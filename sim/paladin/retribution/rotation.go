@@ -12,7 +12,7 @@ func (ret *RetributionPaladin) OnGCDReady(sim *core.Simulation) {
 }
 
 func (ret *RetributionPaladin) OnManaTick(sim *core.Simulation) {
-	if ret.FinishedWaitingForManaAndGCDReady(sim) {
+	if !ret.HasAPLRotation() && ret.FinishedWaitingForManaAndGCDReady(sim) {
 		ret.tryUseGCD(sim)
 	}
 }
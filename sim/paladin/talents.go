@@ -244,3 +244,21 @@ func (paladin *Paladin) applyVengeance() {
 		},
 	})
 }
+
+// Returns an OnSpellHitDealt callback for heals which refunds mana on crits.
+func (paladin *Paladin) illuminationOnHeal(baseCost float64) func(*core.Simulation, *core.Spell, *core.SpellEffect) {
+	if paladin.Talents.Illumination == 0 {
+		return nil
+	}
+
+	actionID := core.ActionID{SpellID: 20215}
+	procChance := 0.2 * float64(paladin.Talents.Illumination)
+	return func(sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
+		if !spellEffect.Outcome.Matches(core.OutcomeCrit) {
+			return
+		}
+		if procChance == 1 || sim.RandomFloat("Illumination") < procChance {
+			paladin.AddMana(sim, baseCost*0.6, actionID, false)
+		}
+	}
+}
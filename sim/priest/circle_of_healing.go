@@ -0,0 +1,40 @@
+package priest
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+// Circle of Healing heals every player in the party of the heal target.
+func (priest *Priest) RegisterCircleOfHealingSpell(party *core.Party) {
+	if !priest.Talents.CircleOfHealing {
+		return
+	}
+
+	baseCost := 450.0
+
+	effects := make([]core.SpellEffect, 0, len(party.Players))
+	for _, partyMember := range party.Players {
+		effect := priest.newHealingSpellEffect(409, 451, 0.214)
+		effect.Target = &partyMember.GetCharacter().Unit
+		effects = append(effects, effect)
+	}
+
+	priest.CircleOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 34866},
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost: baseCost,
+				GCD:  core.GCDDefault,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncHealingMultiple(effects),
+	})
+}
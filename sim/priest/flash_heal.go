@@ -0,0 +1,31 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (priest *Priest) RegisterFlashHealSpell() {
+	baseCost := 470.0
+
+	priest.FlashHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 25235},
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(priest.newHealingSpellEffect(1101, 1279, 0.429+0.02*float64(priest.Talents.EmpoweredHealing))),
+	})
+}
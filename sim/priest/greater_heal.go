@@ -0,0 +1,45 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (priest *Priest) RegisterGreaterHealSpell() {
+	baseCost := 825.0 * (1 - 0.05*float64(priest.Talents.ImprovedHealing))
+
+	priest.GreaterHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 25213},
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond*3000 - time.Millisecond*100*time.Duration(priest.Talents.DivineFury),
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(priest.newHealingSpellEffect(2396, 2784, 0.857+0.04*float64(priest.Talents.EmpoweredHealing))),
+	})
+}
+
+func (priest *Priest) newHealingSpellEffect(minHealing float64, maxHealing float64, spellCoefficient float64) core.SpellEffect {
+	return core.SpellEffect{
+		ProcMask: core.ProcMaskSpellHealing,
+
+		BonusSpellCritRating: float64(priest.Talents.HolySpecialization) * 1 * core.SpellCritRatingPerCritChance,
+
+		DamageMultiplier: 1 + 0.02*float64(priest.Talents.SpiritualHealing),
+		ThreatMultiplier: 1 - 0.04*float64(priest.Talents.SilentResolve),
+
+		BaseDamage:     core.BaseHealingConfigHealing(minHealing, maxHealing, spellCoefficient),
+		OutcomeApplier: priest.OutcomeFuncMagicCrit(1.5),
+	}
+}
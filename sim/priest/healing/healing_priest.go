@@ -0,0 +1,81 @@
+package healing
+
+import (
+	"github.com/wowsims/tbc/sim/common"
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/priest"
+)
+
+func RegisterHealingPriest() {
+	core.RegisterAgentFactory(
+		proto.Player_HealingPriest{},
+		proto.Spec_SpecHealingPriest,
+		func(character core.Character, options proto.Player) core.Agent {
+			return NewHealingPriest(character, options)
+		},
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_HealingPriest)
+			if !ok {
+				panic("Invalid spec value for Healing Priest!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+func NewHealingPriest(character core.Character, options proto.Player) *HealingPriest {
+	healingOptions := options.GetHealingPriest()
+
+	selfBuffs := priest.SelfBuffs{
+		UseShadowfiend: healingOptions.Options.UseShadowfiend,
+	}
+
+	if healingOptions.Options.PowerInfusionTarget != nil {
+		selfBuffs.PowerInfusionTarget = *healingOptions.Options.PowerInfusionTarget
+	} else {
+		selfBuffs.PowerInfusionTarget.TargetIndex = -1
+	}
+
+	basePriest := priest.New(character, selfBuffs, *healingOptions.Talents)
+
+	hpriest := &HealingPriest{
+		Priest:           basePriest,
+		rotation:         *healingOptions.Rotation,
+		healTargetOption: healingOptions.Options.HealTarget,
+		manaTracker:      common.NewManaSpendingRateTracker(),
+	}
+
+	return hpriest
+}
+
+type HealingPriest struct {
+	*priest.Priest
+
+	rotation proto.HealingPriest_Rotation
+
+	healTargetOption *proto.RaidTarget
+	healTarget       *core.Character
+
+	manaTracker common.ManaSpendingRateTracker
+}
+
+func (hpriest *HealingPriest) GetPriest() *priest.Priest {
+	return hpriest.Priest
+}
+
+func (hpriest *HealingPriest) Initialize() {
+	hpriest.Priest.Initialize()
+
+	hpriest.healTarget = hpriest.GetHealTarget(hpriest.healTargetOption)
+	hpriest.RegisterCircleOfHealingSpell(hpriest.healTarget.Party)
+	hpriest.RegisterFlashHealSpell()
+	hpriest.RegisterGreaterHealSpell()
+	hpriest.RegisterPrayerOfMendingSpell()
+	hpriest.RegisterPowerWordShieldSpell(&hpriest.healTarget.Unit)
+}
+
+func (hpriest *HealingPriest) Reset(sim *core.Simulation) {
+	hpriest.Priest.Reset(sim)
+	hpriest.manaTracker.Reset()
+}
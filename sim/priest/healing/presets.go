@@ -0,0 +1,156 @@
+package healing
+
+import (
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+var HolyTalents = &proto.PriestTalents{
+	SilentResolve:              1,
+	ImprovedPowerWordFortitude: 2,
+	InnerFocus:                 true,
+	Meditation:                 3,
+	MentalAgility:              5,
+
+	HealingFocus:       2,
+	ImprovedRenew:      3,
+	HolySpecialization: 5,
+	DivineFury:         5,
+	ImprovedHealing:    3,
+	HealingPrayers:     2,
+	SpiritualHealing:   5,
+	SpiritualGuidance:  5,
+	SurgeOfLight:       2,
+	SpiritOfRedemption: true,
+	EmpoweredHealing:   5,
+	CircleOfHealing:    true,
+}
+
+var DisciplineTalents = &proto.PriestTalents{
+	SilentResolve:              1,
+	ImprovedPowerWordFortitude: 2,
+	InnerFocus:                 true,
+	Meditation:                 3,
+	MentalAgility:              5,
+	MentalStrength:             5,
+	DivineSpirit:               true,
+	ImprovedDivineSpirit:       2,
+	FocusedPower:               2,
+	ForceOfWill:                5,
+	PowerInfusion:              true,
+	Enlightenment:              5,
+	ImprovedPowerWordShield:    3,
+
+	HealingFocus:       2,
+	HolySpecialization: 5,
+	DivineFury:         5,
+	ImprovedHealing:    3,
+}
+
+var FullRaidBuffs = &proto.RaidBuffs{
+	ArcaneBrilliance: true,
+	GiftOfTheWild:    proto.TristateEffect_TristateEffectImproved,
+}
+var FullPartyBuffs = &proto.PartyBuffs{
+	MoonkinAura:     proto.TristateEffect_TristateEffectRegular,
+	WrathOfAirTotem: proto.TristateEffect_TristateEffectImproved,
+	ManaSpringTotem: proto.TristateEffect_TristateEffectRegular,
+}
+var FullIndividualBuffs = &proto.IndividualBuffs{
+	BlessingOfKings:  true,
+	BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+}
+
+var FullConsumes = &proto.Consumes{
+	Flask:              proto.Flask_FlaskOfMightyRestoration,
+	Food:               proto.Food_FoodBlackenedBasilisk,
+	DefaultPotion:      proto.Potions_SuperManaPotion,
+	NumStartingPotions: 1,
+	DefaultConjured:    proto.Conjured_ConjuredDarkRune,
+}
+
+var FullDebuffs = &proto.Debuffs{
+	JudgementOfWisdom: true,
+}
+
+var PlayerOptionsHoly = &proto.Player_HealingPriest{
+	HealingPriest: &proto.HealingPriest{
+		Talents: HolyTalents,
+		Options: &proto.HealingPriest_Options{
+			UseShadowfiend: true,
+		},
+		Rotation: &proto.HealingPriest_Rotation{
+			PrimarySpell:       proto.HealingPriest_Rotation_Adaptive,
+			UsePrayerOfMending: true,
+			UseCircleOfHealing: true,
+		},
+	},
+}
+
+var PlayerOptionsDiscipline = &proto.Player_HealingPriest{
+	HealingPriest: &proto.HealingPriest{
+		Talents: DisciplineTalents,
+		Options: &proto.HealingPriest_Options{
+			UseShadowfiend: true,
+		},
+		Rotation: &proto.HealingPriest_Rotation{
+			PrimarySpell:       proto.HealingPriest_Rotation_GreaterHeal,
+			UsePrayerOfMending: true,
+			UsePowerWordShield: true,
+		},
+	},
+}
+
+var P1Gear = items.EquipmentSpecFromJsonString(`{"items": [
+	{
+		"id": 28756
+	},
+	{
+		"id": 30726
+	},
+	{
+		"id": 21874
+	},
+	{
+		"id": 31329
+	},
+	{
+		"id": 21875
+	},
+	{
+		"id": 29183
+	},
+	{
+		"id": 28304
+	},
+	{
+		"id": 21873
+	},
+	{
+		"id": 30727
+	},
+	{
+		"id": 29251
+	},
+	{
+		"id": 30736
+	},
+	{
+		"id": 28763
+	},
+	{
+		"id": 29376
+	},
+	{
+		"id": 28590
+	},
+	{
+		"id": 28771
+	},
+	{
+		"id": 29274
+	},
+	{
+		"id": 29779
+	}
+]}`)
@@ -0,0 +1,51 @@
+package healing
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+func (hpriest *HealingPriest) OnGCDReady(sim *core.Simulation) {
+	hpriest.tryUseGCD(sim)
+}
+
+func (hpriest *HealingPriest) OnManaTick(sim *core.Simulation) {
+	if !hpriest.HasAPLRotation() && hpriest.FinishedWaitingForManaAndGCDReady(sim) {
+		hpriest.tryUseGCD(sim)
+	}
+}
+
+func (hpriest *HealingPriest) tryUseGCD(sim *core.Simulation) {
+	healTarget := &hpriest.healTarget.Unit
+
+	var spell *core.Spell
+	if hpriest.rotation.UsePowerWordShield && hpriest.CanCastPowerWordShield(sim) {
+		spell = hpriest.PowerWordShield
+	} else if hpriest.rotation.UsePrayerOfMending && hpriest.PrayerOfMending.IsReady(sim) {
+		spell = hpriest.PrayerOfMending
+	} else if hpriest.rotation.UseCircleOfHealing && hpriest.CircleOfHealing != nil && hpriest.healTarget.Party.Size() > 1 {
+		// Circle of Healing is the most efficient heal whenever it hits more
+		// than one player.
+		spell = hpriest.CircleOfHealing
+	} else {
+		switch hpriest.rotation.PrimarySpell {
+		case proto.HealingPriest_Rotation_GreaterHeal:
+			spell = hpriest.GreaterHeal
+		case proto.HealingPriest_Rotation_FlashHeal:
+			spell = hpriest.FlashHeal
+		default:
+			hpriest.manaTracker.Update(sim, hpriest.GetCharacter())
+
+			// Flash Heal heals faster, but Greater Heal is more mana efficient.
+			if hpriest.manaTracker.ProjectedManaSurplus(sim, hpriest.GetCharacter()) {
+				spell = hpriest.FlashHeal
+			} else {
+				spell = hpriest.GreaterHeal
+			}
+		}
+	}
+
+	if success := spell.Cast(sim, healTarget); !success {
+		hpriest.WaitForMana(sim, spell.CurCast.Cost)
+	}
+}
@@ -0,0 +1,64 @@
+package priest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+// Power Word: Shield absorbs damage taken by the target, and applies Weakened
+// Soul which prevents the target from being shielded again for 15s.
+func (priest *Priest) RegisterPowerWordShieldSpell(target *core.Unit) {
+	actionID := core.ActionID{SpellID: 25218}
+	baseCost := 600.0
+
+	priest.WeakenedSoulAura = target.GetOrRegisterAura(core.Aura{
+		Label:    "Weakened Soul",
+		ActionID: core.ActionID{SpellID: 6788},
+		Duration: time.Second * 15,
+	})
+
+	absorbMultiplier := 1 + 0.05*float64(priest.Talents.ImprovedPowerWordShield)
+
+	priest.PowerWordShield = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost: baseCost,
+				GCD:  core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 4,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			amount := (1265 + 0.1*priest.GetStat(stats.HealingPower)) * absorbMultiplier
+			priest.PowerWordShieldAbsorb.Apply(sim, amount)
+			priest.WeakenedSoulAura.Activate(sim)
+		},
+	})
+
+	priest.PowerWordShieldAbsorb = core.NewAbsorbShield(core.AbsorbShield{
+		Spell: priest.PowerWordShield,
+		Aura: target.RegisterAura(core.Aura{
+			Label:    "PowerWordShield-" + strconv.Itoa(int(priest.Index)),
+			ActionID: actionID,
+			Duration: time.Second * 30,
+		}),
+	})
+}
+
+// Whether Power Word: Shield can currently be cast on its target.
+func (priest *Priest) CanCastPowerWordShield(sim *core.Simulation) bool {
+	return priest.PowerWordShield.IsReady(sim) && !priest.WeakenedSoulAura.IsActive()
+}
@@ -0,0 +1,94 @@
+package priest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+// Prayer of Mending places a buff on the target which heals them the next time
+// they take damage, then jumps to another raid member.
+func (priest *Priest) RegisterPrayerOfMendingSpell() {
+	actionID := core.ActionID{SpellID: 33076}
+	baseCost := 390.0 * (1 - 0.1*float64(priest.Talents.HealingPrayers))
+
+	healSpell := priest.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID.WithTag(1),
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(priest.newHealingSpellEffect(800, 800, 0.429)),
+	})
+
+	var players []*core.Unit
+	for _, unit := range priest.Env.Raid.AllUnits {
+		if unit.Type == core.PlayerUnit {
+			players = append(players, unit)
+		}
+	}
+
+	auras := make(map[*core.Unit]*core.Aura, len(players))
+	jump := func(sim *core.Simulation, from *core.Unit, charges int32) {
+		if len(players) < 2 {
+			return
+		}
+
+		// Jumps to a random raid member other than the current one.
+		nextIdx := int(sim.RandomFloat("Prayer of Mending Jump") * float64(len(players)-1))
+		if players[nextIdx] == from {
+			nextIdx = len(players) - 1
+		}
+		nextAura := auras[players[nextIdx]]
+		nextAura.Activate(sim)
+		nextAura.SetStacks(sim, charges)
+	}
+
+	for _, player := range players {
+		auras[player] = player.RegisterAura(core.Aura{
+			Label:     "PrayerOfMending-" + strconv.Itoa(int(priest.Index)),
+			ActionID:  actionID,
+			Duration:  time.Second * 30,
+			MaxStacks: 5,
+			OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, spellEffect *core.SpellEffect) {
+				if !spellEffect.Landed() || spellEffect.Damage == 0 {
+					return
+				}
+
+				healSpell.Cast(sim, aura.Unit)
+				charges := aura.GetStacks() - 1
+				aura.Deactivate(sim)
+				if charges > 0 {
+					jump(sim, aura.Unit, charges)
+				}
+			},
+		})
+	}
+
+	priest.PrayerOfMending = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    actionID,
+		SpellSchool: core.SpellSchoolHoly,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost: baseCost,
+				GCD:  core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			aura := auras[target]
+			aura.Activate(sim)
+			aura.SetStacks(sim, aura.MaxStacks)
+		},
+	})
+}
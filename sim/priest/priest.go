@@ -17,11 +17,16 @@ type Priest struct {
 
 	// cached cast stuff
 	// TODO: aoe multi-target situations will need multiple spells ticking for each target.
+	CircleOfHealing *core.Spell
 	DevouringPlague *core.Spell
+	FlashHeal       *core.Spell
+	GreaterHeal     *core.Spell
 	HolyFire        *core.Spell
 	InnerFocus      *core.Spell
 	MindBlast       *core.Spell
 	MindFlay        []*core.Spell
+	PowerWordShield *core.Spell
+	PrayerOfMending *core.Spell
 	ShadowWordDeath *core.Spell
 	ShadowWordPain  *core.Spell
 	Shadowfiend     *core.Spell
@@ -37,10 +42,13 @@ type Priest struct {
 	StarshardsDot      *core.Dot
 	VampiricTouchDot   *core.Dot
 
+	PowerWordShieldAbsorb *core.AbsorbShield
+
 	InnerFocusAura       *core.Aura
 	MiseryAura           *core.Aura
 	ShadowWeavingAura    *core.Aura
 	SurgeOfLightProcAura *core.Aura
+	WeakenedSoulAura     *core.Aura
 }
 
 type SelfBuffs struct {
@@ -21,7 +21,7 @@ func (spriest *ShadowPriest) OnGCDReady(sim *core.Simulation) {
 }
 
 func (spriest *ShadowPriest) OnManaTick(sim *core.Simulation) {
-	if spriest.FinishedWaitingForManaAndGCDReady(sim) {
+	if !spriest.HasAPLRotation() && spriest.FinishedWaitingForManaAndGCDReady(sim) {
 		spriest.tryUseGCD(sim)
 	}
 }
@@ -20,7 +20,7 @@ func (spriest *SmitePriest) OnGCDReady(sim *core.Simulation) {
 }
 
 func (spriest *SmitePriest) OnManaTick(sim *core.Simulation) {
-	if spriest.FinishedWaitingForManaAndGCDReady(sim) {
+	if !spriest.HasAPLRotation() && spriest.FinishedWaitingForManaAndGCDReady(sim) {
 		spriest.tryUseGCD(sim)
 	}
 }
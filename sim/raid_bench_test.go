@@ -299,6 +299,60 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+// Hunters with pets and melee, which keep many auto attack actions in the
+// pending action queue.
+var petsAndMelee = &proto.Party{
+	Players: []*proto.Player{
+		P1BMHunter,
+		P1BMHunter,
+		P1BMHunter,
+		P1EnhancementShaman,
+		P1EnhancementShaman,
+	},
+	Buffs: &proto.PartyBuffs{
+		Bloodlust:       1,
+		ManaSpringTotem: proto.TristateEffect_TristateEffectRegular,
+	},
+}
+
+// A full 25 player raid, where scheduling pending actions is a large part of
+// the cost of each iteration.
+func BenchmarkSimulate25Man(b *testing.B) {
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				castersWithElemental,
+				castersWithResto,
+				castersWithElemental,
+				petsAndMelee,
+				petsAndMelee,
+			},
+			Buffs: &proto.RaidBuffs{
+				GiftOfTheWild: proto.TristateEffect_TristateEffectImproved,
+			},
+			Debuffs: &proto.Debuffs{
+				JudgementOfWisdom:         true,
+				ImprovedSealOfTheCrusader: true,
+				CurseOfElements:           proto.TristateEffect_TristateEffectImproved,
+				IsbUptime:                 0.2,
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration:          180,
+			ExecuteProportion: 0.1,
+			Targets: []*proto.Target{
+				{
+					Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
+					MobType: proto.MobType_MobTypeDemon,
+				},
+			},
+		},
+		SimOptions: &proto.SimOptions{},
+	}
+
+	core.RaidBenchmark(b, rsr)
+}
+
 // P3 gear for each class
 
 // Shadow Priest Equipment
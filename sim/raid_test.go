@@ -1,9 +1,13 @@
 package sim
 
 import (
+	"context"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/items"
 	"github.com/wowsims/tbc/sim/core/proto"
 	"github.com/wowsims/tbc/sim/core/stats"
 
@@ -12,6 +16,8 @@ import (
 	shadowPriest "github.com/wowsims/tbc/sim/priest/shadow"
 	elementalShaman "github.com/wowsims/tbc/sim/shaman/elemental"
 	enhancementShaman "github.com/wowsims/tbc/sim/shaman/enhancement"
+	warlock "github.com/wowsims/tbc/sim/warlock"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -85,6 +91,16 @@ var P1BMHunter = &proto.Player{
 	Buffs:     hunter.FullIndividualBuffs,
 }
 
+var P4DestroWarlock = &proto.Player{
+	Name:      "P4 Destro Warlock",
+	Race:      proto.Race_RaceOrc,
+	Class:     proto.Class_ClassWarlock,
+	Equipment: warlock.Phase4Gear,
+	Consumes:  warlock.FullConsumes,
+	Spec:      warlock.DefaultDestroWarlock,
+	Buffs:     warlock.FullIndividualBuffs,
+}
+
 var BasicRaid = &proto.Raid{
 	Parties: []*proto.Party{
 		&proto.Party{
@@ -145,3 +161,1205 @@ func TestBasicRaid(t *testing.T) {
 
 	core.RaidSimTest("P1 ST", t, rsr, 6236.24)
 }
+
+func TestConcurrentRaidIsDeterministic(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      BasicRaid,
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations:  20,
+			RandomSeed:  101,
+			Concurrency: 4,
+		},
+	}
+
+	result1 := core.RunRaidSim(rsr)
+	result2 := core.RunRaidSim(rsr)
+
+	if result1.RaidMetrics.Dps.Avg != result2.RaidMetrics.Dps.Avg {
+		t.Fatalf("Expected identical DPS for the same seed, got %0.3f and %0.3f", result1.RaidMetrics.Dps.Avg, result2.RaidMetrics.Dps.Avg)
+	}
+
+	numSamples := int32(0)
+	for _, count := range result1.RaidMetrics.Dps.Hist {
+		numSamples += count
+	}
+	if numSamples != rsr.SimOptions.Iterations {
+		t.Fatalf("Expected %d merged iterations, got %d", rsr.SimOptions.Iterations, numSamples)
+	}
+}
+
+func TestTargetAbilities(t *testing.T) {
+	target := &proto.Target{
+		Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
+		MobType: proto.MobType_MobTypeDemon,
+		Abilities: []*proto.TargetAbility{
+			{
+				SpellId:   40126,
+				Name:      "Random Fire Bolt",
+				CastTime:  1,
+				Cooldown:  10,
+				School:    proto.SpellSchool_SpellSchoolFire,
+				MinDamage: 1000,
+				MaxDamage: 2000,
+				Targeting: proto.TargetAbility_TargetingRandomRaidMember,
+			},
+			{
+				SpellId:         41926,
+				Name:            "Raid Smash",
+				Cooldown:        20,
+				InitialCooldown: 5,
+				School:          proto.SpellSchool_SpellSchoolPhysical,
+				MinDamage:       500,
+				MaxDamage:       500,
+				Targeting:       proto.TargetAbility_TargetingAllRaidMembers,
+				Debuff: &proto.TargetAbilityDebuff{
+					Duration:              10,
+					MaxStacks:             2,
+					DamageTakenMultiplier: 1.1,
+				},
+			},
+		},
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				&proto.Party{
+					Players: []*proto.Player{
+						&proto.Player{},
+						P1ElementalShaman,
+					},
+				},
+				&proto.Party{
+					Players: []*proto.Player{
+						P1ShadowPriest,
+					},
+				},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{target},
+		},
+		SimOptions: SimOptions,
+	}
+
+	result := core.RunRaidSim(rsr)
+
+	for _, player := range []*proto.UnitMetrics{
+		result.RaidMetrics.Parties[0].Players[1],
+		result.RaidMetrics.Parties[1].Players[0],
+	} {
+		if player.Dtps.Avg <= 0 {
+			t.Fatalf("Expected %s to take damage from target abilities", player.Name)
+		}
+	}
+
+	targetMetrics := result.EncounterMetrics.Targets[0]
+	for _, spellID := range []int32{40126, 41926} {
+		found := false
+		for _, action := range targetMetrics.Actions {
+			if action.Id.GetSpellId() == spellID {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Expected target to use ability %d", spellID)
+		}
+	}
+}
+
+func TestTankGroupSplitAbility(t *testing.T) {
+	brutallus := core.GetPresetTargetWithPath("Sunwell Plateau/Brutallus")
+	target := googleProto.Clone(&brutallus.Config).(*proto.Target)
+
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				&proto.Party{
+					Players: []*proto.Player{
+						P1EnhancementShaman,
+						P1ElementalShaman,
+						P1ShadowPriest,
+					},
+				},
+				&proto.Party{
+					Players: []*proto.Player{
+						P1ElementalShaman,
+					},
+				},
+			},
+			Tanks: []*proto.RaidTarget{
+				&proto.RaidTarget{TargetIndex: 0},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{target},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 10,
+			IsTest:     true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed with error: %s", result.ErrorMsg)
+	}
+
+	// Only the tank's party is hit by Meteor Slash.
+	for _, player := range result.RaidMetrics.Parties[0].Players[1:] {
+		if player.Dtps.Avg <= 0 {
+			t.Fatalf("Expected %s to take damage from Meteor Slash", player.Name)
+		}
+	}
+	if dtps := result.RaidMetrics.Parties[1].Players[0].Dtps.Avg; dtps != 0 {
+		t.Fatalf("Expected players outside the tank group to take no damage, got %0.3f dtps", dtps)
+	}
+
+	var meteorSlash *proto.ActionMetrics
+	for _, action := range result.EncounterMetrics.Targets[0].Actions {
+		if action.Id.GetSpellId() == 45150 {
+			meteorSlash = action
+		}
+	}
+	if meteorSlash == nil {
+		t.Fatalf("Expected target to use Meteor Slash")
+	}
+
+	// 3470-4530 damage split between the 3 players, less partial resists.
+	hits := int32(0)
+	damage := 0.0
+	for _, targetMetrics := range meteorSlash.Targets {
+		hits += targetMetrics.Hits
+		damage += targetMetrics.Damage
+	}
+	if hits == 0 {
+		t.Fatalf("Expected Meteor Slash to hit")
+	}
+	avgHit := damage / float64(hits)
+	if avgHit < 3470.0/3*0.75 || avgHit > 4530.0/3 {
+		t.Fatalf("Expected split Meteor Slash hits between %0.1f and %0.1f, got %0.1f", 3470.0/3*0.75, 4530.0/3, avgHit)
+	}
+}
+
+func TestPlayerDeaths(t *testing.T) {
+	target := core.NewDefaultTarget()
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				&proto.Party{
+					Players: []*proto.Player{
+						P1ElementalShaman,
+					},
+				},
+			},
+			Tanks: []*proto.RaidTarget{
+				&proto.RaidTarget{TargetIndex: 0},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration:     120,
+			Targets:      []*proto.Target{target},
+			PlayerDeaths: true,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 10,
+			IsTest:     true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	player := result.RaidMetrics.Parties[0].Players[0]
+
+	if player.PercentSurvived != 0 {
+		t.Fatalf("Expected the tanking player to die in every iteration, but survived %0.1f%%", player.PercentSurvived)
+	}
+	if player.TimeToDeath == nil || player.TimeToDeath.Avg <= 0 || player.TimeToDeath.Avg >= 120 {
+		t.Fatalf("Expected a time to death within the encounter, got %v", player.TimeToDeath)
+	}
+
+	rsr.Encounter.PlayerDeaths = false
+	immortalResult := core.RunRaidSim(rsr)
+	immortalPlayer := immortalResult.RaidMetrics.Parties[0].Players[0]
+
+	if immortalPlayer.PercentSurvived != 100 {
+		t.Fatalf("Expected the player to survive without player deaths, but survived %0.1f%%", immortalPlayer.PercentSurvived)
+	}
+	if player.Dps.Avg >= immortalPlayer.Dps.Avg {
+		t.Fatalf("Expected dying to lower dps, got %0.3f vs %0.3f", player.Dps.Avg, immortalPlayer.Dps.Avg)
+	}
+}
+
+func TestPlayerDeathDismissesPets(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(P1BMHunter, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration:     120,
+			Targets:      []*proto.Target{core.NewDefaultTarget()},
+			PlayerDeaths: true,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 10,
+			IsTest:     true,
+		},
+	}
+	rsr.Raid.Tanks = []*proto.RaidTarget{{TargetIndex: 0}}
+
+	result := core.RunRaidSim(rsr)
+	rsr.Encounter.PlayerDeaths = false
+	immortalResult := core.RunRaidSim(rsr)
+
+	player := result.RaidMetrics.Parties[0].Players[0]
+	if player.PercentSurvived != 0 {
+		t.Fatalf("Expected the tanking hunter to die in every iteration, but survived %0.1f%%", player.PercentSurvived)
+	}
+	petDps := player.Pets[0].Dps.Avg
+	immortalPetDps := immortalResult.RaidMetrics.Parties[0].Players[0].Pets[0].Dps.Avg
+	// The hunter dies within seconds, so the pet should barely deal any damage.
+	if petDps >= immortalPetDps*0.5 {
+		t.Fatalf("Expected the pet to stop attacking when the hunter dies, got pet dps %0.3f vs %0.3f", petDps, immortalPetDps)
+	}
+}
+
+// Tests that every preset encounter can be simmed.
+func TestPresetEncounters(t *testing.T) {
+	presets := core.GetGearList(&proto.GearListRequest{}).Encounters
+	paths := map[string]int{}
+	for _, preset := range presets {
+		paths[preset.Path] = len(preset.Targets)
+
+		encounter := &proto.Encounter{
+			Duration: 60,
+		}
+		for _, target := range preset.Targets {
+			encounter.Targets = append(encounter.Targets, target.Target)
+		}
+
+		core.RunRaidSim(&proto.RaidSimRequest{
+			Raid:       BasicRaid,
+			Encounter:  encounter,
+			SimOptions: SimOptions,
+		})
+	}
+
+	expected := map[string]int{
+		"Karazhan/Prince Malchezaar":                  1,
+		"Gruul's Lair/High King Maulgar":              5,
+		"Magtheridon's Lair/Magtheridon":              1,
+		"Serpentshrine Cavern/Fathom-Lord Karathress": 4,
+		"Tempest Keep/Kael'thas Advisors":             4,
+		"Hyjal Summit/Archimonde":                     1,
+		"Zul'Aman/Zul'jin":                            1,
+	}
+	for path, numTargets := range expected {
+		if paths[path] != numTargets {
+			t.Errorf("Expected preset encounter %s with %d targets, got %d", path, numTargets, paths[path])
+		}
+	}
+}
+
+func TestEncounterPhases(t *testing.T) {
+	boss := &proto.Target{
+		Stats:   stats.Stats{stats.Health: 50_000, stats.Armor: 7684}.ToFloatArray(),
+		MobType: proto.MobType_MobTypeDemon,
+	}
+	add := &proto.Target{
+		Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
+		MobType: proto.MobType_MobTypeDemon,
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{boss, add},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+			Debug:      true,
+		},
+	}
+	baseline := core.RunRaidSim(rsr)
+
+	rsr.Encounter.Phases = []*proto.EncounterPhase{
+		{
+			// Boss is immune for the first 30s.
+			StartTime:     0,
+			Duration:      30,
+			ImmuneTargets: []int32{0},
+		},
+		{
+			// Add spawns every 20s, for 5s each time.
+			StartTime:      10,
+			RepeatInterval: 20,
+			Duration:       5,
+			SpawnTargets:   []int32{1},
+		},
+		{
+			// Boss takes 50% more damage below 90% health.
+			HealthPercent:         90,
+			HealthTargetIndex:     0,
+			DamageTakenMultiplier: 1.5,
+			DamageTakenTargets:    []int32{0},
+		},
+	}
+	result := core.RunRaidSim(rsr)
+
+	baselineDps := baseline.RaidMetrics.Dps.Avg
+	dps := result.RaidMetrics.Dps.Avg
+	if dps <= 0 || dps >= baselineDps*0.9 {
+		t.Fatalf("Expected boss immunity to lower dps, got %0.3f vs %0.3f", dps, baselineDps)
+	}
+
+	if active := result.EncounterMetrics.Targets[0].SecondsActiveAvg; active != 60 {
+		t.Fatalf("Expected boss to be active for 60s, got %0.3f", active)
+	}
+	if active := result.EncounterMetrics.Targets[1].SecondsActiveAvg; active != 15 {
+		t.Fatalf("Expected add to be active for 15s, got %0.3f", active)
+	}
+
+	for _, msg := range []string{"Encounter phase 3 begins.", "[Target 2] Spawned.", "[Target 2] Despawned."} {
+		if !strings.Contains(result.Logs, msg) {
+			t.Fatalf("Expected log message: %s", msg)
+		}
+	}
+}
+
+func TestHealthBasedEncounter(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration:  60,
+			UseHealth: true,
+			Targets: []*proto.Target{
+				{
+					Stats:   stats.Stats{stats.Health: 150_000, stats.Armor: 7684}.ToFloatArray(),
+					MobType: proto.MobType_MobTypeDemon,
+				},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+	result := core.RunRaidSim(rsr)
+
+	if result.KillTime == nil {
+		t.Fatalf("Expected kill time metrics")
+	}
+	killTime := result.KillTime.Avg
+	if killTime <= 60 || killTime >= 600 {
+		t.Fatalf("Expected kill time to be based on target health, got %0.3f", killTime)
+	}
+
+	totalDamage := result.RaidMetrics.Dps.Avg * killTime
+	if totalDamage < 150_000*0.9 || totalDamage > 150_000*1.1 {
+		t.Fatalf("Expected raid damage to match target health, got %0.3f", totalDamage)
+	}
+
+	rsr.Encounter.Targets[0].Stats = stats.Stats{stats.Health: 50_000, stats.Armor: 7684}.ToFloatArray()
+	result = core.RunRaidSim(rsr)
+	if result.KillTime.Avg >= killTime {
+		t.Fatalf("Expected less health to give shorter fights, got %0.3f vs %0.3f", result.KillTime.Avg, killTime)
+	}
+
+	// Iterations cut off by the duration limit aren't kills.
+	rsr.Encounter.Targets[0].Stats = stats.Stats{stats.Health: 100_000_000, stats.Armor: 7684}.ToFloatArray()
+	rsr.SimOptions.Iterations = 2
+	result = core.RunRaidSim(rsr)
+	if result.KillTime != nil {
+		t.Fatalf("Expected no kill time metrics when the target never dies, got %0.3f", result.KillTime.Avg)
+	}
+}
+
+func TestCombatLog(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: core.MakeSingleTargetEncounter(0),
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+			CombatLog:  true,
+		},
+	}
+	result := core.RunRaidSim(rsr)
+
+	if len(result.CombatLog) == 0 {
+		t.Fatalf("Expected combat log events")
+	}
+
+	eventCounts := make(map[proto.CombatLogEvent_Type]int)
+	totalDamage := 0.0
+	lastTimestamp := 0.0
+	for _, event := range result.CombatLog {
+		eventCounts[event.Type]++
+		if event.Timestamp < lastTimestamp {
+			t.Fatalf("Combat log events out of order: %0.3f after %0.3f", event.Timestamp, lastTimestamp)
+		}
+		lastTimestamp = event.Timestamp
+
+		if event.Type == proto.CombatLogEvent_Damage && event.Unit.Type == proto.UnitReference_Player {
+			totalDamage += event.Amount
+		}
+	}
+
+	for _, eventType := range []proto.CombatLogEvent_Type{
+		proto.CombatLogEvent_Damage,
+		proto.CombatLogEvent_CastStart,
+		proto.CombatLogEvent_CastComplete,
+		proto.CombatLogEvent_AuraGained,
+		proto.CombatLogEvent_AuraFaded,
+		proto.CombatLogEvent_ResourceChange,
+		proto.CombatLogEvent_CooldownUsed,
+	} {
+		if eventCounts[eventType] == 0 {
+			t.Fatalf("Expected combat log events of type %s", eventType)
+		}
+	}
+
+	dps := totalDamage / result.FirstIterationDuration
+	if math.Abs(dps-result.RaidMetrics.Dps.Avg) > 0.001 {
+		t.Fatalf("Expected combat log damage to match metrics, got %0.3f dps vs %0.3f", dps, result.RaidMetrics.Dps.Avg)
+	}
+}
+
+func TestMovementEvents(t *testing.T) {
+	movements := []*proto.MovementEvent{
+		{
+			// Ranged players move for 4s every 10s.
+			StartTime:      5,
+			Duration:       4,
+			RepeatInterval: 10,
+			Role:           proto.MovementEvent_Ranged,
+		},
+	}
+
+	for _, testCase := range []struct {
+		player     *proto.Player
+		expectMove bool
+		logMessage string
+	}{
+		{P1ElementalShaman, true, "Cast interrupted by movement."},
+		{P1ShadowPriest, true, "Channel interrupted by movement."},
+		{P1EnhancementShaman, false, ""},
+	} {
+		rsr := &proto.RaidSimRequest{
+			Raid:      core.SinglePlayerRaidProto(testCase.player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+			Encounter: core.MakeSingleTargetEncounter(0),
+			SimOptions: &proto.SimOptions{
+				Iterations: 1,
+				IsTest:     true,
+				Debug:      true,
+			},
+		}
+		baseline := core.RunRaidSim(rsr)
+
+		rsr.Encounter.Movements = movements
+		result := core.RunRaidSim(rsr)
+
+		baselineDps := baseline.RaidMetrics.Dps.Avg
+		dps := result.RaidMetrics.Dps.Avg
+		if testCase.expectMove {
+			if dps <= 0 || dps >= baselineDps*0.9 {
+				t.Fatalf("Expected movement to lower dps, got %0.3f vs %0.3f", dps, baselineDps)
+			}
+			if !strings.Contains(result.Logs, testCase.logMessage) {
+				t.Fatalf("Expected log message: %s", testCase.logMessage)
+			}
+		} else if dps != baselineDps {
+			t.Fatalf("Expected no change in dps for players who don't move, got %0.3f vs %0.3f", dps, baselineDps)
+		}
+	}
+
+	// Melee players are out of range while moving, so their swings are paused.
+	movements[0].Role = proto.MovementEvent_Melee
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1EnhancementShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: core.MakeSingleTargetEncounter(0),
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+		},
+	}
+	baseline := core.RunRaidSim(rsr)
+	rsr.Encounter.Movements = movements
+	result := core.RunRaidSim(rsr)
+	if result.RaidMetrics.Dps.Avg >= baseline.RaidMetrics.Dps.Avg*0.9 {
+		t.Fatalf("Expected movement to lower melee dps, got %0.3f vs %0.3f", result.RaidMetrics.Dps.Avg, baseline.RaidMetrics.Dps.Avg)
+	}
+
+	// Melee pets move too, even when their owner doesn't.
+	rsr.Raid = core.SinglePlayerRaidProto(P1BMHunter, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	rsr.Encounter.Movements = nil
+	baseline = core.RunRaidSim(rsr)
+	rsr.Encounter.Movements = movements
+	result = core.RunRaidSim(rsr)
+	baselinePetDps := baseline.RaidMetrics.Parties[0].Players[0].Pets[0].Dps.Avg
+	petDps := result.RaidMetrics.Parties[0].Players[0].Pets[0].Dps.Avg
+	if petDps >= baselinePetDps*0.9 {
+		t.Fatalf("Expected movement to lower pet dps, got %0.3f vs %0.3f", petDps, baselinePetDps)
+	}
+}
+
+func TestTargetSpellResistances(t *testing.T) {
+	target := &proto.Target{
+		Level:   73,
+		Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
+		MobType: proto.MobType_MobTypeDemon,
+		Abilities: []*proto.TargetAbility{
+			{
+				SpellId:   40827,
+				Name:      "Sinful Beam",
+				Cooldown:  2,
+				School:    proto.SpellSchool_SpellSchoolShadow,
+				MinDamage: 3000,
+				MaxDamage: 3000,
+				Targeting: proto.TargetAbility_TargetingAllRaidMembers,
+			},
+		},
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{target},
+		},
+		SimOptions: SimOptions,
+	}
+	baseline := core.RunRaidSim(rsr)
+
+	rsr.Raid = core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{ShadowProtection: true}, &proto.Debuffs{})
+	result := core.RunRaidSim(rsr)
+
+	baselineDtps := baseline.RaidMetrics.Parties[0].Players[0].Dtps.Avg
+	dtps := result.RaidMetrics.Parties[0].Players[0].Dtps.Avg
+	if baselineDtps <= 0 || dtps >= baselineDtps*0.9 {
+		t.Fatalf("Expected shadow resistance to lower damage taken, got %0.3f vs %0.3f", dtps, baselineDtps)
+	}
+}
+
+func TestImprovedShadowBolt(t *testing.T) {
+	isbActionID := core.ActionID{SpellID: 17803}.ToProto()
+	getIsbMetrics := func(result *proto.RaidSimResult) *proto.AuraMetrics {
+		for _, aura := range result.EncounterMetrics.Targets[0].Auras {
+			if aura.Id.GetSpellId() == isbActionID.GetSpellId() {
+				return aura
+			}
+		}
+		t.Fatalf("Expected ISB aura metrics")
+		return nil
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ShadowPriest, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+	baseline := core.RunRaidSim(rsr)
+
+	// Estimated ISB, from warlocks which aren't in the sim.
+	rsr.Raid.Debuffs = &proto.Debuffs{
+		IsbWarlocks:      2,
+		IsbShadowCasters: 1,
+	}
+	result := core.RunRaidSim(rsr)
+	if result.RaidMetrics.Dps.Avg <= baseline.RaidMetrics.Dps.Avg {
+		t.Fatalf("Expected estimated ISB to increase dps, got %0.3f vs %0.3f", result.RaidMetrics.Dps.Avg, baseline.RaidMetrics.Dps.Avg)
+	}
+	isb := getIsbMetrics(result)
+	if isb.UptimeSecondsAvg <= 0 || isb.UptimeSecondsAvg >= STEncounter.Duration {
+		t.Fatalf("Expected partial ISB uptime, got %0.3f", isb.UptimeSecondsAvg)
+	}
+	if isb.ChargesConsumedAvg <= 0 {
+		t.Fatalf("Expected ISB charges to be consumed")
+	}
+
+	// A real warlock in the raid replaces the flat uptime approximation.
+	rsr.Raid = &proto.Raid{
+		Parties: []*proto.Party{
+			&proto.Party{
+				Players: []*proto.Player{
+					P4DestroWarlock,
+					P1ShadowPriest,
+				},
+			},
+		},
+		Debuffs: &proto.Debuffs{
+			IsbUptime: 1,
+		},
+	}
+	result = core.RunRaidSim(rsr)
+	isb = getIsbMetrics(result)
+	if isb.UptimeSecondsAvg <= 0 || isb.UptimeSecondsAvg >= STEncounter.Duration {
+		t.Fatalf("Expected ISB uptime from real warlock crits, got %0.3f", isb.UptimeSecondsAvg)
+	}
+	if isb.ChargesConsumedAvg <= 0 {
+		t.Fatalf("Expected ISB charges to be consumed")
+	}
+}
+
+func TestAPLRotation(t *testing.T) {
+	lightningBolt := core.ActionID{SpellID: 25449}.ToProto()
+	chainLightning := core.ActionID{SpellID: 25442}.ToProto()
+	manaSpringTotem := core.ActionID{SpellID: 25570}.ToProto()
+	getCasts := func(result *proto.RaidSimResult, actionID *proto.ActionID) int32 {
+		casts := int32(0)
+		for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+			if googleProto.Equal(action.Id, actionID) {
+				for _, target := range action.Targets {
+					casts += target.Casts
+				}
+			}
+		}
+		return casts
+	}
+
+	player := googleProto.Clone(P1ElementalShaman).(*proto.Player)
+	player.AplRotation = &proto.APLRotation{
+		PriorityList: []*proto.APLAction{
+			{
+				ActionId: chainLightning,
+				Conditions: []*proto.APLCondition{
+					{
+						Type:       proto.APLCondition_CurrentManaPercent,
+						Comparison: proto.APLCondition_GreaterThan,
+						Value:      50,
+					},
+				},
+			},
+			{ActionId: lightningBolt},
+		},
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+		},
+	}
+	result := core.RunRaidSim(rsr)
+	if result.RaidMetrics.Dps.Avg <= 0 {
+		t.Fatalf("Expected dps from APL rotation")
+	}
+	if getCasts(result, lightningBolt) == 0 || getCasts(result, chainLightning) == 0 {
+		t.Fatalf("Expected both Lightning Bolt and Chain Lightning casts")
+	}
+	// The spec's built-in rotation, which drops totems, is replaced.
+	if casts := getCasts(result, manaSpringTotem); casts != 0 {
+		t.Fatalf("Expected no totems outside of the APL, got %d casts", casts)
+	}
+
+	// A condition which never passes removes the action from the rotation.
+	player.AplRotation.PriorityList[0].Conditions[0].Not = true
+	player.AplRotation.PriorityList[0].Conditions[0].Value = -1
+	result = core.RunRaidSim(rsr)
+	if casts := getCasts(result, chainLightning); casts != 0 {
+		t.Fatalf("Expected no Chain Lightning casts, got %d", casts)
+	}
+}
+
+func TestInvalidAPLRotation(t *testing.T) {
+	lightningBolt := core.ActionID{SpellID: 25449}.ToProto()
+	for name, priorityList := range map[string][]*proto.APLAction{
+		"missing action ID": {{}},
+		"unknown spell":     {{ActionId: core.ActionID{SpellID: 12345}.ToProto()}},
+		"unknown condition spell": {{
+			ActionId: lightningBolt,
+			Conditions: []*proto.APLCondition{
+				{
+					Type:     proto.APLCondition_SpellReady,
+					ActionId: core.ActionID{SpellID: 12345}.ToProto(),
+				},
+			},
+		}},
+		"unknown condition type": {{
+			ActionId: lightningBolt,
+			Conditions: []*proto.APLCondition{
+				{Type: proto.APLCondition_Type(-1)},
+			},
+		}},
+	} {
+		player := googleProto.Clone(P1ElementalShaman).(*proto.Player)
+		player.AplRotation = &proto.APLRotation{PriorityList: priorityList}
+
+		rsr := &proto.RaidSimRequest{
+			Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+			Encounter: STEncounter,
+			SimOptions: &proto.SimOptions{
+				Iterations: 2,
+				IsTest:     true,
+			},
+		}
+		if result := core.RunRaidSim(rsr); result.ErrorMsg == "" {
+			t.Fatalf("Expected an error for an APL with %s", name)
+		}
+
+		rsr.SimOptions.Concurrency = 2
+		progress := make(chan *proto.ProgressMetrics, 10)
+		core.RunRaidSimAsync(context.Background(), rsr, progress)
+		for metrics := range progress {
+			if metrics.FinalRaidResult != nil {
+				if metrics.FinalRaidResult.ErrorMsg == "" {
+					t.Fatalf("Expected an async error for an APL with %s", name)
+				}
+				break
+			}
+		}
+	}
+}
+
+func TestTimelineMetrics(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{Bloodlust: 1}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations:         20,
+			IsTest:             true,
+			TimelineBucketSize: 10,
+		},
+	}
+
+	for _, concurrency := range []int32{0, 2} {
+		rsr.SimOptions.Concurrency = concurrency
+		result := core.RunRaidSim(rsr)
+		playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+		timeline := playerMetrics.Timeline
+
+		numBuckets := int(STEncounter.Duration / 10)
+		if timeline.BucketSize != 10 || len(timeline.Dps) != numBuckets || len(timeline.Mana) != numBuckets {
+			t.Fatalf("Expected %d buckets of 10s, got %d dps and %d mana buckets of %0.1fs", numBuckets, len(timeline.Dps), len(timeline.Mana), timeline.BucketSize)
+		}
+		if len(timeline.Rage) != 0 || len(timeline.Energy) != 0 {
+			t.Fatalf("Expected no timeline for unused resources")
+		}
+
+		// The average of the buckets is the fight-wide dps, since every bucket is full.
+		totalDps := 0.0
+		for _, dps := range timeline.Dps {
+			totalDps += dps
+		}
+		if math.Abs(totalDps/float64(numBuckets)-playerMetrics.Dps.Avg) > 0.01 {
+			t.Fatalf("Expected timeline dps to average to %0.3f, got %0.3f", playerMetrics.Dps.Avg, totalDps/float64(numBuckets))
+		}
+
+		if timeline.Mana[numBuckets-1] >= timeline.Mana[0] {
+			t.Fatalf("Expected mana to drop over the fight, got %0.1f -> %0.1f", timeline.Mana[0], timeline.Mana[numBuckets-1])
+		}
+
+		// Bloodlust is used once and lasts 40s.
+		var bloodlust *proto.AuraTimeline
+		for _, aura := range timeline.Auras {
+			if aura.Id.GetSpellId() == 2825 {
+				bloodlust = aura
+			}
+		}
+		if bloodlust == nil {
+			t.Fatalf("Expected a Bloodlust aura timeline")
+		}
+		if len(bloodlust.ActiveProbability) != numBuckets {
+			t.Fatalf("Expected %d Bloodlust buckets, got %d", numBuckets, len(bloodlust.ActiveProbability))
+		}
+		uptime := 0.0
+		for _, probability := range bloodlust.ActiveProbability {
+			uptime += probability * timeline.BucketSize
+		}
+		if math.Abs(uptime-40) > 0.01 || bloodlust.ActiveProbability[numBuckets-1] != 0 {
+			t.Fatalf("Expected a single 40s Bloodlust window, got %v", bloodlust.ActiveProbability)
+		}
+	}
+}
+
+func TestDistributionMetrics(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 200,
+			IsTest:     true,
+		},
+	}
+	baseline := core.RunRaidSim(rsr)
+
+	for _, dist := range []*proto.DistributionMetrics{
+		baseline.RaidMetrics.Dps,
+		baseline.RaidMetrics.Parties[0].Dps,
+		baseline.RaidMetrics.Parties[0].Players[0].Dps,
+		baseline.RaidMetrics.Parties[0].Players[0].Threat,
+		baseline.EncounterMetrics.Targets[0].Dps,
+	} {
+		if dist.Count != 200 {
+			t.Fatalf("Expected 200 samples, got %d", dist.Count)
+		}
+		if !(dist.Min <= dist.P5 && dist.P5 <= dist.P25 && dist.P25 <= dist.P50 && dist.P50 <= dist.P75 && dist.P75 <= dist.P95 && dist.P95 <= dist.Max) {
+			t.Fatalf("Expected ordered percentiles, got %v", dist)
+		}
+		if dist.Ci95Lower > dist.Avg || dist.Ci95Upper < dist.Avg || math.Abs(dist.Stderr-dist.Stdev/math.Sqrt(200)) > 0.0001 {
+			t.Fatalf("Expected a confidence interval around the mean, got %v", dist)
+		}
+	}
+
+	// Same setup with a different seed.
+	rsr.SimOptions.RandomSeed = 12345
+	reseeded := core.RunRaidSim(rsr)
+	if comparison := core.CompareRaidSimResults(baseline, reseeded); comparison.Significant {
+		t.Fatalf("Expected no significant difference for the same setup, got %+v", comparison)
+	}
+
+	// A better setup, with yet another seed to keep the sims independent.
+	rsr.SimOptions.RandomSeed = 54321
+	rsr.Raid.Buffs = &proto.RaidBuffs{ArcaneBrilliance: true, GiftOfTheWild: proto.TristateEffect_TristateEffectImproved}
+	rsr.Raid.Parties[0].Buffs = &proto.PartyBuffs{Bloodlust: 1, WrathOfAirTotem: proto.TristateEffect_TristateEffectImproved}
+	buffed := core.RunRaidSim(rsr)
+	comparison := core.CompareRaidSimResults(baseline, buffed)
+	if !comparison.Significant || comparison.Difference <= 0 || comparison.Ci95Lower <= 0 {
+		t.Fatalf("Expected a significant dps increase from buffs, got %+v", comparison)
+	}
+}
+
+func TestAdaptiveIterations(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations:      5000,
+			IsTest:          true,
+			TargetDpsStderr: 5,
+			MinIterations:   50,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	dps := result.RaidMetrics.Dps
+	if dps.Count < 50 || dps.Count >= 5000 {
+		t.Fatalf("Expected to stop between the iteration caps, ran %d iterations", dps.Count)
+	}
+	if dps.Stderr > 5 {
+		t.Fatalf("Expected stderr <= 5, got %0.2f", dps.Stderr)
+	}
+
+	// A tighter target for the player needs more iterations.
+	rsr.SimOptions.TargetDpsStderr = 2.5
+	rsr.SimOptions.TargetDpsStderrPlayer = &proto.RaidTarget{TargetIndex: 0}
+	tighter := core.RunRaidSim(rsr)
+	playerDps := tighter.RaidMetrics.Parties[0].Players[0].Dps
+	if playerDps.Count <= dps.Count || playerDps.Stderr > 2.5 {
+		t.Fatalf("Expected more iterations for a tighter target, ran %d with stderr %0.2f", playerDps.Count, playerDps.Stderr)
+	}
+
+	// Concurrent workers should still meet the combined target.
+	rsr.SimOptions.Concurrency = 4
+	concurrent := core.RunRaidSim(rsr)
+	concurrentDps := concurrent.RaidMetrics.Parties[0].Players[0].Dps
+	if concurrentDps.Count >= 5000 || concurrentDps.Stderr > 2.5*1.1 {
+		t.Fatalf("Expected concurrent sim to stop near the target, ran %d with stderr %0.2f", concurrentDps.Count, concurrentDps.Stderr)
+	}
+
+	// A target player outside the raid fails the request instead of the sim.
+	rsr.SimOptions.TargetDpsStderrPlayer = &proto.RaidTarget{TargetIndex: 5}
+	invalid := core.RunRaidSim(rsr)
+	if invalid.ErrorMsg == "" {
+		t.Fatalf("Expected an error for an invalid target_dps_stderr_player")
+	}
+	rsr.SimOptions.TargetDpsStderrPlayer = &proto.RaidTarget{TargetIndex: 0}
+
+	// The max cap wins over the target.
+	rsr.SimOptions.TargetDpsStderr = 0.01
+	rsr.SimOptions.Concurrency = 0
+	rsr.SimOptions.MaxIterations = 300
+	capped := core.RunRaidSim(rsr)
+	if capped.RaidMetrics.Dps.Count != 300 {
+		t.Fatalf("Expected 300 iterations, got %d", capped.RaidMetrics.Dps.Count)
+	}
+}
+
+func TestCancelledSim(t *testing.T) {
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(P1ElementalShaman, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 5000,
+			IsTest:     true,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	progress := make(chan *proto.ProgressMetrics, 100)
+	result := core.RunSimWithContext(ctx, *rsr, progress)
+	if result.ErrorMsg == "" || result.RaidMetrics != nil {
+		t.Fatalf("Expected a cancelled result, got %v", result)
+	}
+	// Consumers of the progress channel still get a final result.
+	var final *proto.ProgressMetrics
+	for final == nil || final.FinalRaidResult == nil {
+		final = <-progress
+	}
+	if final.CompletedIterations >= 5000 {
+		t.Fatalf("Expected the sim to stop early, ran %d iterations", final.CompletedIterations)
+	}
+
+	rsr.SimOptions.Concurrency = 4
+	concurrent := core.RunSimWithContext(ctx, *rsr, nil)
+	if concurrent.ErrorMsg == "" {
+		t.Fatalf("Expected a cancelled result from the concurrent sim")
+	}
+}
+
+func TestUnimplementedItemEffects(t *testing.T) {
+	// Icon of the Silver Crescent has a registered effect, Cyclone Faceguard is
+	// in a set, and Ribbon of Sacrifice has neither.
+	for _, c := range []struct {
+		item          items.Item
+		unimplemented bool
+	}{
+		{items.Item{ID: 29370, HasEffect: true}, false},
+		{items.Item{ID: 29035, HasEffect: true}, false},
+		{items.Item{ID: 28590, HasEffect: true}, true},
+		{items.Item{ID: 28590}, false},
+	} {
+		if unimplemented := core.HasUnimplementedEffect(c.item); unimplemented != c.unimplemented {
+			t.Errorf("Item %d with HasEffect %t: expected unimplemented effect to be %t", c.item.ID, c.item.HasEffect, c.unimplemented)
+		}
+	}
+
+	// The rest checks the generated item data, whose HasEffect flags come from
+	// the item tooltips.
+	var expected []items.Item
+	for _, item := range items.Items {
+		if core.HasUnimplementedEffect(item) {
+			expected = append(expected, item)
+		}
+	}
+
+	unimplemented := core.GetUnimplementedItems(&proto.UnimplementedItemsRequest{})
+	if len(unimplemented.Items) != len(expected) {
+		t.Fatalf("Expected %d unimplemented items, got %d", len(expected), len(unimplemented.Items))
+	}
+	for i, item := range unimplemented.Items {
+		if item.Id != expected[i].ID || !item.UnimplementedEffect {
+			t.Errorf("Unexpected unimplemented item: %v", item)
+		}
+	}
+
+	for _, item := range core.GetGearList(&proto.GearListRequest{}).Items {
+		if item.UnimplementedEffect != core.HasUnimplementedEffect(items.ByID[item.Id]) {
+			t.Errorf("Unexpected gear list flag for %s: %t", item.Name, item.UnimplementedEffect)
+		}
+	}
+
+	var trinket *items.Item
+	for i := range expected {
+		if expected[i].Type == proto.ItemType_ItemTypeTrinket {
+			trinket = &expected[i]
+			break
+		}
+	}
+	if trinket == nil {
+		t.Skip("No trinkets with unimplemented effects in the generated item data")
+	}
+	player := googleProto.Clone(P1ElementalShaman).(*proto.Player)
+	player.Equipment.Items[proto.ItemSlot_ItemSlotTrinket2] = &proto.ItemSpec{Id: trinket.ID}
+	result := core.ComputeStats(&proto.ComputeStatsRequest{
+		Raid: core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+	})
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], trinket.Name) {
+		t.Fatalf("Expected a warning for %s, got %v", trinket.Name, result.Warnings)
+	}
+}
+
+func TestDualWieldItemProcs(t *testing.T) {
+	player := googleProto.Clone(P1EnhancementShaman).(*proto.Player)
+	player.Equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 28438} // Dragonmaw
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{Id: 28438}
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed: %s", result.ErrorMsg)
+	}
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+	if playerMetrics.Dps.Avg <= 0 {
+		t.Fatalf("Expected dps with dual wielded Dragonmaws")
+	}
+
+	// Both weapons share a single haste aura, which either hand can trigger, so
+	// the 212 haste never stacks.
+	found := false
+	for _, aura := range playerMetrics.Auras {
+		if aura.Id.GetItemId() != 28438 {
+			continue
+		}
+		if aura.Id.Tag != 0 {
+			t.Errorf("Expected a single Dragonmaw proc aura, got one with tag %d", aura.Id.Tag)
+		}
+		if aura.UptimeSecondsAvg > 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected uptime for the Dragonmaw proc")
+	}
+
+	var procs []*proto.ProcMetrics
+	for _, procMetrics := range playerMetrics.Procs {
+		if procMetrics.Id.GetItemId() == 28438 {
+			procs = append(procs, procMetrics)
+		}
+	}
+	if len(procs) != 1 || procs[0].ProcsAvg == 0 {
+		t.Fatalf("Expected a single proc metric for both Dragonmaws, got %v", procs)
+	}
+}
+
+func TestProcMetricsForItemHelpers(t *testing.T) {
+	player := googleProto.Clone(P1EnhancementShaman).(*proto.Player)
+	player.Equipment.Items[proto.ItemSlot_ItemSlotTrinket1] = &proto.ItemSpec{Id: 28579} // Romulo's Poison Vial
+	player.Equipment.Items[proto.ItemSlot_ItemSlotTrinket2] = &proto.ItemSpec{Id: 31856} // Darkmoon Card: Crusade
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed: %s", result.ErrorMsg)
+	}
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+
+	procs := map[core.ActionID]*proto.ProcMetrics{}
+	for _, procMetrics := range playerMetrics.Procs {
+		procs[core.ActionID{ItemID: procMetrics.Id.GetItemId(), Tag: procMetrics.Id.Tag}] = procMetrics
+	}
+	if romulos := procs[core.ActionID{ItemID: 28579}]; romulos == nil || romulos.ProcsAvg == 0 {
+		t.Errorf("Expected Romulo's Poison Vial procs, got %v", romulos)
+	}
+	crusade := procs[core.ActionID{ItemID: 31856, Tag: 1}]
+	if crusade == nil || crusade.ProcsAvg == 0 {
+		t.Fatalf("Expected Darkmoon Card: Crusade procs, got %v", crusade)
+	}
+	if ap := crusade.StatsAvg[stats.AttackPower]; ap <= 0 || ap > 6*20 {
+		t.Errorf("Expected average attack power from Darkmoon Card: Crusade between 0 and 120, got %0.2f", ap)
+	}
+	// Each melee hit adds a stack and refreshes the active aura.
+	if crusade.RefreshesAvg == 0 || crusade.RefreshesAvg > crusade.ProcsAvg {
+		t.Errorf("Expected Darkmoon Card: Crusade refreshes between 0 and its %0.2f procs, got %0.2f", crusade.ProcsAvg, crusade.RefreshesAvg)
+	}
+}
+
+func TestProcMetricsForSetBonusesAndConsumes(t *testing.T) {
+	player := googleProto.Clone(P1ElementalShaman).(*proto.Player)
+	player.Equipment.Items[proto.ItemSlot_ItemSlotHead] = &proto.ItemSpec{Id: 24266} // Spellstrike Hood
+	player.Equipment.Items[proto.ItemSlot_ItemSlotLegs] = &proto.ItemSpec{Id: 24262} // Spellstrike Pants
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed: %s", result.ErrorMsg)
+	}
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+
+	var spellstrike *proto.ProcMetrics
+	for _, procMetrics := range playerMetrics.Procs {
+		if procMetrics.Id.GetSpellId() == 32106 {
+			spellstrike = procMetrics
+		}
+		// Potions and other on-use items aren't procs.
+		if itemID := procMetrics.Id.GetItemId(); itemID == 22839 || itemID == 22832 {
+			t.Errorf("Unexpected proc metrics for potion %d: %v", itemID, procMetrics)
+		}
+	}
+	if spellstrike == nil || spellstrike.ProcsAvg == 0 {
+		t.Fatalf("Expected Spellstrike Infusion procs, got %v", spellstrike)
+	}
+	if sp := spellstrike.StatsAvg[stats.SpellPower]; sp <= 0 || sp >= 92 {
+		t.Errorf("Expected average spell power from Spellstrike Infusion between 0 and 92, got %0.2f", sp)
+	}
+}
+
+func TestProcMetricsAndTrinketContributions(t *testing.T) {
+	player := googleProto.Clone(P1EnhancementShaman).(*proto.Player)
+	// Dragonspine Trophy is already in the first trinket slot.
+	player.Equipment.Items[proto.ItemSlot_ItemSlotTrinket2] = &proto.ItemSpec{Id: 28034} // Hourglass of the Unraveller
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations:           20,
+			IsTest:               true,
+			TrinketContributions: true,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed: %s", result.ErrorMsg)
+	}
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+
+	procs := map[int32]*proto.ProcMetrics{}
+	for _, procMetrics := range playerMetrics.Procs {
+		procs[procMetrics.Id.GetItemId()] = procMetrics
+	}
+	dst := procs[28830]
+	if dst == nil || dst.ProcsAvg == 0 {
+		t.Fatalf("Expected Dragonspine Trophy procs, got %v", dst)
+	}
+	// 20s internal cooldown.
+	if dst.ProcsPerMinute > 3 {
+		t.Errorf("Dragonspine Trophy procced %0.2f times per minute", dst.ProcsPerMinute)
+	}
+	if dst.IcdBlockedProcsAvg == 0 {
+		t.Errorf("Expected some Dragonspine Trophy procs to be blocked by the internal cooldown")
+	}
+	if haste := dst.StatsAvg[stats.MeleeHaste]; haste <= 0 || haste >= 325 {
+		t.Errorf("Expected average haste from Dragonspine Trophy between 0 and 325, got %0.2f", haste)
+	}
+	if hourglass := procs[28034]; hourglass == nil || hourglass.ProcsAvg == 0 {
+		t.Errorf("Expected Hourglass of the Unraveller procs, got %v", hourglass)
+	}
+
+	contributions := playerMetrics.TrinketContributions
+	if len(contributions) != 2 {
+		t.Fatalf("Expected 2 trinket contributions, got %v", contributions)
+	}
+	if contributions[0].ItemId != 28830 || contributions[0].Slot != proto.ItemSlot_ItemSlotTrinket1 || contributions[1].ItemId != 28034 {
+		t.Errorf("Unexpected trinket contributions: %v", contributions)
+	}
+	if contributions[0].Dps <= 0 {
+		t.Errorf("Expected Dragonspine Trophy to add DPS, got %0.2f", contributions[0].Dps)
+	}
+}
@@ -4,17 +4,21 @@ import (
 	_ "github.com/wowsims/tbc/sim/common"
 	"github.com/wowsims/tbc/sim/druid/balance"
 	"github.com/wowsims/tbc/sim/druid/feral"
+	restoDruid "github.com/wowsims/tbc/sim/druid/restoration"
 	feralTank "github.com/wowsims/tbc/sim/druid/tank"
 	_ "github.com/wowsims/tbc/sim/encounters"
 	"github.com/wowsims/tbc/sim/hunter"
 	"github.com/wowsims/tbc/sim/mage"
+	holyPaladin "github.com/wowsims/tbc/sim/paladin/holy"
 	protectionPaladin "github.com/wowsims/tbc/sim/paladin/protection"
 	"github.com/wowsims/tbc/sim/paladin/retribution"
+	healingPriest "github.com/wowsims/tbc/sim/priest/healing"
 	"github.com/wowsims/tbc/sim/priest/shadow"
 	"github.com/wowsims/tbc/sim/priest/smite"
 	"github.com/wowsims/tbc/sim/rogue"
 	"github.com/wowsims/tbc/sim/shaman/elemental"
 	"github.com/wowsims/tbc/sim/shaman/enhancement"
+	restoShaman "github.com/wowsims/tbc/sim/shaman/restoration"
 	"github.com/wowsims/tbc/sim/warlock"
 	dpsWarrior "github.com/wowsims/tbc/sim/warrior/dps"
 	protectionWarrior "github.com/wowsims/tbc/sim/warrior/protection"
@@ -31,8 +35,10 @@ func RegisterAll() {
 	balance.RegisterBalanceDruid()
 	feral.RegisterFeralDruid()
 	feralTank.RegisterFeralTankDruid()
+	restoDruid.RegisterRestorationDruid()
 	elemental.RegisterElementalShaman()
 	enhancement.RegisterEnhancementShaman()
+	restoShaman.RegisterRestorationShaman()
 	hunter.RegisterHunter()
 	mage.RegisterMage()
 	shadow.RegisterShadowPriest()
@@ -41,6 +47,8 @@ func RegisterAll() {
 	protectionWarrior.RegisterProtectionWarrior()
 	retribution.RegisterRetributionPaladin()
 	protectionPaladin.RegisterProtectionPaladin()
+	holyPaladin.RegisterHolyPaladin()
 	smite.RegisterSmitePriest()
+	healingPriest.RegisterHealingPriest()
 	warlock.RegisterWarlock()
 }
@@ -0,0 +1,66 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+// Chain Heal always lands on the heal target first, then jumps to the next
+// players in the raid.
+func (shaman *Shaman) RegisterChainHealSpell(healTarget *core.Unit) {
+	baseCost := 540.0 * (1 - 0.01*float64(shaman.Talents.TidalFocus))
+
+	effect := shaman.newHealingSpellEffect(553, 623, 0.714)
+	effect.DamageMultiplier *= 1 + 0.1*float64(shaman.Talents.ImprovedChainHeal)
+
+	effects := []core.SpellEffect{effect}
+	effects[0].Target = healTarget
+	for _, unit := range shaman.Env.Raid.AllUnits {
+		if len(effects) == 3 {
+			break
+		}
+		if unit == healTarget || unit.Type != core.PlayerUnit {
+			continue
+		}
+
+		bounceEffect := effects[len(effects)-1] // Makes a copy of the previous bounce
+		bounceEffect.Target = unit
+		bounceEffect.DamageMultiplier *= 0.5
+		effects = append(effects, bounceEffect)
+	}
+
+	shaman.ChainHeal = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 25423},
+		SpellSchool: core.SpellSchoolNature,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncHealingMultiple(effects),
+	})
+}
+
+func (shaman *Shaman) newHealingSpellEffect(minHealing float64, maxHealing float64, spellCoefficient float64) core.SpellEffect {
+	return core.SpellEffect{
+		ProcMask: core.ProcMaskSpellHealing,
+
+		BonusSpellCritRating: float64(shaman.Talents.TidalMastery) * 1 * core.SpellCritRatingPerCritChance,
+
+		DamageMultiplier: 1 + 0.02*float64(shaman.Talents.Purification),
+		ThreatMultiplier: 1,
+
+		BaseDamage:     core.BaseHealingConfigHealing(minHealing, maxHealing, spellCoefficient),
+		OutcomeApplier: shaman.OutcomeFuncMagicCrit(1.5),
+	}
+}
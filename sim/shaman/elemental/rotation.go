@@ -18,7 +18,7 @@ func (eleShaman *ElementalShaman) OnGCDReady(sim *core.Simulation) {
 }
 
 func (eleShaman *ElementalShaman) OnManaTick(sim *core.Simulation) {
-	if eleShaman.FinishedWaitingForManaAndGCDReady(sim) {
+	if !eleShaman.HasAPLRotation() && eleShaman.FinishedWaitingForManaAndGCDReady(sim) {
 		eleShaman.tryUseGCD(sim)
 	}
 }
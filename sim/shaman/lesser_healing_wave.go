@@ -0,0 +1,31 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/stats"
+)
+
+func (shaman *Shaman) RegisterLesserHealingWaveSpell() {
+	baseCost := 440.0 * (1 - 0.01*float64(shaman.Talents.TidalFocus))
+
+	shaman.LesserHealingWave = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 25420},
+		SpellSchool: core.SpellSchoolNature,
+		SpellExtras: core.SpellExtrasHealing,
+
+		ResourceType: stats.Mana,
+		BaseCost:     baseCost,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				Cost:     baseCost,
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		ApplyEffects: core.ApplyEffectFuncDirectHealing(shaman.newHealingSpellEffect(1039, 1186, 0.429)),
+	})
+}
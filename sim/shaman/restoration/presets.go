@@ -0,0 +1,132 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/core/items"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+var StandardTalents = &proto.ShamanTalents{
+	AncestralKnowledge:   5,
+	ShieldSpecialization: 5,
+
+	ImprovedHealingWave: 5,
+	TidalFocus:          5,
+	TotemicFocus:        5,
+	NaturesGuidance:     3,
+	RestorativeTotems:   5,
+	TidalMastery:        5,
+	NaturesSwiftness:    true,
+	Purification:        5,
+	ManaTideTotem:       true,
+	ImprovedChainHeal:   2,
+	NaturesBlessing:     3,
+}
+
+var BasicTotems = &proto.ShamanTotems{
+	Earth: proto.EarthTotem_TremorTotem,
+	Air:   proto.AirTotem_WrathOfAirTotem,
+	Water: proto.WaterTotem_ManaSpringTotem,
+}
+
+var restoShamOptions = &proto.RestorationShaman_Options{
+	WaterShield: true,
+	Bloodlust:   true,
+}
+
+var PlayerOptionsAdaptive = &proto.Player_RestorationShaman{
+	RestorationShaman: &proto.RestorationShaman{
+		Talents: StandardTalents,
+		Options: restoShamOptions,
+		Rotation: &proto.RestorationShaman_Rotation{
+			Totems:       BasicTotems,
+			PrimarySpell: proto.RestorationShaman_Rotation_Adaptive,
+		},
+	},
+}
+
+var PlayerOptionsChainHeal = &proto.Player_RestorationShaman{
+	RestorationShaman: &proto.RestorationShaman{
+		Talents: StandardTalents,
+		Options: restoShamOptions,
+		Rotation: &proto.RestorationShaman_Rotation{
+			Totems:       BasicTotems,
+			PrimarySpell: proto.RestorationShaman_Rotation_ChainHeal,
+		},
+	},
+}
+
+var FullRaidBuffs = &proto.RaidBuffs{
+	ArcaneBrilliance: true,
+	GiftOfTheWild:    proto.TristateEffect_TristateEffectImproved,
+	DivineSpirit:     proto.TristateEffect_TristateEffectImproved,
+}
+var FullPartyBuffs = &proto.PartyBuffs{
+	MoonkinAura: proto.TristateEffect_TristateEffectRegular,
+}
+var FullIndividualBuffs = &proto.IndividualBuffs{
+	BlessingOfKings:  true,
+	BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+}
+
+var FullConsumes = &proto.Consumes{
+	Flask:              proto.Flask_FlaskOfMightyRestoration,
+	Food:               proto.Food_FoodBlackenedBasilisk,
+	DefaultPotion:      proto.Potions_SuperManaPotion,
+	NumStartingPotions: 1,
+	DefaultConjured:    proto.Conjured_ConjuredDarkRune,
+}
+
+var FullDebuffs = &proto.Debuffs{
+	JudgementOfWisdom: true,
+}
+
+var P1Gear = items.EquipmentSpecFromJsonString(`{"items": [
+	{
+		"id": 30728
+	},
+	{
+		"id": 30726
+	},
+	{
+		"id": 28631
+	},
+	{
+		"id": 31329
+	},
+	{
+		"id": 28735
+	},
+	{
+		"id": 28503
+	},
+	{
+		"id": 28520
+	},
+	{
+		"id": 28567
+	},
+	{
+		"id": 28751
+	},
+	{
+		"id": 27549
+	},
+	{
+		"id": 30736
+	},
+	{
+		"id": 28763
+	},
+	{
+		"id": 29376
+	},
+	{
+		"id": 28590
+	},
+	{
+		"id": 28771
+	},
+	{
+		"id": 29274
+	}
+]}`)
@@ -0,0 +1,74 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/common"
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/shaman"
+)
+
+func RegisterRestorationShaman() {
+	core.RegisterAgentFactory(
+		proto.Player_RestorationShaman{},
+		proto.Spec_SpecRestorationShaman,
+		func(character core.Character, options proto.Player) core.Agent {
+			return NewRestorationShaman(character, options)
+		},
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_RestorationShaman)
+			if !ok {
+				panic("Invalid spec value for Restoration Shaman!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+func NewRestorationShaman(character core.Character, options proto.Player) *RestorationShaman {
+	restoShamOptions := options.GetRestorationShaman()
+
+	selfBuffs := shaman.SelfBuffs{
+		Bloodlust:   restoShamOptions.Options.Bloodlust,
+		WaterShield: restoShamOptions.Options.WaterShield,
+	}
+
+	totems := proto.ShamanTotems{}
+	if restoShamOptions.Rotation.Totems != nil {
+		totems = *restoShamOptions.Rotation.Totems
+	}
+
+	return &RestorationShaman{
+		Shaman:           shaman.NewShaman(character, *restoShamOptions.Talents, totems, selfBuffs),
+		primarySpell:     restoShamOptions.Rotation.PrimarySpell,
+		healTargetOption: restoShamOptions.Options.HealTarget,
+		manaTracker:      common.NewManaSpendingRateTracker(),
+	}
+}
+
+type RestorationShaman struct {
+	*shaman.Shaman
+
+	primarySpell proto.RestorationShaman_Rotation_PrimarySpell
+
+	healTargetOption *proto.RaidTarget
+	healTarget       *core.Character
+
+	manaTracker common.ManaSpendingRateTracker
+}
+
+func (restoShaman *RestorationShaman) GetShaman() *shaman.Shaman {
+	return restoShaman.Shaman
+}
+
+func (restoShaman *RestorationShaman) Initialize() {
+	restoShaman.Shaman.Initialize()
+
+	restoShaman.healTarget = restoShaman.GetHealTarget(restoShaman.healTargetOption)
+	restoShaman.RegisterChainHealSpell(&restoShaman.healTarget.Unit)
+	restoShaman.RegisterLesserHealingWaveSpell()
+}
+
+func (restoShaman *RestorationShaman) Reset(sim *core.Simulation) {
+	restoShaman.Shaman.Reset(sim)
+	restoShaman.manaTracker.Reset()
+}
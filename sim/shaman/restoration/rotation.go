@@ -0,0 +1,44 @@
+package restoration
+
+import (
+	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/proto"
+)
+
+func (restoShaman *RestorationShaman) OnGCDReady(sim *core.Simulation) {
+	restoShaman.tryUseGCD(sim)
+}
+
+func (restoShaman *RestorationShaman) OnManaTick(sim *core.Simulation) {
+	if !restoShaman.HasAPLRotation() && restoShaman.FinishedWaitingForManaAndGCDReady(sim) {
+		restoShaman.tryUseGCD(sim)
+	}
+}
+
+func (restoShaman *RestorationShaman) tryUseGCD(sim *core.Simulation) {
+	if restoShaman.TryDropTotems(sim) {
+		return
+	}
+
+	var spell *core.Spell
+	switch restoShaman.primarySpell {
+	case proto.RestorationShaman_Rotation_ChainHeal:
+		spell = restoShaman.ChainHeal
+	case proto.RestorationShaman_Rotation_LesserHealingWave:
+		spell = restoShaman.LesserHealingWave
+	default:
+		restoShaman.manaTracker.Update(sim, restoShaman.GetCharacter())
+
+		// Lesser Healing Wave heals the target faster, but Chain Heal is much
+		// more mana efficient.
+		if restoShaman.manaTracker.ProjectedManaSurplus(sim, restoShaman.GetCharacter()) {
+			spell = restoShaman.LesserHealingWave
+		} else {
+			spell = restoShaman.ChainHeal
+		}
+	}
+
+	if success := spell.Cast(sim, &restoShaman.healTarget.Unit); !success {
+		restoShaman.WaitForMana(sim, spell.CurCast.Cost)
+	}
+}
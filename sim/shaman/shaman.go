@@ -121,6 +121,9 @@ type Shaman struct {
 
 	Stormstrike *core.Spell
 
+	ChainHeal         *core.Spell
+	LesserHealingWave *core.Spell
+
 	EarthShock *core.Spell
 	FlameShock *core.Spell
 	FrostShock *core.Spell
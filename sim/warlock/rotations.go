@@ -13,7 +13,7 @@ func (warlock *Warlock) OnGCDReady(sim *core.Simulation) {
 }
 
 func (warlock *Warlock) OnManaTick(sim *core.Simulation) {
-	if warlock.FinishedWaitingForManaAndGCDReady(sim) {
+	if !warlock.HasAPLRotation() && warlock.FinishedWaitingForManaAndGCDReady(sim) {
 		warlock.tryUseGCD(sim)
 	}
 }
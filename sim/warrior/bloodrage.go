@@ -25,6 +25,7 @@ func (warrior *Warrior) registerBloodrageCD() {
 			warrior.AddRage(sim, instantRage, actionID)
 
 			core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Unit:     &warrior.Unit,
 				NumTicks: 10,
 				Period:   time.Second * 1,
 				OnAction: func(sim *core.Simulation) {
@@ -99,3 +99,45 @@ func BenchmarkSimulate(b *testing.B) {
 
 	core.RaidBenchmark(b, rsr)
 }
+
+func TestAPLRotationReplacesRotation(t *testing.T) {
+	bloodthirst := core.ActionID{SpellID: 30335}
+	player := core.WithSpec(&proto.Player{
+		Race:      proto.Race_RaceOrc,
+		Class:     proto.Class_ClassWarrior,
+		Equipment: FuryP1Gear,
+		AplRotation: &proto.APLRotation{
+			PriorityList: []*proto.APLAction{{ActionId: bloodthirst.ToProto()}},
+		},
+	}, PlayerOptionsFury)
+
+	rsr := &proto.RaidSimRequest{
+		Raid:      core.SinglePlayerRaidProto(player, FullPartyBuffs, FullRaidBuffs, FullDebuffs),
+		Encounter: core.MakeSingleTargetEncounter(0),
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+		},
+	}
+	result := core.RunRaidSim(rsr)
+	if result.ErrorMsg != "" {
+		t.Fatalf("Sim failed: %s", result.ErrorMsg)
+	}
+
+	casts := map[core.ActionID]int32{}
+	for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+		for _, target := range action.Targets {
+			casts[core.ActionID{SpellID: action.Id.GetSpellId()}] += target.Casts
+		}
+	}
+	if casts[bloodthirst] == 0 {
+		t.Fatalf("Expected Bloodthirst casts from the APL")
+	}
+	// Heroic Strike is queued on auto attacks and Whirlwind is used on rage
+	// gains by the built-in rotation, which the APL replaces.
+	for _, actionID := range []core.ActionID{{SpellID: 29707}, {SpellID: 1680}} {
+		if casts[actionID] != 0 {
+			t.Errorf("Expected no %s casts outside of the APL, got %d", actionID, casts[actionID])
+		}
+	}
+}
@@ -45,6 +45,9 @@ func NewProtectionWarrior(character core.Character, options proto.Player) *Prote
 	}
 
 	war.EnableRageBar(warOptions.Options.StartingRage, core.TernaryFloat64(war.Talents.EndlessRage, 1.25, 1), func(sim *core.Simulation) {
+		if war.HasAPLRotation() {
+			return
+		}
 		if war.GCD.IsReady(sim) {
 			war.TryUseCooldowns(sim)
 			if war.GCD.IsReady(sim) {
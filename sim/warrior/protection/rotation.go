@@ -10,6 +10,9 @@ func (war *ProtectionWarrior) OnGCDReady(sim *core.Simulation) {
 }
 
 func (war *ProtectionWarrior) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
+	if war.HasAPLRotation() {
+		return
+	}
 	war.tryQueueHsCleave(sim)
 }
 
@@ -83,6 +83,7 @@ func (warrior *Warrior) applyAngerManagement() {
 
 	warrior.RegisterResetEffect(func(sim *core.Simulation) {
 		core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			Unit:   &warrior.Unit,
 			Period: time.Second * 3,
 			OnAction: func(sim *core.Simulation) {
 				warrior.AddRage(sim, 1, core.ActionID{SpellID: 12296})
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"syscall/js"
 
@@ -22,10 +23,17 @@ func main() {
 
 	js.Global().Set("computeStats", js.FuncOf(computeStats))
 	js.Global().Set("gearList", js.FuncOf(gearList))
+	js.Global().Set("unimplementedItems", js.FuncOf(unimplementedItems))
 	js.Global().Set("raidSim", js.FuncOf(raidSim))
 	js.Global().Set("raidSimAsync", js.FuncOf(raidSimAsync))
 	js.Global().Set("statWeights", js.FuncOf(statWeights))
 	js.Global().Set("statWeightsAsync", js.FuncOf(statWeightsAsync))
+	js.Global().Set("gearOptimize", js.FuncOf(gearOptimize))
+	js.Global().Set("gearOptimizeAsync", js.FuncOf(gearOptimizeAsync))
+	js.Global().Set("cooldownOptimize", js.FuncOf(cooldownOptimize))
+	js.Global().Set("cooldownOptimizeAsync", js.FuncOf(cooldownOptimizeAsync))
+	js.Global().Set("talentOptimize", js.FuncOf(talentOptimize))
+	js.Global().Set("talentOptimizeAsync", js.FuncOf(talentOptimizeAsync))
 	js.Global().Call("wasmready")
 	<-c
 }
@@ -70,6 +78,26 @@ func gearList(this js.Value, args []js.Value) interface{} {
 	return outArray
 }
 
+func unimplementedItems(this js.Value, args []js.Value) interface{} {
+	uir := &proto.UnimplementedItemsRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), uir); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.GetUnimplementedItems(uir)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
 func raidSim(this js.Value, args []js.Value) interface{} {
 	rsr := &proto.RaidSimRequest{}
 	if err := googleProto.Unmarshal(getArgsBinary(args[0]), rsr); err != nil {
@@ -97,7 +125,7 @@ func raidSimAsync(this js.Value, args []js.Value) interface{} {
 		return nil
 	}
 	reporter := make(chan *proto.ProgressMetrics, 100)
-	core.RunRaidSimAsync(rsr, reporter)
+	core.RunRaidSimAsync(context.Background(), rsr, reporter)
 
 	result := processAsyncProgress(args[1], reporter)
 	close(reporter)
@@ -131,7 +159,109 @@ func statWeightsAsync(this js.Value, args []js.Value) interface{} {
 		return nil
 	}
 	reporter := make(chan *proto.ProgressMetrics, 100)
-	core.StatWeightsAsync(rsr, reporter)
+	core.StatWeightsAsync(context.Background(), rsr, reporter)
+
+	result := processAsyncProgress(args[1], reporter)
+	close(reporter)
+	return result
+}
+
+func gearOptimize(this js.Value, args []js.Value) interface{} {
+	gor := &proto.GearOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), gor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.GearOptimize(gor)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
+func gearOptimizeAsync(this js.Value, args []js.Value) interface{} {
+	gor := &proto.GearOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), gor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	core.GearOptimizeAsync(context.Background(), gor, reporter)
+
+	result := processAsyncProgress(args[1], reporter)
+	close(reporter)
+	return result
+}
+
+func cooldownOptimize(this js.Value, args []js.Value) interface{} {
+	cor := &proto.CooldownOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), cor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.CooldownOptimize(cor)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
+func cooldownOptimizeAsync(this js.Value, args []js.Value) interface{} {
+	cor := &proto.CooldownOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), cor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	core.CooldownOptimizeAsync(context.Background(), cor, reporter)
+
+	result := processAsyncProgress(args[1], reporter)
+	close(reporter)
+	return result
+}
+
+func talentOptimize(this js.Value, args []js.Value) interface{} {
+	tor := &proto.TalentOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), tor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.TalentOptimize(tor)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
+func talentOptimizeAsync(this js.Value, args []js.Value) interface{} {
+	tor := &proto.TalentOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), tor); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	core.TalentOptimizeAsync(context.Background(), tor, reporter)
 
 	result := processAsyncProgress(args[1], reporter)
 	close(reporter)
@@ -163,7 +293,7 @@ reader:
 			js.CopyBytesToJS(outArray, outbytes)
 			progFunc.Invoke(outArray)
 
-			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil {
+			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalGearOptimizeResult != nil || progMetric.FinalCooldownOptimizeResult != nil || progMetric.FinalTalentOptimizeResult != nil {
 				return outArray
 			}
 		}
@@ -2,14 +2,18 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"runtime/pprof"
 	"strings"
 	"sync"
@@ -23,6 +27,7 @@ import (
 	"github.com/wowsims/tbc/sim/core"
 	proto "github.com/wowsims/tbc/sim/core/proto"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	googleProto "google.golang.org/protobuf/proto"
 )
 
@@ -33,6 +38,9 @@ func init() {
 var (
 	Version  string
 	outdated int
+
+	// Number of worker sims used for raid sim requests which don't set one.
+	defaultConcurrency int32
 )
 
 func main() {
@@ -45,9 +53,14 @@ func main() {
 	var host = flag.String("host", ":3333", "URL to host the interface on.")
 	var launch = flag.Bool("launch", true, "auto launch browser")
 	var skipVersionCheck = flag.Bool("nvc", false, "set true to skip version check")
+	var concurrency = flag.Int("concurrency", runtime.NumCPU(), "Number of worker sims to split raid sim iterations across.")
+	var maxJobs = flag.Int("max_jobs", 4, "Max number of async requests to run at once, 0 for no limit. Later requests are queued.")
+	var jobTTL = flag.Duration("job_ttl", 5*time.Minute, "Async requests which aren't polled for this long are cancelled, or 0 to never cancel them.")
 
 	flag.Parse()
 
+	defaultConcurrency = int32(*concurrency)
+
 	fmt.Printf("Version: %s\n", Version)
 	if !*skipVersionCheck && Version != "development" {
 		go func() {
@@ -74,66 +87,229 @@ func main() {
 		}()
 	}
 
-	setupAsyncServer()
+	setupAsyncServer(*maxJobs, *jobTTL)
 	runServer(*useFS, *host, *launch, *simName, *wasm, bufio.NewReader(os.Stdin))
 }
 
-type simProgReportCreator func() (string, progReport)
-type progReport func(progMetric *proto.ProgressMetrics)
 type asyncAPIHandler struct {
-	msg    func() googleProto.Message
-	handle func(googleProto.Message, chan *proto.ProgressMetrics)
+	msg func() googleProto.Message
+	// The handler should stop early and send a final result when ctx is cancelled.
+	handle func(context.Context, googleProto.Message, chan *proto.ProgressMetrics)
 }
 
 var asyncAPIHandlers = map[string]asyncAPIHandler{
-	"/raidSimAsync": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
-		core.RunRaidSimAsync(msg.(*proto.RaidSimRequest), reporter)
+	"/raidSimAsync": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(ctx context.Context, msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
+		core.RunRaidSimAsync(ctx, withDefaultConcurrency(msg.(*proto.RaidSimRequest)), reporter)
+	}},
+	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(ctx context.Context, msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
+		core.StatWeightsAsync(ctx, msg.(*proto.StatWeightsRequest), reporter)
+	}},
+	"/gearOptimizeAsync": {msg: func() googleProto.Message { return &proto.GearOptimizeRequest{} }, handle: func(ctx context.Context, msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
+		core.GearOptimizeAsync(ctx, msg.(*proto.GearOptimizeRequest), reporter)
 	}},
-	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
-		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter)
+	"/cooldownOptimizeAsync": {msg: func() googleProto.Message { return &proto.CooldownOptimizeRequest{} }, handle: func(ctx context.Context, msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
+		core.CooldownOptimizeAsync(ctx, msg.(*proto.CooldownOptimizeRequest), reporter)
 	}},
+	"/talentOptimizeAsync": {msg: func() googleProto.Message { return &proto.TalentOptimizeRequest{} }, handle: func(ctx context.Context, msg googleProto.Message, reporter chan *proto.ProgressMetrics) {
+		core.TalentOptimizeAsync(ctx, msg.(*proto.TalentOptimizeRequest), reporter)
+	}},
+}
+
+// Fills in the server's worker count for requests that don't specify one.
+func withDefaultConcurrency(rsr *proto.RaidSimRequest) *proto.RaidSimRequest {
+	if rsr.SimOptions != nil && rsr.SimOptions.Concurrency == 0 {
+		rsr.SimOptions.Concurrency = defaultConcurrency
+	}
+	return rsr
 }
 
-func handleAsyncAPI(w http.ResponseWriter, r *http.Request, addNewSim simProgReportCreator) {
+func isFinalProgress(progress *proto.ProgressMetrics) bool {
+	return progress.FinalRaidResult != nil || progress.FinalWeightResult != nil || progress.FinalGearOptimizeResult != nil || progress.FinalCooldownOptimizeResult != nil || progress.FinalTalentOptimizeResult != nil
+}
+
+type asyncJob struct {
+	mut            sync.Mutex
+	latestProgress *proto.ProgressMetrics
+	lastPolled     time.Time
+	cancel         context.CancelFunc
+
+	// Receives every progress update of a streamed job, and is closed once the
+	// job is done. Nil for polled jobs.
+	stream chan *proto.ProgressMetrics
+}
+
+func (job *asyncJob) report(ctx context.Context, progress *proto.ProgressMetrics) {
+	job.mut.Lock()
+	job.latestProgress = progress
+	job.mut.Unlock()
+
+	if job.stream != nil {
+		select {
+		case job.stream <- progress:
+		case <-ctx.Done():
+			// Nobody is listening anymore.
+		}
+	}
+}
+
+// Returns the latest progress, and marks the job as polled.
+func (job *asyncJob) poll() *proto.ProgressMetrics {
+	job.mut.Lock()
+	defer job.mut.Unlock()
+	job.lastPolled = time.Now()
+	return job.latestProgress
+}
+
+// Tracks the running async jobs by progress id.
+type asyncJobs struct {
+	mut  sync.RWMutex
+	jobs map[string]*asyncJob
+
+	// Holds a value for each running job, or nil if there is no limit.
+	slots chan struct{}
+
+	// Jobs which aren't polled for this long are cancelled and forgotten.
+	ttl time.Duration
+}
+
+func newAsyncJobs(maxJobs int, ttl time.Duration) *asyncJobs {
+	jobs := &asyncJobs{
+		jobs: map[string]*asyncJob{},
+		ttl:  ttl,
+	}
+	if maxJobs > 0 {
+		jobs.slots = make(chan struct{}, maxJobs)
+	}
+	return jobs
+}
+
+// Adds a new job. If stream is set, the job will send every progress update to it.
+func (jobs *asyncJobs) add(cancel context.CancelFunc, stream chan *proto.ProgressMetrics) (string, *asyncJob) {
+	id := uuid.NewV4().String()
+	job := &asyncJob{
+		latestProgress: &proto.ProgressMetrics{Queued: true},
+		lastPolled:     time.Now(),
+		cancel:         cancel,
+		stream:         stream,
+	}
+	jobs.mut.Lock()
+	jobs.jobs[id] = job
+	jobs.mut.Unlock()
+	return id, job
+}
+
+func (jobs *asyncJobs) get(id string) (*asyncJob, bool) {
+	jobs.mut.RLock()
+	defer jobs.mut.RUnlock()
+	job, ok := jobs.jobs[id]
+	return job, ok
+}
+
+// Removes a job, cancelling it if it is still running.
+func (jobs *asyncJobs) remove(id string) bool {
+	jobs.mut.Lock()
+	job, ok := jobs.jobs[id]
+	delete(jobs.jobs, id)
+	jobs.mut.Unlock()
+	if ok {
+		job.cancel()
+	}
+	return ok
+}
+
+// Removes the jobs which haven't been polled within the ttl. Streamed jobs are
+// kept, since they end with their connection instead.
+func (jobs *asyncJobs) evictExpired(now time.Time) {
+	var expired []string
+	jobs.mut.RLock()
+	for id, job := range jobs.jobs {
+		job.mut.Lock()
+		if job.stream == nil && now.Sub(job.lastPolled) > jobs.ttl {
+			expired = append(expired, id)
+		}
+		job.mut.Unlock()
+	}
+	jobs.mut.RUnlock()
+
+	for _, id := range expired {
+		log.Printf("Evicting abandoned async job: %s", id)
+		jobs.remove(id)
+	}
+}
+
+// Runs a job once a slot is free, reporting its progress until it finishes.
+func (jobs *asyncJobs) run(ctx context.Context, job *asyncJob, handler asyncAPIHandler, msg googleProto.Message) {
+	defer job.cancel()
+	if job.stream != nil {
+		defer close(job.stream)
+	}
+
+	if jobs.slots != nil {
+		select {
+		case jobs.slots <- struct{}{}:
+		default:
+			job.report(ctx, &proto.ProgressMetrics{Queued: true})
+			select {
+			case jobs.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		defer func() { <-jobs.slots }()
+	}
+	// Clears the queued status.
+	job.report(ctx, &proto.ProgressMetrics{})
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	handler.handle(ctx, msg, reporter)
+
+	// Keep reading even after a cancel, so the handler isn't blocked and the
+	// slot is only released once it has stopped.
+	for progMetric := range reporter {
+		job.report(ctx, progMetric)
+		if isFinalProgress(progMetric) {
+			return
+		}
+	}
+}
+
+// Reads the request for an async endpoint, writing an error status if it fails.
+func readAsyncRequest(w http.ResponseWriter, r *http.Request, endpoint string) (asyncAPIHandler, googleProto.Message, bool) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return
+		return asyncAPIHandler{}, nil, false
 	}
-	endpoint := r.URL.Path
+	return parseAsyncRequest(w, endpoint, body, googleProto.Unmarshal)
+}
+
+// Parses the request for an async endpoint with the given unmarshal func,
+// writing an error status if it fails.
+func parseAsyncRequest(w http.ResponseWriter, endpoint string, data []byte, unmarshal func([]byte, googleProto.Message) error) (asyncAPIHandler, googleProto.Message, bool) {
 	handler, ok := asyncAPIHandlers[endpoint]
 	if !ok {
 		log.Printf("Invalid Endpoint: %s", endpoint)
 		w.WriteHeader(http.StatusNotFound)
-		return
+		return asyncAPIHandler{}, nil, false
 	}
 
 	msg := handler.msg()
-	if err := googleProto.Unmarshal(body, msg); err != nil {
+	if err := unmarshal(data, msg); err != nil {
 		log.Printf("Failed to parse request: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return asyncAPIHandler{}, nil, false
 	}
+	return handler, msg, true
+}
 
-	reporter := make(chan *proto.ProgressMetrics, 100)
-	handler.handle(msg, reporter)
+func handleAsyncAPI(w http.ResponseWriter, r *http.Request, jobs *asyncJobs) {
+	handler, msg, ok := readAsyncRequest(w, r, r.URL.Path)
+	if !ok {
+		return
+	}
 
-	id, report := addNewSim()
-	go func() {
-		for {
-			// TODO: cleanup so we dont collect these
-			select {
-			case progMetric, ok := <-reporter:
-				if !ok {
-					return
-				}
-				report(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil {
-					close(reporter)
-					return
-				}
-			}
-		}
-	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	id, job := jobs.add(cancel, nil)
+	go jobs.run(ctx, job, handler, msg)
 
 	protoResult := &proto.AsyncAPIResult{
 		ProgressId: id,
@@ -150,69 +326,171 @@ func handleAsyncAPI(w http.ResponseWriter, r *http.Request, addNewSim simProgRep
 	w.Write(outbytes)
 }
 
-func setupAsyncServer() {
-	type asyncProgress struct {
-		mut            sync.Mutex
-		latestProgress proto.ProgressMetrics
-	}
-	progresses := map[string]*asyncProgress{}
-	progMut := &sync.RWMutex{}
-	addNewSim := func() (string, progReport) {
-		newID := uuid.NewV4().String()
-		progMut.Lock()
-		progresses[newID] = &asyncProgress{}
-		progMut.Unlock()
-
-		return newID, func(newProg *proto.ProgressMetrics) {
-			progresses[newID].mut.Lock()
-			progresses[newID].latestProgress = *newProg
-			progresses[newID].mut.Unlock()
+// Returns the streaming endpoint for an async endpoint, e.g. /raidSimStream for /raidSimAsync.
+func streamEndpoint(asyncEndpoint string) string {
+	return strings.TrimSuffix(asyncEndpoint, "Async") + "Stream"
+}
+
+// Writes a single ProgressMetrics message of a stream as a Server-Sent Event.
+// With protobuf framing the event data is the base64 encoded message, and with
+// protojson framing it is the JSON message, which never contains newlines.
+func writeStreamEvent(w io.Writer, progress *proto.ProgressMetrics, useJSON bool) error {
+	var data string
+	if useJSON {
+		jsonBytes, err := protojson.Marshal(progress)
+		if err != nil {
+			return err
+		}
+		data = string(jsonBytes)
+	} else {
+		protoBytes, err := googleProto.Marshal(progress)
+		if err != nil {
+			return err
 		}
+		data = base64.StdEncoding.EncodeToString(protoBytes)
 	}
-	type progReport func(progMetric *proto.ProgressMetrics)
+	_, err := fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
 
-	http.HandleFunc("/statWeightsAsync", func(w http.ResponseWriter, r *http.Request) {
-		handleAsyncAPI(w, r, addNewSim)
-	})
-	http.HandleFunc("/raidSimAsync", func(w http.ResponseWriter, r *http.Request) {
-		handleAsyncAPI(w, r, addNewSim)
-	})
-	http.HandleFunc("/asyncProgress", func(w http.ResponseWriter, r *http.Request) {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return
+// Reads the request for a streaming endpoint. POST requests send it as the
+// body like the async endpoints, while GET requests (e.g. from an EventSource)
+// send it in the "request" query param, base64 encoded or as protojson.
+func readStreamRequest(w http.ResponseWriter, r *http.Request, asyncEndpoint string, useJSON bool) (asyncAPIHandler, googleProto.Message, bool) {
+	if r.Method != http.MethodGet {
+		return readAsyncRequest(w, r, asyncEndpoint)
+	}
+
+	request := r.URL.Query().Get("request")
+	if useJSON {
+		return parseAsyncRequest(w, asyncEndpoint, []byte(request), protojson.Unmarshal)
+	}
+	data, err := base64.StdEncoding.DecodeString(request)
+	if err != nil {
+		log.Printf("Failed to decode request: %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return asyncAPIHandler{}, nil, false
+	}
+	return parseAsyncRequest(w, asyncEndpoint, data, googleProto.Unmarshal)
+}
+
+// Runs an async request like handleAsyncAPI, but sends every progress update
+// as a Server-Sent Event as it happens instead of returning a progress id.
+// The event with the final result is the last one, after which clients should
+// close the stream; closing the connection cancels the request. Uses protojson
+// framing if the format query param is "json".
+func handleAsyncStream(w http.ResponseWriter, r *http.Request, jobs *asyncJobs, asyncEndpoint string) {
+	useJSON := r.URL.Query().Get("format") == "json"
+	handler, msg, ok := readStreamRequest(w, r, asyncEndpoint, useJSON)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	stream := make(chan *proto.ProgressMetrics, 100)
+	id, job := jobs.add(cancel, stream)
+	defer jobs.remove(id)
+	go jobs.run(ctx, job, handler, msg)
+
+	w.Header().Add("Content-Type", "text/event-stream")
+	w.Header().Add("Cache-Control", "no-cache")
+	// Lets the client cancel the request through /asyncCancel too.
+	w.Header().Add("X-Progress-Id", id)
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	writeFailed := false
+	for progress := range stream {
+		if writeFailed || r.Context().Err() != nil {
+			// The client is gone, but keep draining until the job stops.
+			continue
 		}
-		msg := &proto.AsyncAPIResult{}
-		if err := googleProto.Unmarshal(body, msg); err != nil {
-			log.Printf("Failed to parse request: %s", err.Error())
-			w.WriteHeader(http.StatusInternalServerError)
+		if err := writeStreamEvent(w, progress, useJSON); err != nil {
+			log.Printf("Failed to write stream: %s", err.Error())
+			writeFailed = true
+			cancel()
+			continue
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// Reads the AsyncAPIResult which identifies a job from a request body.
+func readProgressId(w http.ResponseWriter, r *http.Request) (string, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", false
+	}
+	msg := &proto.AsyncAPIResult{}
+	if err := googleProto.Unmarshal(body, msg); err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return "", false
+	}
+	return msg.ProgressId, true
+}
+
+// Registers the async endpoints. At most maxJobs async requests run at once
+// (no limit if 0), and later ones are queued. Jobs which aren't polled for
+// jobTTL are cancelled (never if 0).
+func setupAsyncServer(maxJobs int, jobTTL time.Duration) {
+	jobs := newAsyncJobs(maxJobs, jobTTL)
+
+	if jobTTL > 0 {
+		go func() {
+			for now := range time.Tick(jobTTL / 2) {
+				jobs.evictExpired(now)
+			}
+		}()
+	}
+
+	for endpoint := range asyncAPIHandlers {
+		asyncEndpoint := endpoint
+		http.HandleFunc(asyncEndpoint, func(w http.ResponseWriter, r *http.Request) {
+			handleAsyncAPI(w, r, jobs)
+		})
+		http.HandleFunc(streamEndpoint(asyncEndpoint), func(w http.ResponseWriter, r *http.Request) {
+			handleAsyncStream(w, r, jobs, asyncEndpoint)
+		})
+	}
+	http.HandleFunc("/asyncProgress", func(w http.ResponseWriter, r *http.Request) {
+		progressId, ok := readProgressId(w, r)
+		if !ok {
 			return
 		}
 
-		progMut.RLock()
-		progress, ok := progresses[msg.ProgressId]
-		progMut.RUnlock()
+		job, ok := jobs.get(progressId)
 		if !ok {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
-		progress.mut.Lock()
-		latest := progress.latestProgress
-		progress.mut.Unlock()
-		outbytes, err := googleProto.Marshal(&latest)
+		latest := job.poll()
+		outbytes, err := googleProto.Marshal(latest)
 		if err != nil {
 			log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil {
-			progMut.Lock()
-			delete(progresses, msg.ProgressId)
-			progMut.Unlock()
+		if isFinalProgress(latest) {
+			jobs.remove(progressId)
 		}
 		w.Header().Add("Content-Type", "application/x-protobuf")
 		w.Write(outbytes)
 	})
+	http.HandleFunc("/asyncCancel", func(w http.ResponseWriter, r *http.Request) {
+		progressId, ok := readProgressId(w, r)
+		if !ok {
+			return
+		}
+
+		if !jobs.remove(progressId) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
 }
 
 func runServer(useFS bool, host string, launchBrowser bool, simName string, wasm bool, inputReader *bufio.Reader) {
@@ -234,6 +512,10 @@ func runServer(useFS bool, host string, launchBrowser bool, simName string, wasm
 	http.HandleFunc("/individualSim", handleAPI)
 	http.HandleFunc("/raidSim", handleAPI)
 	http.HandleFunc("/gearList", handleAPI)
+	http.HandleFunc("/unimplementedItems", handleAPI)
+	http.HandleFunc("/gearOptimize", handleAPI)
+	http.HandleFunc("/cooldownOptimize", handleAPI)
+	http.HandleFunc("/talentOptimize", handleAPI)
 	http.HandleFunc("/", func(resp http.ResponseWriter, req *http.Request) {
 		resp.Header().Add("Cache-Control", "no-cache")
 		if strings.HasSuffix(req.URL.Path, "/tbc/") {
@@ -339,7 +621,7 @@ type apiHandler struct {
 // Handlers to decode and handle each proto function
 var handlers = map[string]apiHandler{
 	"/raidSim": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
-		return core.RunRaidSim(msg.(*proto.RaidSimRequest))
+		return core.RunRaidSim(withDefaultConcurrency(msg.(*proto.RaidSimRequest)))
 	}},
 	"/statWeights": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.StatWeights(msg.(*proto.StatWeightsRequest))
@@ -350,6 +632,18 @@ var handlers = map[string]apiHandler{
 	"/gearList": {msg: func() googleProto.Message { return &proto.GearListRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.GetGearList(msg.(*proto.GearListRequest))
 	}},
+	"/unimplementedItems": {msg: func() googleProto.Message { return &proto.UnimplementedItemsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.GetUnimplementedItems(msg.(*proto.UnimplementedItemsRequest))
+	}},
+	"/gearOptimize": {msg: func() googleProto.Message { return &proto.GearOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.GearOptimize(msg.(*proto.GearOptimizeRequest))
+	}},
+	"/cooldownOptimize": {msg: func() googleProto.Message { return &proto.CooldownOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.CooldownOptimize(msg.(*proto.CooldownOptimizeRequest))
+	}},
+	"/talentOptimize": {msg: func() googleProto.Message { return &proto.TalentOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.TalentOptimize(msg.(*proto.TalentOptimizeRequest))
+	}},
 }
 
 // handleAPI is generic handler for any api function using protos.
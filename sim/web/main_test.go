@@ -3,14 +3,21 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/base64"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/wowsims/tbc/sim/core"
+	"github.com/wowsims/tbc/sim/core/items"
 	"github.com/wowsims/tbc/sim/core/proto"
+	"github.com/wowsims/tbc/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
 	googleProto "google.golang.org/protobuf/proto"
 )
 
@@ -61,6 +68,8 @@ var p1Equip = &proto.EquipmentSpec{
 }
 
 func init() {
+	// Only run one async request at a time, so queueing can be tested.
+	setupAsyncServer(1, time.Minute)
 	go func() {
 		runServer(true, ":3333", false, "", false, bufio.NewReader(bytes.NewBuffer([]byte{})))
 	}()
@@ -68,8 +77,34 @@ func init() {
 	time.Sleep(time.Second) // hack so we have time for server to startup. Probably could repeatedly curl the endpoint until it responds.
 }
 
+// Posts a proto request to the server, parsing the response into result if it
+// has a body. Returns the response status code.
+func postProto(t *testing.T, endpoint string, req googleProto.Message, result googleProto.Message) int {
+	msgBytes, err := googleProto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+
+	r, err := http.Post("http://localhost:3333"+endpoint, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read result body: %s", err.Error())
+	}
+	if result != nil && len(body) > 0 {
+		if err := googleProto.Unmarshal(body, result); err != nil {
+			t.Fatalf("Failed to parse result: %s", err.Error())
+		}
+	}
+	return r.StatusCode
+}
+
 // TestIndividualSim is just a smoke test to make sure the http server works as expected.
-//   Don't modify this test unless the proto defintions change and this no longer compiles.
+//
+//	Don't modify this test unless the proto defintions change and this no longer compiles.
 func TestIndividualSim(t *testing.T) {
 	req := &proto.RaidSimRequest{
 		Raid: core.SinglePlayerRaidProto(
@@ -119,3 +154,367 @@ func TestIndividualSim(t *testing.T) {
 
 	log.Printf("RESULT: %#v", rsr)
 }
+
+func TestGearOptimize(t *testing.T) {
+	weights := stats.Stats{stats.Intellect: 0.3, stats.SpellPower: 1, stats.SpellCrit: 0.8, stats.SpellHit: 1.2}
+	req := &proto.GearOptimizeRequest{
+		Player: &proto.Player{
+			Race:      proto.Race_RaceTroll10,
+			Class:     proto.Class_ClassShaman,
+			Equipment: p1Equip,
+			Spec:      basicSpec,
+		},
+		PartyBuffs: &proto.PartyBuffs{},
+		RaidBuffs:  &proto.RaidBuffs{},
+		Debuffs:    &proto.Debuffs{},
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				&proto.Target{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 500,
+			RandomSeed: 1,
+		},
+		ItemIds:       []int32{29172, 29367, 28793},
+		GemIds:        []int32{34220, 24030, 24056, 24059},
+		EnchantIds:    []int32{29191, 28909, 22555},
+		StatWeights:   weights[:],
+		SpellHitCap:   202,
+		NumCandidates: 3,
+	}
+
+	result := &proto.GearOptimizeResult{}
+	postProto(t, "/gearOptimize", req, result)
+
+	if result.ErrorMsg != "" {
+		t.Fatalf("Unexpected error: %s", result.ErrorMsg)
+	}
+	if len(result.Candidates) != 3 {
+		t.Fatalf("Expected 3 candidates, got %d", len(result.Candidates))
+	}
+	for i, candidate := range result.Candidates {
+		if i > 0 && candidate.SimScore.Avg > result.Candidates[i-1].SimScore.Avg {
+			t.Fatalf("Candidates not sorted by sim score")
+		}
+
+		equipment := items.ProtoToEquipment(*candidate.Equipment)
+		if equipment.MetaGem().ID != 34220 || !equipment.MetaGemActive() {
+			t.Fatalf("Expected an active meta gem in candidate %d", i)
+		}
+		if equipment[items.ItemSlotFinger1].ID == equipment[items.ItemSlotFinger2].ID {
+			t.Fatalf("Unique ring equipped twice in candidate %d", i)
+		}
+	}
+}
+
+func TestCooldownOptimize(t *testing.T) {
+	req := &proto.CooldownOptimizeRequest{
+		Player: &proto.Player{
+			Race:      proto.Race_RaceTroll10,
+			Class:     proto.Class_ClassShaman,
+			Equipment: p1Equip,
+			Spec:      basicSpec,
+		},
+		PartyBuffs: &proto.PartyBuffs{
+			Bloodlust: 1,
+		},
+		RaidBuffs: &proto.RaidBuffs{},
+		Debuffs:   &proto.Debuffs{},
+		Encounter: &proto.Encounter{
+			Duration:          120,
+			ExecuteProportion: 0.2,
+			Targets: []*proto.Target{
+				&proto.Target{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 500,
+			RandomSeed: 1,
+		},
+		TimingStep: 20,
+	}
+
+	result := &proto.CooldownOptimizeResult{}
+	postProto(t, "/cooldownOptimize", req, result)
+
+	if result.ErrorMsg != "" {
+		t.Fatalf("Unexpected error: %s", result.ErrorMsg)
+	}
+	if result.BaselineDps.Avg <= 0 || result.OptimizedDps.Avg <= 0 {
+		t.Fatalf("Expected dps from the confirmation sims")
+	}
+	if result.DpsGain != result.OptimizedDps.Avg-result.BaselineDps.Avg {
+		t.Fatalf("Expected dps gain to match the confirmation sims")
+	}
+	if result.DpsGain < 0 {
+		t.Fatalf("Expected the baseline cooldowns to be kept rather than lose dps, got gain %0.2f", result.DpsGain)
+	}
+	for _, cooldown := range result.Cooldowns.Cooldowns {
+		if core.ProtoToActionID(*cooldown.Id).SpellID == 2825 {
+			t.Fatalf("Bloodlust should not be optimized")
+		}
+	}
+	log.Printf("Cooldowns: %v, gain: %0.2f", result.Cooldowns, result.DpsGain)
+}
+
+func TestTalentOptimize(t *testing.T) {
+	req := &proto.TalentOptimizeRequest{
+		Player: &proto.Player{
+			Race:      proto.Race_RaceTroll10,
+			Class:     proto.Class_ClassShaman,
+			Equipment: p1Equip,
+			Spec:      basicSpec,
+		},
+		PartyBuffs: &proto.PartyBuffs{},
+		RaidBuffs:  &proto.RaidBuffs{},
+		Debuffs:    &proto.Debuffs{},
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				&proto.Target{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 100,
+			RandomSeed: 1,
+		},
+		Talents: []string{"concussion", "unrelenting_storm"},
+	}
+
+	result := &proto.TalentOptimizeResult{}
+	postProto(t, "/talentOptimize", req, result)
+
+	if result.ErrorMsg != "" {
+		t.Fatalf("Unexpected error: %s", result.ErrorMsg)
+	}
+	// The base build has 5 Concussion and 3 Unrelenting Storm, so only 3 builds
+	// spend the same 8 points.
+	if len(result.Builds) != 3 {
+		t.Fatalf("Expected 3 builds, got %d", len(result.Builds))
+	}
+	for i, build := range result.Builds {
+		if build.Points[0]+build.Points[1] != 8 {
+			t.Fatalf("Expected builds to spend 8 points, got %v", build.Points)
+		}
+		if i > 0 && build.Dps.Avg > result.Builds[i-1].Dps.Avg {
+			t.Fatalf("Expected builds sorted by dps")
+		}
+	}
+	log.Printf("Best build: %v, dps: %0.2f", result.Builds[0].Points, result.Builds[0].Dps.Avg)
+}
+
+func TestUnimplementedItems(t *testing.T) {
+	result := &proto.UnimplementedItemsResult{}
+	if status := postProto(t, "/unimplementedItems", &proto.UnimplementedItemsRequest{}, result); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+
+	expected := core.GetUnimplementedItems(&proto.UnimplementedItemsRequest{})
+	if !googleProto.Equal(result, expected) {
+		t.Fatalf("Expected the same unimplemented items as the sim, got %d items instead of %d", len(result.Items), len(expected.Items))
+	}
+}
+
+func TestAsyncCancel(t *testing.T) {
+	player := &proto.Player{
+		Race:      proto.Race_RaceTroll10,
+		Class:     proto.Class_ClassShaman,
+		Equipment: p1Equip,
+		Spec:      basicSpec,
+	}
+	encounter := &proto.Encounter{
+		Duration: 120,
+		Targets: []*proto.Target{
+			&proto.Target{},
+		},
+	}
+
+	// Long enough that it only finishes by being cancelled.
+	weightsReq := &proto.StatWeightsRequest{
+		Player:       player,
+		PartyBuffs:   &proto.PartyBuffs{},
+		RaidBuffs:    &proto.RaidBuffs{},
+		Debuffs:      &proto.Debuffs{},
+		Encounter:    encounter,
+		SimOptions:   &proto.SimOptions{Iterations: 10000000, RandomSeed: 1},
+		StatsToWeigh: []proto.Stat{proto.Stat_StatSpellPower, proto.Stat_StatSpellCrit},
+	}
+	weightsJob := &proto.AsyncAPIResult{}
+	postProto(t, "/statWeightsAsync", weightsReq, weightsJob)
+
+	simReq := &proto.RaidSimRequest{
+		Raid:       core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter:  encounter,
+		SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: 1},
+	}
+	simJob := &proto.AsyncAPIResult{}
+	postProto(t, "/raidSimAsync", simReq, simJob)
+
+	progress := &proto.ProgressMetrics{}
+	postProto(t, "/asyncProgress", simJob, progress)
+	if !progress.Queued {
+		t.Fatalf("Expected raid sim to be queued behind stat weights")
+	}
+
+	if status := postProto(t, "/asyncCancel", weightsJob, nil); status != http.StatusOK {
+		t.Fatalf("Expected cancel to return %d, got %d", http.StatusOK, status)
+	}
+	if status := postProto(t, "/asyncProgress", weightsJob, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected cancelled job to be removed, got status %d", status)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	for progress.FinalRaidResult == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Raid sim didn't finish after stat weights were cancelled")
+		}
+		time.Sleep(time.Millisecond * 50)
+		progress = &proto.ProgressMetrics{}
+		postProto(t, "/asyncProgress", simJob, progress)
+	}
+	if progress.FinalRaidResult.ErrorMsg != "" {
+		t.Fatalf("Unexpected error: %s", progress.FinalRaidResult.ErrorMsg)
+	}
+}
+
+func TestAsyncJobEviction(t *testing.T) {
+	jobs := newAsyncJobs(0, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	id, _ := jobs.add(cancel, nil)
+
+	jobs.evictExpired(time.Now())
+	if _, ok := jobs.get(id); !ok {
+		t.Fatalf("Job was evicted before its ttl")
+	}
+
+	jobs.evictExpired(time.Now().Add(time.Minute * 2))
+	if _, ok := jobs.get(id); ok {
+		t.Fatalf("Expected abandoned job to be evicted")
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("Expected evicted job to be cancelled")
+	}
+}
+
+func TestAsyncStream(t *testing.T) {
+	req := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(
+			&proto.Player{
+				Race:      proto.Race_RaceTroll10,
+				Class:     proto.Class_ClassShaman,
+				Equipment: p1Equip,
+				Spec:      basicSpec,
+			},
+			&proto.PartyBuffs{},
+			&proto.RaidBuffs{},
+			&proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				&proto.Target{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 1000,
+			RandomSeed: 1,
+		},
+	}
+
+	msgBytes, err := googleProto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+
+	jsonBytes, err := protojson.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+
+	// Returns every message in the stream.
+	readStream := func(r *http.Response, format string) []*proto.ProgressMetrics {
+		defer r.Body.Close()
+		if r.Header.Get("X-Progress-Id") == "" {
+			t.Fatalf("Expected a progress id header")
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "text/event-stream" {
+			t.Fatalf("Expected an event stream, got %s", contentType)
+		}
+
+		var messages []*proto.ProgressMetrics
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, 10*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, "data: ") {
+				t.Fatalf("Unexpected stream line: %s", line)
+			}
+			data := strings.TrimPrefix(line, "data: ")
+
+			progress := &proto.ProgressMetrics{}
+			if format == "json" {
+				err = protojson.Unmarshal([]byte(data), progress)
+			} else {
+				var protoBytes []byte
+				protoBytes, err = base64.StdEncoding.DecodeString(data)
+				if err == nil {
+					err = googleProto.Unmarshal(protoBytes, progress)
+				}
+			}
+			if err != nil {
+				t.Fatalf("Failed to parse stream message: %s", err.Error())
+			}
+			messages = append(messages, progress)
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("Failed to read stream: %s", err.Error())
+		}
+		return messages
+	}
+
+	type streamCase struct {
+		name   string
+		format string
+		open   func() (*http.Response, error)
+	}
+	cases := []streamCase{
+		{"POST protobuf", "protobuf", func() (*http.Response, error) {
+			return http.Post("http://localhost:3333/raidSimStream", "application/x-protobuf", bytes.NewReader(msgBytes))
+		}},
+		{"POST json", "json", func() (*http.Response, error) {
+			return http.Post("http://localhost:3333/raidSimStream?format=json", "application/x-protobuf", bytes.NewReader(msgBytes))
+		}},
+		// EventSource clients can only send GET requests.
+		{"GET protobuf", "protobuf", func() (*http.Response, error) {
+			return http.Get("http://localhost:3333/raidSimStream?request=" + url.QueryEscape(base64.StdEncoding.EncodeToString(msgBytes)))
+		}},
+		{"GET json", "json", func() (*http.Response, error) {
+			return http.Get("http://localhost:3333/raidSimStream?format=json&request=" + url.QueryEscape(string(jsonBytes)))
+		}},
+	}
+
+	for _, c := range cases {
+		r, err := c.open()
+		if err != nil {
+			t.Fatalf("%s: Failed to open stream: %s", c.name, err.Error())
+		}
+		messages := readStream(r, c.format)
+		if len(messages) < 2 {
+			t.Fatalf("%s: Expected progress updates before the final result, got %d messages", c.name, len(messages))
+		}
+		for i, progress := range messages {
+			isLast := i == len(messages)-1
+			if (progress.FinalRaidResult != nil) != isLast {
+				t.Fatalf("%s: Expected only the last message to have the final result, message %d of %d", c.name, i+1, len(messages))
+			}
+		}
+		final := messages[len(messages)-1].FinalRaidResult
+		if final.ErrorMsg != "" || final.RaidMetrics.Dps.Avg == 0 {
+			t.Fatalf("%s: Unexpected final result: %v", c.name, final)
+		}
+	}
+}